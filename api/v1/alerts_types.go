@@ -0,0 +1,55 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+type AlertSeverity string
+
+const (
+	AlertSeverityInfo    AlertSeverity = "Info"
+	AlertSeverityWarning AlertSeverity = "Warning"
+	AlertSeverityError   AlertSeverity = "Error"
+)
+
+type AlertsSpec struct {
+	// Disabled stops polling the nodes' notification centers.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// MinSeverity is the lowest severity that is counted in status and turned into events.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Info;Warning;Error
+	// +kubebuilder:default=Warning
+	MinSeverity AlertSeverity `json:"minSeverity,omitempty"`
+}
+
+type AlertsStatus struct {
+	Active int `json:"active"`
+
+	// Items lists up to 50 of the active alerts.
+	// +kubebuilder:validation:Optional
+	Items []AlertSummary `json:"items,omitempty"`
+}
+
+type AlertSummary struct {
+	ID        string `json:"id"`
+	Node      string `json:"node"`
+	Database  string `json:"database,omitempty"`
+	Severity  string `json:"severity"`
+	AlertType string `json:"alertType,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
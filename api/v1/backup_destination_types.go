@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// BackupDestination is where backups are written to (or, for a restore, read from). Exactly one
+// of s3, azure and local is set. Credentials come from a Secret in the resource's namespace.
+// +kubebuilder:validation:XValidation:rule="(has(self.s3) ? 1 : 0) + (has(self.azure) ? 1 : 0) + (has(self.local) ? 1 : 0) == 1",message="exactly one of s3, azure and local must be set"
+type BackupDestination struct {
+	// +kubebuilder:validation:Optional
+	S3 *S3BackupDestination `json:"s3,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Azure *AzureBackupDestination `json:"azure,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Local *LocalBackupDestination `json:"local,omitempty"`
+}
+
+// S3BackupDestination is an S3 bucket, or any S3-compatible store (e.g. MinIO) through
+// customServerUrl.
+type S3BackupDestination struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	BucketName string `json:"bucketName"`
+
+	// +kubebuilder:validation:Optional
+	RemoteFolderName string `json:"remoteFolderName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Region string `json:"region,omitempty"`
+
+	// CustomServerURL points at an S3-compatible server instead of AWS.
+	// +kubebuilder:validation:Optional
+	CustomServerURL string `json:"customServerUrl,omitempty"`
+
+	// ForcePathStyle addresses the bucket in the path rather than the host name, which most
+	// S3-compatible servers need.
+	// +kubebuilder:validation:Optional
+	ForcePathStyle bool `json:"forcePathStyle,omitempty"`
+
+	// CredentialsSecretRef names a Secret with accessKeyId and secretAccessKey, and optionally
+	// sessionToken.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretRef string `json:"credentialsSecretRef"`
+}
+
+// AzureBackupDestination is an Azure Blob storage container.
+type AzureBackupDestination struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	StorageContainer string `json:"storageContainer"`
+
+	// +kubebuilder:validation:Optional
+	RemoteFolderName string `json:"remoteFolderName,omitempty"`
+
+	// CredentialsSecretRef names a Secret with accountName, and accountKey or sasToken.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	CredentialsSecretRef string `json:"credentialsSecretRef"`
+}
+
+// LocalBackupDestination is a folder inside the RavenDB pods, typically on an additional volume.
+type LocalBackupDestination struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	FolderPath string `json:"folderPath"`
+}
+
+// CredentialsSecretRef is the Secret the destination reads its credentials from, if any.
+func (d *BackupDestination) CredentialsSecretRef() string {
+	switch {
+	case d.S3 != nil:
+		return d.S3.CredentialsSecretRef
+	case d.Azure != nil:
+		return d.Azure.CredentialsSecretRef
+	}
+	return ""
+}
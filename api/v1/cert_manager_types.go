@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CertManagerIssuerKind is the kind of the cert-manager issuer signing the certificates.
+type CertManagerIssuerKind string
+
+const (
+	CertManagerIssuer        CertManagerIssuerKind = "Issuer"
+	CertManagerClusterIssuer CertManagerIssuerKind = "ClusterIssuer"
+)
+
+// CertManagerSpec lets cert-manager issue the certificates of a None-mode cluster. The
+// operator requests a server certificate covering every node host and a client certificate
+// for itself, and writes them in the layout the nodes and the bootstrapper read: server.pfx
+// into clusterCertSecretRef, client.pfx into clientCertSecretRef and the issuer's ca.crt into
+// caCertSecretRef. Those Secrets are created by the operator and must not exist beforehand.
+// A renewed server certificate is handed to RavenDB through its cluster certificate
+// replacement. The cert-manager CRDs must be installed.
+type CertManagerSpec struct {
+	// IssuerRef names the Issuer (in the cluster's namespace) or ClusterIssuer to use.
+	// +kubebuilder:validation:Required
+	IssuerRef CertManagerIssuerRef `json:"issuerRef"`
+
+	// Duration is the requested certificate lifetime; the issuer's default applies when empty.
+	// +kubebuilder:validation:Optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
+
+	// RenewBefore is how long before expiry cert-manager renews; its default applies when empty.
+	// +kubebuilder:validation:Optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+type CertManagerIssuerRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default=Issuer
+	Kind CertManagerIssuerKind `json:"kind,omitempty"`
+
+	// Group is the API group of the issuer, for external issuers.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=cert-manager.io
+	Group string `json:"group,omitempty"`
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// CertificateRenewalStatus follows the Let's Encrypt certificates RavenDB renews on its own
+// and writes back into the nodes' certSecretRef secrets.
+type CertificateRenewalStatus struct {
+	// LastRenewalTime is when a node's secret last got a new certificate.
+	LastRenewalTime *metav1.Time `json:"lastRenewalTime,omitempty"`
+
+	Nodes []NodeCertificateRenewalStatus `json:"nodes,omitempty"`
+}
+
+type NodeCertificateRenewalStatus struct {
+	Tag        string `json:"tag"`
+	SecretName string `json:"secretName,omitempty"`
+
+	// SecretThumbprint is the certificate in the secret, ServedThumbprint the one the node
+	// presents on its public URL.
+	SecretThumbprint string       `json:"secretThumbprint,omitempty"`
+	ServedThumbprint string       `json:"servedThumbprint,omitempty"`
+	NotAfter         *metav1.Time `json:"notAfter,omitempty"`
+
+	LastRenewalTime *metav1.Time `json:"lastRenewalTime,omitempty"`
+
+	// Diverged is set while the node serves another certificate than its secret holds. A
+	// restart would bring back the one in the secret.
+	Diverged bool   `json:"diverged,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type CertificateRotationPhase string
+
+const (
+	// CertificateRotationPending waits for the cluster to be reachable before the replacement starts.
+	CertificateRotationPending CertificateRotationPhase = "Pending"
+	// CertificateRotationInProgress means RavenDB accepted the certificate and not every node serves it yet.
+	CertificateRotationInProgress CertificateRotationPhase = "InProgress"
+	CertificateRotationCompleted  CertificateRotationPhase = "Completed"
+	// CertificateRotationFailed is retried on every reconcile.
+	CertificateRotationFailed CertificateRotationPhase = "Failed"
+)
+
+// CertificateRotationStatus tracks the last cluster certificate handed to RavenDB. Before
+// bootstrap it just follows the spec, so the certificate the cluster was created with is
+// never rotated.
+type CertificateRotationStatus struct {
+	// SecretName and Generation are the clusterCertSecretRef and certificateRotationGeneration
+	// this rotation was started for.
+	SecretName string `json:"secretName"`
+	Generation int64  `json:"generation,omitempty"`
+	// PreviousSecretName is the secret the nodes served their certificate from before.
+	PreviousSecretName string `json:"previousSecretName,omitempty"`
+	// Thumbprint is the SHA-1 thumbprint of the certificate in SecretName, as RavenDB shows it.
+	Thumbprint string `json:"thumbprint,omitempty"`
+
+	// +kubebuilder:validation:Enum=Pending;InProgress;Completed;Failed
+	Phase       CertificateRotationPhase `json:"phase,omitempty"`
+	Message     string                   `json:"message,omitempty"`
+	StartedAt   *metav1.Time             `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time             `json:"completedAt,omitempty"`
+
+	// Nodes reports, per node, the certificate it serves on its public URL.
+	Nodes []NodeCertificateRotationStatus `json:"nodes,omitempty"`
+}
+
+type NodeCertificateRotationStatus struct {
+	Tag string `json:"tag"`
+	// Confirmed is set once the node serves the new certificate.
+	Confirmed        bool   `json:"confirmed,omitempty"`
+	ServedThumbprint string `json:"servedThumbprint,omitempty"`
+	Error            string `json:"error,omitempty"`
+}
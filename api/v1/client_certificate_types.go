@@ -0,0 +1,103 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// SecurityClearance is the RavenDB security clearance of a client certificate.
+// +kubebuilder:validation:Enum=ClusterAdmin;Operator;ValidUser
+type SecurityClearance string
+
+const (
+	ClearanceClusterAdmin SecurityClearance = "ClusterAdmin"
+	ClearanceOperator     SecurityClearance = "Operator"
+	ClearanceValidUser    SecurityClearance = "ValidUser"
+)
+
+// Privileged tells whether the clearance gives control over the cluster rather than databases.
+func (c SecurityClearance) Privileged() bool {
+	return c == ClearanceClusterAdmin || c == ClearanceOperator
+}
+
+// DatabaseAccess is what a ValidUser certificate may do on one database.
+// +kubebuilder:validation:Enum=Admin;ReadWrite;Read
+type DatabaseAccess string
+
+const (
+	DatabaseAccessAdmin     DatabaseAccess = "Admin"
+	DatabaseAccessReadWrite DatabaseAccess = "ReadWrite"
+	DatabaseAccessRead      DatabaseAccess = "Read"
+)
+
+const DefaultTrustedClientCertificateKey = "tls.crt"
+
+// TrustedClientCertificate is a client certificate, besides the operator's own, that the
+// operator registers in RavenDB. Removing it from the list revokes it.
+type TrustedClientCertificate struct {
+	// Name is the certificate name shown in RavenDB.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// SecretRef is the secret holding the PEM certificate; the private key isn't needed.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretRef string `json:"secretRef"`
+
+	// Key is the secret key holding the certificate. Defaults to tls.crt.
+	// +kubebuilder:validation:Optional
+	Key string `json:"key,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=ValidUser
+	Clearance SecurityClearance `json:"clearance,omitempty"`
+
+	// Permissions grants access per database name; only used with the ValidUser clearance.
+	// +kubebuilder:validation:Optional
+	Permissions map[string]DatabaseAccess `json:"permissions,omitempty"`
+}
+
+func (t TrustedClientCertificate) GetKey() string {
+	if t.Key == "" {
+		return DefaultTrustedClientCertificateKey
+	}
+	return t.Key
+}
+
+func (t TrustedClientCertificate) GetClearance() SecurityClearance {
+	if t.Clearance == "" {
+		return ClearanceValidUser
+	}
+	return t.Clearance
+}
+
+// ClientCertificateStatus is the operator's own client certificate as registered in RavenDB.
+// The operator keeps using SecretName until a certificate from a new clientCertSecretRef is
+// registered and answers requests; the old one is revoked after that.
+type ClientCertificateStatus struct {
+	SecretName string `json:"secretName"`
+	Thumbprint string `json:"thumbprint,omitempty"`
+	// RevokeThumbprint is the replaced certificate, until revoking it in RavenDB succeeded.
+	RevokeThumbprint string `json:"revokeThumbprint,omitempty"`
+	// Message explains why a new clientCertSecretRef isn't in use yet.
+	Message string `json:"message,omitempty"`
+}
+
+type TrustedClientCertificateStatus struct {
+	Name       string `json:"name"`
+	Thumbprint string `json:"thumbprint,omitempty"`
+	Registered bool   `json:"registered,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
@@ -64,6 +64,53 @@ type RavenDBClusterSpec struct {
 	// +kubebuilder:validation:Optional
 	CACertSecretRef *string `json:"caCertSecretRef,omitempty"`
 
+	// DeletionProtection makes the validating webhook reject deletes of this cluster
+	// and of its operator-owned StatefulSets and PVCs. Set it to false before deleting.
+	// +kubebuilder:validation:Optional
+	DeletionProtection bool `json:"deletionProtection,omitempty"`
+
+	// RollOnSecretChange rolls nodes one at a time, through the upgrade gates, when the
+	// content of their mounted certificate or license secret changes.
+	// +kubebuilder:validation:Optional
+	RollOnSecretChange bool `json:"rollOnSecretChange,omitempty"`
+
+	// Alerts controls how RavenDB notification-center alerts surface on the cluster.
+	// +kubebuilder:validation:Optional
+	Alerts *AlertsSpec `json:"alerts,omitempty"`
+
+	// Monitoring makes the operator create the Prometheus scrape configuration for the nodes.
+	// +kubebuilder:validation:Optional
+	Monitoring *MonitoringSpec `json:"monitoring,omitempty"`
+
+	// Health tunes the restart, crash and storage checks behind the Degraded condition.
+	// +kubebuilder:validation:Optional
+	Health *HealthSpec `json:"health,omitempty"`
+
+	// CertManager makes cert-manager issue the server, client and CA certificates in None mode.
+	// +kubebuilder:validation:Optional
+	CertManager *CertManagerSpec `json:"certManager,omitempty"`
+
+	// CertificateRotationGeneration rotates the cluster certificate when bumped, after a new
+	// server.pfx was written into clusterCertSecretRef. Pointing clusterCertSecretRef at another
+	// secret rotates too. The operator hands the certificate to RavenDB's cluster certificate
+	// replacement and reports progress in status.certificateRotation. None mode only.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	CertificateRotationGeneration int64 `json:"certificateRotationGeneration,omitempty"`
+
+	// TrustedClientCertificates are registered in RavenDB with their clearance and permissions.
+	// Certificates dropped from the list are revoked.
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	TrustedClientCertificates []TrustedClientCertificate `json:"trustedClientCertificates,omitempty"`
+
+	// AllowPrivilegedClientCertificates lets RavenDBClientCertificates of this cluster have the
+	// ClusterAdmin or Operator clearance. Without it those are refused, so being allowed to create
+	// a RavenDBClientCertificate doesn't give full control over the cluster.
+	// +kubebuilder:validation:Optional
+	AllowPrivilegedClientCertificates bool `json:"allowPrivilegedClientCertificates,omitempty"`
+
 	// // +kubebuilder:validation:Optional
 	// Sidecars []Sidecar `json:"sidecars,omitempty"`
 }
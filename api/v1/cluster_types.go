@@ -44,4 +44,60 @@ type RavenDBClusterStatus struct {
 	ObservedGeneration int64               `json:"observedGeneration,omitempty"`
 	Nodes              []RavenDBNodeStatus `json:"nodes,omitempty"`
 	Conditions         []metav1.Condition  `json:"conditions,omitempty"`
+
+	// Databases summarizes the database groups RavenDB reports, sorted by name.
+	// +kubebuilder:validation:Optional
+	Databases []DatabaseSummary `json:"databases,omitempty"`
+
+	// Alerts reports the active RavenDB notification-center alerts at or above spec.alerts.minSeverity.
+	// +kubebuilder:validation:Optional
+	Alerts *AlertsStatus `json:"alerts,omitempty"`
+
+	// Certificates describes the certificates found in the referenced secrets, sorted by secret and key.
+	// +kubebuilder:validation:Optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+
+	// License describes the license in licenseSecretRef.
+	// +kubebuilder:validation:Optional
+	License *LicenseStatus `json:"license,omitempty"`
+
+	// LicenseUpdate tracks the last license pushed to the running cluster.
+	// +kubebuilder:validation:Optional
+	LicenseUpdate *LicenseUpdateStatus `json:"licenseUpdate,omitempty"`
+
+	// CertificateRotation reports the progress of the last cluster certificate rotation.
+	// +kubebuilder:validation:Optional
+	CertificateRotation *CertificateRotationStatus `json:"certificateRotation,omitempty"`
+
+	// CertificateRenewal follows the Let's Encrypt renewals RavenDB does itself.
+	// +kubebuilder:validation:Optional
+	CertificateRenewal *CertificateRenewalStatus `json:"certificateRenewal,omitempty"`
+
+	// ClientCertificate is the client certificate the operator authenticates with.
+	// +kubebuilder:validation:Optional
+	ClientCertificate *ClientCertificateStatus `json:"clientCertificate,omitempty"`
+
+	// TrustedClientCertificates reports the registration of spec.trustedClientCertificates.
+	// +kubebuilder:validation:Optional
+	TrustedClientCertificates []TrustedClientCertificateStatus `json:"trustedClientCertificates,omitempty"`
+}
+
+type CertificateStatus struct {
+	SecretName string `json:"secretName"`
+	// Key is the secret key holding the certificate: server.pfx, client.pfx or ca.crt.
+	Key      string       `json:"key"`
+	Subject  string       `json:"subject,omitempty"`
+	DNSNames []string     `json:"dnsNames,omitempty"`
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Error is set when the certificate couldn't be decoded.
+	Error string `json:"error,omitempty"`
+}
+
+type DatabaseSummary struct {
+	Name              string   `json:"name"`
+	ReplicationFactor int      `json:"replicationFactor,omitempty"`
+	Members           []string `json:"members,omitempty"`
+	Rehabs            []string `json:"rehabs,omitempty"`
+	Disabled          bool     `json:"disabled,omitempty"`
+	LastError         string   `json:"lastError,omitempty"`
 }
@@ -42,15 +42,21 @@ const (
 type ClusterConditionType string
 
 const (
-	ConditionReady               ClusterConditionType = "Ready"
-	ConditionProgressing         ClusterConditionType = "Progressing"
-	ConditionDegraded            ClusterConditionType = "Degraded"
-	ConditionCertificatesReady   ClusterConditionType = "CertificatesReady"
-	ConditionLicensesValid       ClusterConditionType = "LicensesValid"
-	ConditionStorageReady        ClusterConditionType = "StorageReady"
-	ConditionExternalAccessReady ClusterConditionType = "ExternalAccessReady"
-	ConditionNodesHealthy        ClusterConditionType = "NodesHealthy"
-	ConditionBootstrapCompleted  ClusterConditionType = "BootstrapCompleted"
+	ConditionReady                 ClusterConditionType = "Ready"
+	ConditionProgressing           ClusterConditionType = "Progressing"
+	ConditionDegraded              ClusterConditionType = "Degraded"
+	ConditionCertificatesReady     ClusterConditionType = "CertificatesReady"
+	ConditionLicensesValid         ClusterConditionType = "LicensesValid"
+	ConditionStorageReady          ClusterConditionType = "StorageReady"
+	ConditionExternalAccessReady   ClusterConditionType = "ExternalAccessReady"
+	ConditionNodesHealthy          ClusterConditionType = "NodesHealthy"
+	ConditionBootstrapCompleted    ClusterConditionType = "BootstrapCompleted"
+	ConditionReconcilePaused       ClusterConditionType = "ReconcilePaused"
+	ConditionClusterFormed         ClusterConditionType = "ClusterFormed"
+	ConditionDatabasesHealthy      ClusterConditionType = "DatabasesHealthy"
+	ConditionCertificatesExpiring  ClusterConditionType = "CertificatesExpiring"
+	ConditionLicenseExpiring       ClusterConditionType = "LicenseExpiring"
+	ConditionLicenseLimitsExceeded ClusterConditionType = "LicenseLimitsExceeded"
 )
 
 type ClusterConditionReason string
@@ -67,4 +73,21 @@ const (
 	ReasonBootstrapJobRunning   ClusterConditionReason = "BootstrapJobRunning"
 	ReasonBootstrapFailed       ClusterConditionReason = "BootstrapFailed"
 	ReasonPVCNotBound           ClusterConditionReason = "PVCNotBound"
+	ReasonReconcilePaused       ClusterConditionReason = "ReconcilePaused"
+	ReasonTopologyUnavailable   ClusterConditionReason = "TopologyUnavailable"
+	ReasonNodesNotInCluster     ClusterConditionReason = "NodesNotInCluster"
+	ReasonNodesDisconnected     ClusterConditionReason = "NodesDisconnected"
+	ReasonDatabasesUnavailable  ClusterConditionReason = "DatabasesUnavailable"
+	ReasonDatabasesDegraded     ClusterConditionReason = "DatabasesDegraded"
+	ReasonRestartThreshold      ClusterConditionReason = "RestartThresholdExceeded"
+	ReasonOOMKilled             ClusterConditionReason = "OOMKilled"
+	ReasonCrashLoopBackOff      ClusterConditionReason = "CrashLoopBackOff"
+	ReasonPVCCapacity           ClusterConditionReason = "PVCCapacityBelowRequest"
+	ReasonStorageLow            ClusterConditionReason = "StorageLow"
+	ReasonCertificateExpiring   ClusterConditionReason = "CertificateExpiring"
+	ReasonCertificateExpired    ClusterConditionReason = "CertificateExpired"
+	ReasonLicenseInvalid        ClusterConditionReason = "LicenseInvalid"
+	ReasonLicenseExpiring       ClusterConditionReason = "LicenseExpiring"
+	ReasonLicenseExpired        ClusterConditionReason = "LicenseExpired"
+	ReasonLicenseLimitsExceeded ClusterConditionReason = "LicenseLimitsExceeded"
 )
@@ -251,3 +251,43 @@ func (r *RavenDBCluster) GetClientCertSecretRef() string {
 func (r *RavenDBCluster) GetCACertSecretRef() *string {
 	return r.Spec.CACertSecretRef
 }
+
+func (r *RavenDBCluster) GetTrustedClientCertNames() []string {
+	var names []string
+	for _, t := range r.Spec.TrustedClientCertificates {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// GetTrustedClientCertSources returns "secret/key" per trusted client certificate.
+func (r *RavenDBCluster) GetTrustedClientCertSources() []string {
+	var sources []string
+	for _, t := range r.Spec.TrustedClientCertificates {
+		sources = append(sources, t.SecretRef+"/"+t.GetKey())
+	}
+	return sources
+}
+
+// GetLicenseMaxClusterSize returns the cluster size RavenDB reported for the license, 0 when unknown.
+func (r *RavenDBCluster) GetLicenseMaxClusterSize() int32 {
+	if r.Status.License == nil {
+		return 0
+	}
+	return r.Status.License.MaxClusterSize
+}
+
+func (r *RavenDBCluster) GetCertManagerIssuerName() string {
+	if r.Spec.CertManager == nil {
+		return ""
+	}
+	return r.Spec.CertManager.IssuerRef.Name
+}
+
+func (r *RavenDBCluster) IsDeletionProtectionEnabled() bool {
+	return r.Spec.DeletionProtection
+}
+
+func (r *RavenDBCluster) IsBeingDeleted() bool {
+	return r.DeletionTimestamp != nil
+}
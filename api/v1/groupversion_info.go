@@ -37,4 +37,9 @@ var (
 
 func init() {
 	SchemeBuilder.Register(&RavenDBCluster{}, &RavenDBClusterList{})
+	SchemeBuilder.Register(&RavenDBClientCertificate{}, &RavenDBClientCertificateList{})
+	SchemeBuilder.Register(&RavenDBDatabase{}, &RavenDBDatabaseList{})
+	SchemeBuilder.Register(&RavenDBBackup{}, &RavenDBBackupList{})
+	SchemeBuilder.Register(&RavenDBRestore{}, &RavenDBRestoreList{})
+	SchemeBuilder.Register(&RavenDBBackupRun{}, &RavenDBBackupRunList{})
 }
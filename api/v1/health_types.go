@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaults used when spec.health (or one of its fields) is omitted
+const (
+	DefaultRestartThreshold         int32 = 5
+	DefaultRestartWindow                  = time.Hour
+	DefaultMinFreeStoragePercent    int32 = 10
+	DefaultCertExpiryWarningDays    int32 = 30
+	DefaultLicenseExpiryWarningDays int32 = 30
+)
+
+// HealthSpec tunes what makes the cluster Degraded.
+type HealthSpec struct {
+	// RestartThreshold is how many container restarts of one node within RestartWindow
+	// make the cluster Degraded. 0 turns the check off.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	RestartThreshold *int32 `json:"restartThreshold,omitempty"`
+
+	// RestartWindow is how far back restarts count, e.g. "1h". Defaults to one hour.
+	// +kubebuilder:validation:Optional
+	RestartWindow *metav1.Duration `json:"restartWindow,omitempty"`
+
+	// IgnoreOOMKilled stops containers OOM-killed within RestartWindow from degrading the cluster.
+	// +kubebuilder:validation:Optional
+	IgnoreOOMKilled bool `json:"ignoreOOMKilled,omitempty"`
+
+	// IgnoreCrashLoopBackOff stops containers in CrashLoopBackOff from degrading the cluster.
+	// +kubebuilder:validation:Optional
+	IgnoreCrashLoopBackOff bool `json:"ignoreCrashLoopBackOff,omitempty"`
+
+	// MinFreeStoragePercent is the free space RavenDB must report on a node's data volume
+	// before the cluster is Degraded. 0 turns the check off.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	MinFreeStoragePercent *int32 `json:"minFreeStoragePercent,omitempty"`
+
+	// CertificateExpiryWarningDays is how many days before a certificate expires the
+	// CertificatesExpiring condition turns True. 0 turns the warning off.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	CertificateExpiryWarningDays *int32 `json:"certificateExpiryWarningDays,omitempty"`
+
+	// LicenseExpiryWarningDays is how many days before the license expires the
+	// LicenseExpiring condition turns True. 0 turns the warning off.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	LicenseExpiryWarningDays *int32 `json:"licenseExpiryWarningDays,omitempty"`
+}
+
+func (h *HealthSpec) GetRestartThreshold() int32 {
+	if h == nil || h.RestartThreshold == nil {
+		return DefaultRestartThreshold
+	}
+	return *h.RestartThreshold
+}
+
+func (h *HealthSpec) GetRestartWindow() time.Duration {
+	if h == nil || h.RestartWindow == nil || h.RestartWindow.Duration <= 0 {
+		return DefaultRestartWindow
+	}
+	return h.RestartWindow.Duration
+}
+
+func (h *HealthSpec) GetMinFreeStoragePercent() int32 {
+	if h == nil || h.MinFreeStoragePercent == nil {
+		return DefaultMinFreeStoragePercent
+	}
+	return *h.MinFreeStoragePercent
+}
+
+func (h *HealthSpec) GetCertificateExpiryWarningDays() int32 {
+	if h == nil || h.CertificateExpiryWarningDays == nil {
+		return DefaultCertExpiryWarningDays
+	}
+	return *h.CertificateExpiryWarningDays
+}
+
+func (h *HealthSpec) GetLicenseExpiryWarningDays() int32 {
+	if h == nil || h.LicenseExpiryWarningDays == nil {
+		return DefaultLicenseExpiryWarningDays
+	}
+	return *h.LicenseExpiryWarningDays
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LicenseStatus describes the license in licenseSecretRef. Id and LicensedTo are read from
+// license.json; the attributes are signed into its keys, so type, expiration and limits are
+// what RavenDB reports once the cluster is bootstrapped.
+type LicenseStatus struct {
+	Id         string `json:"id,omitempty"`
+	LicensedTo string `json:"licensedTo,omitempty"`
+
+	// ActiveId is the license RavenDB runs with. It differs from Id while RavenDB still runs
+	// the license the secret held before.
+	ActiveId   string       `json:"activeId,omitempty"`
+	Type       string       `json:"type,omitempty"`
+	Expiration *metav1.Time `json:"expiration,omitempty"`
+
+	// MaxCores and MaxMemoryGB are what the license lets RavenDB use across the nodes. RavenDB
+	// assigns them to the nodes itself; the operator only reports them.
+	MaxCores       int32 `json:"maxCores,omitempty"`
+	MaxMemoryGB    int32 `json:"maxMemoryGB,omitempty"`
+	MaxClusterSize int32 `json:"maxClusterSize,omitempty"`
+
+	// Error is set when license.json couldn't be read, or RavenDB didn't answer.
+	Error string `json:"error,omitempty"`
+}
+
+// NodesCovered reports why the license can't run the given number of nodes, or "" when it can.
+// Only the cluster size is checked, and not before RavenDB reported it.
+func (l *LicenseStatus) NodesCovered(nodes int) string {
+	if l == nil {
+		return ""
+	}
+	if l.MaxClusterSize > 0 && int32(nodes) > l.MaxClusterSize {
+		return fmt.Sprintf("%d nodes exceed the license's cluster size of %d", nodes, l.MaxClusterSize)
+	}
+	return ""
+}
+
+type LicenseUpdatePhase string
+
+const (
+	// LicenseUpdateInProgress means the license was activated through the API and not every
+	// node reports it yet.
+	LicenseUpdateInProgress LicenseUpdatePhase = "InProgress"
+	// LicenseUpdateRestarting means the activation failed, or wasn't confirmed in time, so the
+	// nodes are restarted one at a time, through the upgrade gates, to load the license file.
+	LicenseUpdateRestarting LicenseUpdatePhase = "Restarting"
+	LicenseUpdateCompleted  LicenseUpdatePhase = "Completed"
+	// LicenseUpdateFailed means every node was restarted and some still don't run the license.
+	// Only another license secret starts a new update.
+	LicenseUpdateFailed LicenseUpdatePhase = "Failed"
+)
+
+// LicenseUpdateStatus follows a replaced license secret into the running cluster. Before
+// bootstrap it just records the license the nodes start with.
+type LicenseUpdateStatus struct {
+	// Id is the license.json id this update is for.
+	Id string `json:"id"`
+
+	// +kubebuilder:validation:Enum=InProgress;Restarting;Completed;Failed
+	Phase       LicenseUpdatePhase `json:"phase,omitempty"`
+	Message     string             `json:"message,omitempty"`
+	StartedAt   *metav1.Time       `json:"startedAt,omitempty"`
+	CompletedAt *metav1.Time       `json:"completedAt,omitempty"`
+
+	// RestartedAt is when every node was found restarted with the license file and ready.
+	RestartedAt *metav1.Time `json:"restartedAt,omitempty"`
+
+	// Nodes reports the license each node runs with.
+	Nodes []NodeLicenseStatus `json:"nodes,omitempty"`
+}
+
+// Done reports whether the update ended, with every node running the license or not.
+func (s *LicenseUpdateStatus) Done() bool {
+	return s.Phase == LicenseUpdateCompleted || s.Phase == LicenseUpdateFailed
+}
+
+type NodeLicenseStatus struct {
+	Tag      string `json:"tag"`
+	ActiveId string `json:"activeId,omitempty"`
+	// Confirmed is set once the node runs the new license.
+	Confirmed bool   `json:"confirmed,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+// MonitorKind picks the prometheus-operator resource used to scrape the nodes.
+type MonitorKind string
+
+const (
+	MonitorKindServiceMonitor MonitorKind = "ServiceMonitor"
+	MonitorKindPodMonitor     MonitorKind = "PodMonitor"
+)
+
+// MonitoringSpec opts the cluster into Prometheus scraping of RavenDB's metrics endpoint.
+// The endpoint sits on the nodes' HTTPS port and requires a client certificate, so every
+// monitor carries the TLS settings for it. The prometheus-operator CRDs must be installed.
+type MonitoringSpec struct {
+	// Kind selects a per-node ServiceMonitor (scraping a dedicated metrics Service)
+	// or a per-node PodMonitor (scraping the pod directly).
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=ServiceMonitor;PodMonitor
+	// +kubebuilder:default=ServiceMonitor
+	Kind MonitorKind `json:"kind,omitempty"`
+
+	// Interval is the scrape interval, e.g. "30s". Prometheus' default applies when empty.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	Interval string `json:"interval,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	ScrapeTimeout string `json:"scrapeTimeout,omitempty"`
+
+	// Labels are added to the monitors so a Prometheus' monitor selector picks them up.
+	// +kubebuilder:validation:Optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// ClientCertSecretRef names a kubernetes.io/tls Secret (tls.crt/tls.key) with the client
+	// certificate Prometheus presents to RavenDB. Register it with the least clearance that can
+	// read the metrics endpoint, e.g. through trustedClientCertificates. The operator's own
+	// certificate is never handed to Prometheus.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClientCertSecretRef string `json:"clientCertSecretRef"`
+
+	// InsecureSkipVerify turns off verification of the node certificates.
+	// +kubebuilder:validation:Optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
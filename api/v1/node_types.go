@@ -43,6 +43,17 @@ const (
 	NodeStatusFailed  RavenDBNodeStatusPhase = "Failed"
 )
 
+// RavenDBNodeRole is the node's place in the RavenDB cluster topology.
+type RavenDBNodeRole string
+
+const (
+	NodeRoleLeader       RavenDBNodeRole = "Leader"
+	NodeRoleMember       RavenDBNodeRole = "Member"
+	NodeRolePromotable   RavenDBNodeRole = "Promotable"
+	NodeRoleWatcher      RavenDBNodeRole = "Watcher"
+	NodeRoleNotInCluster RavenDBNodeRole = "NotInCluster"
+)
+
 type RavenDBNodeStatus struct {
 	Tag string `json:"tag"`
 
@@ -51,4 +62,27 @@ type RavenDBNodeStatus struct {
 	LastAttemptedImage string                 `json:"lastAttemptedImage,omitempty"`
 	LastError          string                 `json:"lastError,omitempty"`
 	LastAttemptTime    metav1.Time            `json:"lastAttemptTime,omitempty"`
+
+	// Role, Connected and ServerVersion come from the RavenDB cluster topology and are
+	// left empty while it can't be read.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=Leader;Member;Promotable;Watcher;NotInCluster
+	Role RavenDBNodeRole `json:"role,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Connected *bool `json:"connected,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	ServerVersion string `json:"serverVersion,omitempty"`
+
+	// Restarts is the container restart count of the node's pod when last observed. The
+	// first observation is only a baseline, so restarts from before it are never counted.
+	// +kubebuilder:validation:Optional
+	Restarts *int32 `json:"restarts,omitempty"`
+
+	// RecentRestarts holds when new restarts were observed, oldest first, limited to
+	// spec.health.restartWindow. Its length is what the restart threshold is checked against.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=50
+	RecentRestarts []metav1.Time `json:"recentRestarts,omitempty"`
 }
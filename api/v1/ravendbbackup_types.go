@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rdbbackup
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Database",type=string,JSONPath=`.spec.databaseName`
+// +kubebuilder:printcolumn:name="Schedule",type=string,JSONPath=`.spec.fullBackupFrequency`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Last Success",type=date,JSONPath=`.status.lastSuccessTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RavenDBBackup is a periodic backup task of a database. The operator keeps it as an ongoing
+// backup task in RavenDB and reports how its backups went. Deleting it removes the task, not
+// the backups.
+type RavenDBBackup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBBackupSpec   `json:"spec,omitempty"`
+	Status RavenDBBackupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBBackupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBBackup `json:"items"`
+}
+
+type RavenDBBackupSpec struct {
+	// ClusterRef names the RavenDBCluster, in the same namespace, that hosts the database.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterRef is immutable"
+	ClusterRef string `json:"clusterRef"`
+
+	// DatabaseName is the database in RavenDB.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databaseName is immutable"
+	DatabaseName string `json:"databaseName"`
+
+	// TaskName is the name of the task in RavenDB. Defaults to metadata.name.
+	// +kubebuilder:validation:Optional
+	TaskName string `json:"taskName,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Backup
+	Type BackupType `json:"type,omitempty"`
+
+	// FullBackupFrequency is a cron expression, e.g. "0 2 * * *".
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	FullBackupFrequency string `json:"fullBackupFrequency"`
+
+	// IncrementalBackupFrequency is a cron expression. Without it only full backups are taken.
+	// +kubebuilder:validation:Optional
+	IncrementalBackupFrequency string `json:"incrementalBackupFrequency,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	Retention *BackupRetention `json:"retention,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Destination BackupDestination `json:"destination"`
+
+	// Disabled keeps the task in RavenDB but stops it from running.
+	// +kubebuilder:validation:Optional
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Backup;Snapshot
+type BackupType string
+
+const (
+	BackupTypeBackup   BackupType = "Backup"
+	BackupTypeSnapshot BackupType = "Snapshot"
+)
+
+// BackupRetention has RavenDB delete backups once they are older than minimumBackupAge. The
+// newest full backup and the incrementals after it are always kept.
+type BackupRetention struct {
+	// e.g. "168h"
+	// +kubebuilder:validation:Required
+	MinimumBackupAge metav1.Duration `json:"minimumBackupAge"`
+}
+
+type BackupPhase string
+
+const (
+	BackupPending BackupPhase = "Pending"
+	BackupReady   BackupPhase = "Ready"
+	BackupFailed  BackupPhase = "Failed"
+)
+
+type RavenDBBackupStatus struct {
+	// Failed covers both the task not being configured and its last backup having failed.
+	// +kubebuilder:validation:Enum=Pending;Ready;Failed
+	Phase              BackupPhase `json:"phase,omitempty"`
+	Message            string      `json:"message,omitempty"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+
+	// TaskId is the ongoing task in RavenDB.
+	TaskId int64 `json:"taskId,omitempty"`
+	// TaskName is the name of the task the operator created, set before creating it, so
+	// a task of that name found later is known to be ours. Only that task is ever updated
+	// or removed; a task of the same name created otherwise is refused.
+	TaskName string `json:"taskName,omitempty"`
+	// ConfigurationHash covers the spec and the credentials the task was last configured with.
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	LastFullBackup        *metav1.Time `json:"lastFullBackup,omitempty"`
+	LastIncrementalBackup *metav1.Time `json:"lastIncrementalBackup,omitempty"`
+	// LastSuccessTime is the latest of lastFullBackup and lastIncrementalBackup.
+	LastSuccessTime *metav1.Time `json:"lastSuccessTime,omitempty"`
+
+	// LastError is the error of the last failed backup, as RavenDB reports it.
+	LastError     string       `json:"lastError,omitempty"`
+	LastErrorTime *metav1.Time `json:"lastErrorTime,omitempty"`
+}
+
+func (b *RavenDBBackup) GetTaskName() string {
+	if b.Spec.TaskName != "" {
+		return b.Spec.TaskName
+	}
+	return b.Name
+}
+
+func (b *RavenDBBackup) GetType() BackupType {
+	if b.Spec.Type == "" {
+		return BackupTypeBackup
+	}
+	return b.Spec.Type
+}
+
+// Failing is true when the last backup failed after the last one that succeeded.
+func (s *RavenDBBackupStatus) Failing() bool {
+	if s.LastErrorTime == nil {
+		return false
+	}
+	return s.LastSuccessTime == nil || s.LastSuccessTime.Before(s.LastErrorTime)
+}
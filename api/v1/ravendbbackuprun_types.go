@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rdbbackuprun
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Databases",type=string,JSONPath=`.spec.databases`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Completed",type=date,JSONPath=`.status.completionTime`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RavenDBBackupRun is a one-time backup of some databases, e.g. before a risky change. The
+// operator starts a backup operation per database and follows it until it's done. Runs are
+// owned by their cluster, and only the newest finished ones are kept.
+type RavenDBBackupRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBBackupRunSpec   `json:"spec,omitempty"`
+	Status RavenDBBackupRunStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBBackupRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBBackupRun `json:"items"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="a backup run can't be changed, create a new one"
+type RavenDBBackupRunSpec struct {
+	// ClusterRef names the RavenDBCluster, in the same namespace, that hosts the databases.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClusterRef string `json:"clusterRef"`
+
+	// Databases are backed up in parallel, each in its own folder of the destination.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Databases []string `json:"databases"`
+
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Backup
+	Type BackupType `json:"type,omitempty"`
+
+	// +kubebuilder:validation:Required
+	Destination BackupDestination `json:"destination"`
+
+	// HistoryLimit is how many finished runs of the cluster are kept, this one included, once it
+	// finished. Older ones are deleted.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=5
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+}
+
+type BackupRunPhase string
+
+const (
+	BackupRunPending   BackupRunPhase = "Pending"
+	BackupRunRunning   BackupRunPhase = "Running"
+	BackupRunSucceeded BackupRunPhase = "Succeeded"
+	BackupRunFailed    BackupRunPhase = "Failed"
+)
+
+type RavenDBBackupRunStatus struct {
+	// Failed once any database failed, after all of them are done.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase              BackupRunPhase `json:"phase,omitempty"`
+	Message            string         `json:"message,omitempty"`
+	ObservedGeneration int64          `json:"observedGeneration,omitempty"`
+
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// +listType=map
+	// +listMapKey=name
+	Databases []DatabaseBackupRunStatus `json:"databases,omitempty"`
+}
+
+// DatabaseBackupRunStatus follows the backup operation of one database.
+type DatabaseBackupRunStatus struct {
+	Name        string         `json:"name"`
+	OperationId int64          `json:"operationId,omitempty"`
+	NodeTag     string         `json:"nodeTag,omitempty"`
+	Phase       BackupRunPhase `json:"phase,omitempty"`
+	Message     string         `json:"message,omitempty"`
+}
+
+func (r *RavenDBBackupRun) GetType() BackupType {
+	if r.Spec.Type == "" {
+		return BackupTypeBackup
+	}
+	return r.Spec.Type
+}
+
+func (r *RavenDBBackupRun) GetHistoryLimit() int {
+	if r.Spec.HistoryLimit == nil {
+		return 5
+	}
+	return int(*r.Spec.HistoryLimit)
+}
+
+// Done is true once the run succeeded or failed; it's never started again.
+func (s *RavenDBBackupRunStatus) Done() bool {
+	return s.Phase == BackupRunSucceeded || s.Phase == BackupRunFailed
+}
+
+// Outcome is the phase of the run given its databases: Running while any of them is, then
+// Failed if any failed.
+func (s *RavenDBBackupRunStatus) Outcome() BackupRunPhase {
+	outcome := BackupRunSucceeded
+	for _, db := range s.Databases {
+		switch db.Phase {
+		case BackupRunSucceeded:
+		case BackupRunFailed:
+			outcome = BackupRunFailed
+		default:
+			return BackupRunRunning
+		}
+	}
+	return outcome
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rdbcert
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Clearance",type=string,JSONPath=`.spec.clearance`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Thumbprint",type=string,JSONPath=`.status.thumbprint`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RavenDBClientCertificate is a client certificate trusted by a RavenDBCluster. The operator
+// either has RavenDB generate it, or registers a provided one, and writes the PFX into a
+// Secret. Deleting it revokes the certificate in RavenDB.
+type RavenDBClientCertificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBClientCertificateSpec   `json:"spec,omitempty"`
+	Status RavenDBClientCertificateStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBClientCertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBClientCertificate `json:"items"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.permissions) || size(self.permissions) == 0 || self.clearance == 'ValidUser'",message="permissions only apply to the ValidUser clearance"
+type RavenDBClientCertificateSpec struct {
+	// ClusterRef names the RavenDBCluster, in the same namespace, that trusts the certificate.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterRef is immutable"
+	ClusterRef string `json:"clusterRef"`
+
+	// CertificateName is the name shown in RavenDB. Defaults to metadata.name.
+	// +kubebuilder:validation:Optional
+	CertificateName string `json:"certificateName,omitempty"`
+
+	// Clearance of the certificate. ClusterAdmin and Operator are refused unless the cluster
+	// sets spec.allowPrivilegedClientCertificates.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=ValidUser
+	Clearance SecurityClearance `json:"clearance,omitempty"`
+
+	// Permissions grants access per database name.
+	// +kubebuilder:validation:Optional
+	Permissions map[string]DatabaseAccess `json:"permissions,omitempty"`
+
+	// SecretName is the Secret the PFX is written to, as client.pfx. Defaults to metadata.name.
+	// The operator owns it.
+	// +kubebuilder:validation:Optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Provided registers an existing certificate instead of having RavenDB generate one.
+	// +kubebuilder:validation:Optional
+	Provided *ProvidedClientCertificate `json:"provided,omitempty"`
+}
+
+// ProvidedClientCertificate points at a kubernetes.io/tls style Secret. Without the private
+// key the certificate is only registered and no PFX is written.
+type ProvidedClientCertificate struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SecretRef string `json:"secretRef"`
+
+	// CertKey holds the PEM certificate. Defaults to tls.crt.
+	// +kubebuilder:validation:Optional
+	CertKey string `json:"certKey,omitempty"`
+
+	// PrivateKeyKey holds the PEM private key. Defaults to tls.key.
+	// +kubebuilder:validation:Optional
+	PrivateKeyKey string `json:"privateKeyKey,omitempty"`
+}
+
+type ClientCertificatePhase string
+
+const (
+	ClientCertificatePending ClientCertificatePhase = "Pending"
+	ClientCertificateReady   ClientCertificatePhase = "Ready"
+	ClientCertificateFailed  ClientCertificatePhase = "Failed"
+)
+
+type RavenDBClientCertificateStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Ready;Failed
+	Phase              ClientCertificatePhase `json:"phase,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+
+	// Thumbprint is the certificate registered in RavenDB, revoked when this resource is deleted.
+	Thumbprint string       `json:"thumbprint,omitempty"`
+	NotAfter   *metav1.Time `json:"notAfter,omitempty"`
+	// SecretName is set once the PFX was written.
+	SecretName string `json:"secretName,omitempty"`
+}
+
+func (c *RavenDBClientCertificate) GetCertificateName() string {
+	if c.Spec.CertificateName != "" {
+		return c.Spec.CertificateName
+	}
+	return c.Name
+}
+
+func (c *RavenDBClientCertificate) GetSecretName() string {
+	if c.Spec.SecretName != "" {
+		return c.Spec.SecretName
+	}
+	return c.Name
+}
+
+func (c *RavenDBClientCertificate) GetClearance() SecurityClearance {
+	if c.Spec.Clearance == "" {
+		return ClearanceValidUser
+	}
+	return c.Spec.Clearance
+}
+
+func (p *ProvidedClientCertificate) GetCertKey() string {
+	if p.CertKey == "" {
+		return DefaultTrustedClientCertificateKey
+	}
+	return p.CertKey
+}
+
+func (p *ProvidedClientCertificate) GetPrivateKeyKey() string {
+	if p.PrivateKeyKey == "" {
+		return "tls.key"
+	}
+	return p.PrivateKeyKey
+}
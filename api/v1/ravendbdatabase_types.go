@@ -0,0 +1,171 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rdbdb
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Database",type=string,JSONPath=`.status.databaseName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Members",type=string,JSONPath=`.status.members`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RavenDBDatabase is a database on a RavenDBCluster. The operator creates it through the admin
+// API, keeps its topology and settings in line with the spec, and deletes it (or leaves it be)
+// according to spec.deletionPolicy.
+type RavenDBDatabase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBDatabaseSpec   `json:"spec,omitempty"`
+	Status RavenDBDatabaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBDatabaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBDatabase `json:"items"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.topology) || !has(self.replicationFactor) || self.replicationFactor == size(self.topology)",message="replicationFactor must match the number of topology nodes"
+// +kubebuilder:validation:XValidation:rule="!has(self.encrypted) || !self.encrypted || (has(self.topology) && size(self.topology) > 0)",message="an encrypted database needs an explicit topology"
+// +kubebuilder:validation:XValidation:rule="has(self.databaseName) == has(oldSelf.databaseName)",message="databaseName is immutable"
+type RavenDBDatabaseSpec struct {
+	// ClusterRef names the RavenDBCluster, in the same namespace, that hosts the database.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="clusterRef is immutable"
+	ClusterRef string `json:"clusterRef"`
+
+	// DatabaseName is the name in RavenDB. Defaults to metadata.name.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="databaseName is immutable"
+	DatabaseName string `json:"databaseName,omitempty"`
+
+	// ReplicationFactor is the number of nodes holding the database. Defaults to the size of
+	// topology, or 1. Without an explicit topology RavenDB picks the nodes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	ReplicationFactor *int32 `json:"replicationFactor,omitempty"`
+
+	// Topology pins the database to these node tags. Nodes are added to and removed from the
+	// database group to match.
+	// +kubebuilder:validation:Optional
+	// +listType=set
+	Topology []string `json:"topology,omitempty"`
+
+	// Encrypted creates the database encrypted at rest. Requires an explicit topology, since the
+	// key is distributed to those nodes.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=false
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="encrypted is immutable"
+	Encrypted bool `json:"encrypted,omitempty"`
+
+	// EncryptionKeySecretRef names the Secret holding the base64 encryption key under "key".
+	// When it doesn't exist the operator generates a key and writes it there. Defaults to
+	// <metadata.name>-encryption-key. The Secret is never deleted by the operator: without it a
+	// retained database can't be loaded on a new node.
+	// +kubebuilder:validation:Optional
+	EncryptionKeySecretRef string `json:"encryptionKeySecretRef,omitempty"`
+
+	// Settings is the database configuration (e.g. Indexing.MapBatchSize). When set it replaces
+	// the settings of the database record, and the database is reloaded when they change. Unset
+	// leaves the record's settings alone.
+	// +kubebuilder:validation:Optional
+	Settings map[string]string `json:"settings,omitempty"`
+
+	// DeletionPolicy decides what happens to the database when this resource is deleted.
+	// Retain leaves it in RavenDB, Delete removes it from every node along with its data.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=Retain
+	DeletionPolicy DatabaseDeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// AdoptExisting lets this resource take over a database of that name that already exists
+	// in RavenDB. It's then managed like one the operator created, deletionPolicy included.
+	// Without it such a database is left alone and the resource fails.
+	// +kubebuilder:validation:Optional
+	AdoptExisting bool `json:"adoptExisting,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=Retain;Delete
+type DatabaseDeletionPolicy string
+
+const (
+	DatabaseDeletionRetain DatabaseDeletionPolicy = "Retain"
+	DatabaseDeletionDelete DatabaseDeletionPolicy = "Delete"
+)
+
+type DatabasePhase string
+
+const (
+	DatabasePending DatabasePhase = "Pending"
+	DatabaseReady   DatabasePhase = "Ready"
+	DatabaseFailed  DatabasePhase = "Failed"
+)
+
+type RavenDBDatabaseStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Ready;Failed
+	Phase              DatabasePhase `json:"phase,omitempty"`
+	Message            string        `json:"message,omitempty"`
+	ObservedGeneration int64         `json:"observedGeneration,omitempty"`
+
+	// DatabaseName is set right before the operator creates the database, or when it adopts
+	// an existing one. The database is only managed, and deleted, once it's set.
+	DatabaseName string `json:"databaseName,omitempty"`
+
+	// the database group as RavenDB reports it
+	ReplicationFactor int32    `json:"replicationFactor,omitempty"`
+	Members           []string `json:"members,omitempty"`
+	Promotables       []string `json:"promotables,omitempty"`
+	Rehabs            []string `json:"rehabs,omitempty"`
+}
+
+func (d *RavenDBDatabase) GetDatabaseName() string {
+	if d.Spec.DatabaseName != "" {
+		return d.Spec.DatabaseName
+	}
+	return d.Name
+}
+
+func (d *RavenDBDatabase) GetReplicationFactor() int {
+	if d.Spec.ReplicationFactor != nil {
+		return int(*d.Spec.ReplicationFactor)
+	}
+	if len(d.Spec.Topology) > 0 {
+		return len(d.Spec.Topology)
+	}
+	return 1
+}
+
+func (d *RavenDBDatabase) GetEncryptionKeySecretRef() string {
+	if d.Spec.EncryptionKeySecretRef != "" {
+		return d.Spec.EncryptionKeySecretRef
+	}
+	return d.Name + "-encryption-key"
+}
+
+func (d *RavenDBDatabase) GetDeletionPolicy() DatabaseDeletionPolicy {
+	if d.Spec.DeletionPolicy == "" {
+		return DatabaseDeletionRetain
+	}
+	return d.Spec.DeletionPolicy
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:shortName=rdbrestore
+// +kubebuilder:printcolumn:name="Cluster",type=string,JSONPath=`.spec.clusterRef`
+// +kubebuilder:printcolumn:name="Database",type=string,JSONPath=`.spec.databaseName`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Progress",type=string,JSONPath=`.status.progress`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RavenDBRestore restores a database from a backup, once. The operator starts RavenDB's restore
+// operation and follows it until it completed or failed.
+type RavenDBRestore struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RavenDBRestoreSpec   `json:"spec,omitempty"`
+	Status RavenDBRestoreStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type RavenDBRestoreList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RavenDBRestore `json:"items"`
+}
+
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="a restore can't be changed, create a new one"
+type RavenDBRestoreSpec struct {
+	// ClusterRef names the RavenDBCluster, in the same namespace, to restore into.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ClusterRef string `json:"clusterRef"`
+
+	// DatabaseName is the database the backup is restored as.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	DatabaseName string `json:"databaseName"`
+
+	// Source is the folder holding the backup files: remoteFolderName in a bucket or container,
+	// or folderPath inside the RavenDB pods.
+	// +kubebuilder:validation:Required
+	Source BackupDestination `json:"source"`
+
+	// LastFileNameToRestore stops at this backup file, to restore an earlier point in time.
+	// Defaults to the latest one.
+	// +kubebuilder:validation:Optional
+	LastFileNameToRestore string `json:"lastFileNameToRestore,omitempty"`
+
+	// EncryptionKeySecretRef names the Secret holding, under "key", the key of an encrypted
+	// backup. The restored database is encrypted with it as well.
+	// +kubebuilder:validation:Optional
+	EncryptionKeySecretRef string `json:"encryptionKeySecretRef,omitempty"`
+
+	// AllowOverwrite deletes an existing database of that name, with its data, before restoring.
+	// Without it the restore fails when the database exists.
+	// +kubebuilder:validation:Optional
+	AllowOverwrite bool `json:"allowOverwrite,omitempty"`
+
+	// DisableOngoingTasks restores the database with its ongoing tasks (backups, ETLs,
+	// subscriptions...) disabled.
+	// +kubebuilder:validation:Optional
+	DisableOngoingTasks bool `json:"disableOngoingTasks,omitempty"`
+
+	// SkipIndexes restores the documents only.
+	// +kubebuilder:validation:Optional
+	SkipIndexes bool `json:"skipIndexes,omitempty"`
+}
+
+type RestorePhase string
+
+const (
+	RestorePending   RestorePhase = "Pending"
+	RestoreRunning   RestorePhase = "Running"
+	RestoreSucceeded RestorePhase = "Succeeded"
+	RestoreFailed    RestorePhase = "Failed"
+)
+
+type RavenDBRestoreStatus struct {
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+	Phase              RestorePhase `json:"phase,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	ObservedGeneration int64        `json:"observedGeneration,omitempty"`
+
+	// OperationId is the restore operation, running on node NodeTag.
+	OperationId int64  `json:"operationId,omitempty"`
+	NodeTag     string `json:"nodeTag,omitempty"`
+
+	// Progress is the last message of the operation.
+	Progress string `json:"progress,omitempty"`
+	// StartTime is saved right before the restore operation is started.
+	StartTime      *metav1.Time `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// Done is true once the restore succeeded or failed; it's never started again.
+func (s *RavenDBRestoreStatus) Done() bool {
+	return s.Phase == RestoreSucceeded || s.Phase == RestoreFailed
+}
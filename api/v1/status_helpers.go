@@ -40,6 +40,51 @@ func (r *RavenDBCluster) SetBootstrapped(now metav1.Time) {
 	r.SetConditionTrue(ConditionBootstrapCompleted, ReasonCompleted, "Bootstrap job succeeded", now)
 }
 
+// CertificateRotationRequested reports whether clusterCertSecretRef or certificateRotationGeneration
+// moved past the last rotation in status, or whether that rotation still has to be (re)started.
+func (r *RavenDBCluster) CertificateRotationRequested() bool {
+	rot := r.Status.CertificateRotation
+	if rot == nil || r.Spec.ClusterCertSecretRef == nil {
+		return false
+	}
+	if rot.Phase == CertificateRotationPending || rot.Phase == CertificateRotationFailed {
+		return true
+	}
+	return rot.SecretName != *r.Spec.ClusterCertSecretRef || rot.Generation != r.Spec.CertificateRotationGeneration
+}
+
+// LicenseRestartId is the license the nodes are being restarted to load, after RavenDB didn't
+// take it through the API. Empty when no such restart is going on.
+func (r *RavenDBCluster) LicenseRestartId() string {
+	if u := r.Status.LicenseUpdate; u != nil && u.Phase == LicenseUpdateRestarting {
+		return u.Id
+	}
+	return ""
+}
+
+// DivergedNodes lists the nodes serving another certificate than their secret holds.
+func (s *CertificateRenewalStatus) DivergedNodes() []string {
+	if s == nil {
+		return nil
+	}
+	var tags []string
+	for _, n := range s.Nodes {
+		if n.Diverged {
+			tags = append(tags, n.Tag)
+		}
+	}
+	return tags
+}
+
+// ActiveClientCertSecretName is the client certificate secret the operator talks to RavenDB
+// with: the one registered in RavenDB, which lags behind clientCertSecretRef during a rotation.
+func (r *RavenDBCluster) ActiveClientCertSecretName() string {
+	if cc := r.Status.ClientCertificate; cc != nil && cc.SecretName != "" {
+		return cc.SecretName
+	}
+	return r.Spec.ClientCertSecretRef
+}
+
 func (r *RavenDBCluster) GetCondition(t ClusterConditionType) (c *metav1.Condition, ok bool) {
 	for i := range r.Status.Conditions {
 		condition := &r.Status.Conditions[i]
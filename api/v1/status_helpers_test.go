@@ -254,3 +254,112 @@ func Test_TL13_DegradedTrue_and_AllRequiredTrue(t *testing.T) {
 	require.Equal(t, metav1.ConditionTrue, ready.Status)
 	require.Equal(t, PhaseRunning, c.Status.Phase)
 }
+
+func Test_TL14_CertificateRotationRequested(t *testing.T) {
+	secret := "ravendb-certs"
+	c := newCluster(false)
+	c.Spec.ClusterCertSecretRef = &secret
+	require.False(t, c.CertificateRotationRequested(), "no baseline recorded yet")
+
+	c.Status.CertificateRotation = &CertificateRotationStatus{SecretName: secret, Phase: CertificateRotationCompleted}
+	require.False(t, c.CertificateRotationRequested())
+
+	c.Spec.CertificateRotationGeneration = 1
+	require.True(t, c.CertificateRotationRequested(), "bumped generation")
+
+	c.Status.CertificateRotation.Generation = 1
+	other := "ravendb-certs-2"
+	c.Spec.ClusterCertSecretRef = &other
+	require.True(t, c.CertificateRotationRequested(), "changed secret")
+
+	c.Status.CertificateRotation.SecretName = other
+	c.Status.CertificateRotation.Phase = CertificateRotationFailed
+	require.True(t, c.CertificateRotationRequested(), "failed rotations are retried")
+}
+
+func Test_TL15_CertificateRenewalDivergedNodes(t *testing.T) {
+	var st *CertificateRenewalStatus
+	require.Empty(t, st.DivergedNodes())
+
+	st = &CertificateRenewalStatus{Nodes: []NodeCertificateRenewalStatus{
+		{Tag: "A", SecretThumbprint: "AA", ServedThumbprint: "AA"},
+		{Tag: "B", SecretThumbprint: "BB", ServedThumbprint: "B0", Diverged: true},
+		{Tag: "C", Error: "no certSecretRef"},
+	}}
+	require.Equal(t, []string{"B"}, st.DivergedNodes())
+}
+
+func Test_TL16_LicenseRestartId(t *testing.T) {
+	c := newCluster(false)
+	require.Empty(t, c.LicenseRestartId())
+
+	c.Status.LicenseUpdate = &LicenseUpdateStatus{Id: "new", Phase: LicenseUpdateInProgress}
+	require.Empty(t, c.LicenseRestartId(), "activated through the API, no restart")
+
+	c.Status.LicenseUpdate.Phase = LicenseUpdateRestarting
+	require.Equal(t, "new", c.LicenseRestartId())
+
+	c.Status.LicenseUpdate.Phase = LicenseUpdateCompleted
+	require.Empty(t, c.LicenseRestartId())
+}
+
+func Test_TL17_BackupFailing(t *testing.T) {
+	at := func(h int) *metav1.Time {
+		mt := metav1.NewTime(time.Date(2025, 1, 1, h, 0, 0, 0, time.UTC))
+		return &mt
+	}
+
+	st := &RavenDBBackupStatus{}
+	require.False(t, st.Failing())
+
+	st.LastErrorTime = at(2)
+	require.True(t, st.Failing(), "never succeeded")
+
+	st.LastSuccessTime = at(3)
+	require.False(t, st.Failing(), "succeeded after the error")
+
+	st.LastErrorTime = at(4)
+	require.True(t, st.Failing())
+}
+
+func Test_TL18_BackupDestinationCredentials(t *testing.T) {
+	d := BackupDestination{S3: &S3BackupDestination{BucketName: "b", CredentialsSecretRef: "minio"}}
+	require.Equal(t, "minio", d.CredentialsSecretRef())
+
+	d = BackupDestination{Azure: &AzureBackupDestination{StorageContainer: "c", CredentialsSecretRef: "azure"}}
+	require.Equal(t, "azure", d.CredentialsSecretRef())
+
+	d = BackupDestination{Local: &LocalBackupDestination{FolderPath: "/backups"}}
+	require.Empty(t, d.CredentialsSecretRef())
+}
+
+func Test_TL19_RestoreDone(t *testing.T) {
+	st := &RavenDBRestoreStatus{}
+	require.False(t, st.Done())
+
+	for phase, done := range map[RestorePhase]bool{
+		RestorePending:   false,
+		RestoreRunning:   false,
+		RestoreSucceeded: true,
+		RestoreFailed:    true,
+	} {
+		st.Phase = phase
+		require.Equal(t, done, st.Done(), phase)
+	}
+}
+
+func Test_TL20_BackupRunOutcome(t *testing.T) {
+	st := &RavenDBBackupRunStatus{Databases: []DatabaseBackupRunStatus{
+		{Name: "Orders", Phase: BackupRunSucceeded},
+		{Name: "Reports", Phase: BackupRunRunning},
+		{Name: "Missing", Phase: BackupRunFailed},
+	}}
+	require.Equal(t, BackupRunRunning, st.Outcome(), "still running")
+
+	st.Databases[1].Phase = BackupRunSucceeded
+	require.Equal(t, BackupRunFailed, st.Outcome())
+
+	st.Databases = st.Databases[:2]
+	require.Equal(t, BackupRunSucceeded, st.Outcome())
+	require.False(t, st.Done(), "the run's phase is only set once the outcome is")
+}
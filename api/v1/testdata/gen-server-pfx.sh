@@ -0,0 +1,19 @@
+#!/usr/bin/env bash
+# Regenerates server.pfx, the self-signed server certificate the webhook tests decode.
+# It covers the node hosts of baseCluster/baseClusterLetsEncrypt, carries both the
+# server- and client-auth EKUs and is valid for 100 years. The PFX uses SHA1/3DES so
+# golang.org/x/crypto/pkcs12 can read it, and an empty password like the real secrets.
+set -euo pipefail
+cd "$(dirname "$0")"
+
+tmp=$(mktemp -d)
+trap 'rm -rf "$tmp"' EXIT
+
+openssl req -x509 -newkey ec -pkeyopt ec_paramgen_curve:P-256 -nodes \
+  -keyout "$tmp/key.pem" -out "$tmp/cert.pem" -days 36500 \
+  -subj "/CN=a.example.com" \
+  -addext "subjectAltName=DNS:a.example.com,DNS:a-tcp.example.com,DNS:b.example.com,DNS:b-tcp.example.com" \
+  -addext "extendedKeyUsage=serverAuth,clientAuth"
+
+openssl pkcs12 -export -inkey "$tmp/key.pem" -in "$tmp/cert.pem" -out server.pfx \
+  -passout pass: -certpbe PBE-SHA1-3DES -keypbe PBE-SHA1-3DES -macalg sha1
@@ -38,11 +38,12 @@ func (r *RavenDBCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	validator.Register(validator.NewNodeValidator(mgr.GetClient()))
 	validator.Register(validator.NewEaValidator(mgr.GetClient()))
 	validator.Register(validator.NewStorageValidator(mgr.GetClient()))
+	validator.Register(validator.NewDeletionValidator())
 
 	return ctrl.NewWebhookManagedBy(mgr).For(r).Complete()
 }
 
-// +kubebuilder:webhook:path=/validate-ravendb-ravendb-io-v1-ravendbcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=ravendb.ravendb.io,resources=ravendbclusters,verbs=create;update,versions=v1,name=vravendbcluster.kb.io,admissionReviewVersions=v1
+// +kubebuilder:webhook:path=/validate-ravendb-ravendb-io-v1-ravendbcluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=ravendb.ravendb.io,resources=ravendbclusters,verbs=create;update;delete,versions=v1,name=vravendbcluster.kb.io,admissionReviewVersions=v1
 
 var _ sigswebhook.Validator = &RavenDBCluster{}
 
@@ -62,7 +63,7 @@ func (r *RavenDBCluster) ValidateUpdate(old runtime.Object) (admission.Warnings,
 
 func (r *RavenDBCluster) ValidateDelete() (admission.Warnings, error) {
 	ravendbclusterlog.Info("validate delete", "name", r.Name)
-	return nil, nil
+	return nil, webhook.ValidateDelete(context.TODO(), r)
 }
 
 // +kubebuilder:webhook:path=/mutate-ravendb-ravendb-io-v1-ravendbcluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=ravendb.ravendb.io,resources=ravendbclusters,verbs=create;update,versions=v1,name=mravendbcluster.kb.io,admissionReviewVersions=v1
@@ -18,15 +18,33 @@ package v1_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
 	"net/url"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	v1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/webhook/protection"
 	"ravendb-operator/pkg/webhook/validator"
 
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -254,6 +272,15 @@ func TestGeneralValidatorValidateLicenseSecret(t *testing.T) {
 					"two.json": []byte("{}"),
 				},
 			},
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "broken-license",
+					Namespace: "ravendb",
+				},
+				Data: map[string][]byte{
+					"license.json": []byte(`{"Id": "8f1c`),
+				},
+			},
 		).Build()
 
 	v := validator.NewGeneralValidator(client)
@@ -278,6 +305,12 @@ func TestGeneralValidatorValidateLicenseSecret(t *testing.T) {
 		require.Contains(t, errs[0], "spec.licenseSecretRef: secret 'non-json-key-license' must contain a file ending with '.json'")
 	})
 
+	t.Run("license secret with invalid json", func(t *testing.T) {
+		errs := validator.ValidateLicenseSecret(v, ctx, "broken-license")
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "'license.json' in secret 'broken-license' is not valid JSON")
+	})
+
 	t.Run("license secret with multiple keys", func(t *testing.T) {
 		cluster := baseClusterLetsEncrypt("invalid-license-multi-keys")
 		cluster.Spec.LicenseSecretRef = "invalid-license-multi-keys"
@@ -773,6 +806,270 @@ func TestValidateNodeCertSecret(t *testing.T) {
 	})
 }
 
+func serverPFXFixture(t *testing.T) []byte {
+	t.Helper()
+	pfx, err := os.ReadFile("testdata/server.pfx")
+	require.NoError(t, err)
+	return pfx
+}
+
+func certSecret(name, key string, data []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ravendb"},
+		Data:       map[string][]byte{key: data},
+	}
+}
+
+func TestValidateClusterCertificate(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().
+		WithObjects(
+			certSecret("real-cluster-cert", "cluster.pfx", serverPFXFixture(t)),
+			certSecret("garbage-cluster-cert", "cluster.pfx", []byte("fake")),
+		).Build()
+
+	v := validator.NewGeneralValidator(client)
+
+	t.Run("accept cert covering every node host", func(t *testing.T) {
+		hosts := []string{"a.example.com", "b.example.com", "a-tcp.example.com", "b-tcp.example.com"}
+		errs := validator.ValidateClusterCertificate(v, ctx, "real-cluster-cert", hosts)
+		require.Empty(t, errs)
+	})
+
+	t.Run("reject cert not covering a node host", func(t *testing.T) {
+		errs := validator.ValidateClusterCertificate(v, ctx, "real-cluster-cert", []string{"a.example.com", "c-tcp.example.com"})
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.clusterCertSecretRef: certificate SANs")
+		require.Contains(t, errs[0], "do not cover host 'c-tcp.example.com'")
+	})
+
+	t.Run("reject undecodable pfx", func(t *testing.T) {
+		errs := validator.ValidateClusterCertificate(v, ctx, "garbage-cluster-cert", []string{"a.example.com"})
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.clusterCertSecretRef: secret 'garbage-cluster-cert' file 'cluster.pfx': decode pfx")
+	})
+
+	t.Run("webhook rejects cluster cert missing the tcp host", func(t *testing.T) {
+		cluster := baseCluster("missing-tcp-host")
+		cluster.Spec.Nodes[0].PublicServerUrlTcp = "tcp://c-tcp.example.com"
+		cert := "real-cluster-cert"
+		cluster.Spec.ClusterCertSecretRef = &cert
+		err := v.ValidateCreate(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.clusterCertSecretRef: certificate SANs")
+		require.Contains(t, err.Error(), "'c-tcp.example.com'")
+	})
+}
+
+func TestValidateNodeCertificate(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().
+		WithObjects(
+			certSecret("real-node-cert", "node.pfx", serverPFXFixture(t)),
+			certSecret("garbage-node-cert", "node.pfx", []byte("fake")),
+		).Build()
+
+	v := validator.NewNodeValidator(client)
+
+	t.Run("accept cert covering the node hosts", func(t *testing.T) {
+		errs := validator.ValidateNodeCertificate(ctx, v, "A", "real-node-cert", []string{"a.example.com", "a-tcp.example.com"})
+		require.Empty(t, errs)
+	})
+
+	t.Run("reject cert not covering the node hosts", func(t *testing.T) {
+		errs := validator.ValidateNodeCertificate(ctx, v, "C", "real-node-cert", []string{"c.example.com", "c-tcp.example.com"})
+		require.Len(t, errs, 2)
+		require.Contains(t, errs[0], "spec.nodes[tag=C].certsSecretRef: certificate SANs")
+		require.Contains(t, errs[1], "do not cover host 'c-tcp.example.com'")
+	})
+
+	t.Run("reject undecodable pfx", func(t *testing.T) {
+		errs := validator.ValidateNodeCertificate(ctx, v, "A", "garbage-node-cert", []string{"a.example.com"})
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.nodes[tag=A].certsSecretRef: file 'node.pfx': decode pfx")
+	})
+
+	t.Run("update only decodes changed certs", func(t *testing.T) {
+		oldC := baseClusterLetsEncrypt("update")
+		oldC.Spec.Nodes = oldC.Spec.Nodes[:1]
+		oldC.Spec.Nodes[0].Tag = "a"
+		garbage := "garbage-node-cert"
+		oldC.Spec.Nodes[0].CertSecretRef = &garbage
+
+		newC := oldC.DeepCopy()
+		newC.Spec.Image = "ravendb/ravendb:7.1.3-ubuntu.22.04-x64"
+		require.NoError(t, v.ValidateUpdate(ctx, oldC, newC))
+
+		err := v.ValidateCreate(ctx, newC)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.nodes[tag=a].certsSecretRef: file 'node.pfx': decode pfx")
+	})
+}
+
+func TestValidateServerCertificate(t *testing.T) {
+	now := time.Now()
+	newCert := func(dnsNames []string, notBefore, notAfter time.Time, eku ...x509.ExtKeyUsage) *x509.Certificate {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(1),
+			Subject:      pkix.Name{CommonName: "server"},
+			DNSNames:     dnsNames,
+			NotBefore:    notBefore,
+			NotAfter:     notAfter,
+			ExtKeyUsage:  eku,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		require.NoError(t, err)
+		cert, err := x509.ParseCertificate(der)
+		require.NoError(t, err)
+		return cert
+	}
+	both := []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}
+	hosts := []string{"a.example.com", "a-tcp.example.com"}
+
+	t.Run("accept wildcard SAN", func(t *testing.T) {
+		cert := newCert([]string{"*.example.com"}, now.Add(-time.Hour), now.Add(time.Hour), both...)
+		require.Empty(t, validator.ValidateServerCertificate("spec.x", cert, hosts, now))
+	})
+
+	t.Run("accept cert without EKU restrictions", func(t *testing.T) {
+		cert := newCert(hosts, now.Add(-time.Hour), now.Add(time.Hour))
+		require.Empty(t, validator.ValidateServerCertificate("spec.x", cert, hosts, now))
+	})
+
+	t.Run("reject expired cert", func(t *testing.T) {
+		cert := newCert(hosts, now.Add(-2*time.Hour), now.Add(-time.Hour), both...)
+		errs := validator.ValidateServerCertificate("spec.x", cert, hosts, now)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.x: certificate expired on")
+	})
+
+	t.Run("reject not yet valid cert", func(t *testing.T) {
+		cert := newCert(hosts, now.Add(time.Hour), now.Add(2*time.Hour), both...)
+		errs := validator.ValidateServerCertificate("spec.x", cert, hosts, now)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.x: certificate is not valid before")
+	})
+
+	t.Run("reject cert without client auth", func(t *testing.T) {
+		cert := newCert(hosts, now.Add(-time.Hour), now.Add(time.Hour), x509.ExtKeyUsageServerAuth)
+		errs := validator.ValidateServerCertificate("spec.x", cert, hosts, now)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.x: certificate must allow client authentication")
+	})
+}
+
+func TestValidateCertManager(t *testing.T) {
+	ca := "ca"
+
+	t.Run("reject cert-manager outside None mode", func(t *testing.T) {
+		errs := validator.ValidateCertManager("LetsEncrypt", "", "client-cert", nil)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "spec.certManager is only supported when mode is None")
+	})
+
+	t.Run("require the secret names to write into", func(t *testing.T) {
+		errs := validator.ValidateCertManager("None", "", "client-cert", nil)
+		require.Len(t, errs, 2)
+		require.Contains(t, errs[0], "spec.clusterCertSecretRef is required when spec.certManager is set")
+		require.Contains(t, errs[1], "spec.caCertSecretRef is required when spec.certManager is set")
+	})
+
+	t.Run("reject shared secret names", func(t *testing.T) {
+		errs := validator.ValidateCertManager("None", "ca", "client-cert", &ca)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "must name different secrets")
+	})
+
+	t.Run("skip secret reads when cert-manager writes them", func(t *testing.T) {
+		client := fake.NewClientBuilder().
+			WithObjects(certSecret("license", "license.json", []byte("{}"))).
+			Build()
+		v := validator.NewGeneralValidator(client)
+
+		cluster := baseCluster("cert-manager")
+		cluster.Namespace = "ravendb"
+		cluster.Spec.Email = nil
+		cluster.Spec.CertManager = &v1.CertManagerSpec{IssuerRef: v1.CertManagerIssuerRef{Name: "ravendb-ca"}}
+		require.NoError(t, v.ValidateCreate(context.Background(), cluster))
+	})
+}
+
+func TestValidateTrustedClientCertificates(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "app"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	client := fake.NewClientBuilder().
+		WithObjects(
+			certSecret("app-cert", "tls.crt", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			certSecret("garbage-cert", "tls.crt", []byte("fake")),
+		).Build()
+	v := validator.NewGeneralValidator(client)
+
+	t.Run("accept PEM certificate", func(t *testing.T) {
+		errs := validator.ValidateTrustedClientCertificates(v, ctx, []string{"app"}, []string{"app-cert/tls.crt"}, nil)
+		require.Empty(t, errs)
+	})
+
+	t.Run("reject duplicate names", func(t *testing.T) {
+		errs := validator.ValidateTrustedClientCertificates(v, ctx, []string{"app", "app"}, []string{"app-cert/tls.crt", "app-cert/tls.crt"}, nil)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "name 'app' is used more than once")
+	})
+
+	t.Run("reject missing key and undecodable certificate", func(t *testing.T) {
+		errs := validator.ValidateTrustedClientCertificates(v, ctx, []string{"a", "b"}, []string{"app-cert/ca.crt", "garbage-cert/tls.crt"}, nil)
+		require.Len(t, errs, 2)
+		require.Contains(t, errs[0], "spec.trustedClientCertificates[name=a]: secret 'app-cert' has no key 'ca.crt'")
+		require.Contains(t, errs[1], "spec.trustedClientCertificates[name=b]: secret 'garbage-cert' key 'tls.crt': no PEM certificate found")
+	})
+
+	t.Run("update only reads new sources", func(t *testing.T) {
+		errs := validator.ValidateTrustedClientCertificates(v, ctx, []string{"b"}, []string{"garbage-cert/tls.crt"}, []string{"garbage-cert/tls.crt"})
+		require.Empty(t, errs)
+	})
+}
+
+func TestConvertedPFXPassesValidation(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "a.example.com"},
+		DNSNames:     []string{"a.example.com", "a-tcp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	// what the cert-manager actor writes from an issued kubernetes.io/tls Secret
+	pfx, err := certutil.PEMToPFX(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		"",
+	)
+	require.NoError(t, err)
+
+	cert, err := certutil.ParsePFXCertificate(pfx, "")
+	require.NoError(t, err)
+	require.Empty(t, validator.ValidateServerCertificate("spec.x", cert, []string{"a.example.com", "a-tcp.example.com"}, time.Now()))
+}
+
 func TestEAValidator(t *testing.T) {
 	ctx := context.Background()
 	client := fake.NewClientBuilder().Build()
@@ -1083,6 +1380,139 @@ func TestValidateAdditionalVolumes(t *testing.T) {
 	})
 }
 
+func TestDeletionValidator(t *testing.T) {
+	ctx := context.Background()
+	v := validator.NewDeletionValidator()
+
+	t.Run("allows delete when protection is off", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("unprotected")
+		require.NoError(t, v.ValidateDelete(ctx, cluster))
+	})
+
+	t.Run("rejects delete when protection is on", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("protected")
+		cluster.Spec.DeletionProtection = true
+		err := v.ValidateDelete(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "spec.deletionProtection is enabled")
+	})
+
+	t.Run("RunDelete only consults delete validators", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("protected-run")
+		cluster.Spec.DeletionProtection = true
+		validator.Register(v)
+		err := validator.RunDelete(ctx, cluster)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "[deletion-validator]")
+	})
+}
+
+func TestProtectionWebhook(t *testing.T) {
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, v1.AddToScheme(scheme))
+	decoder := admission.NewDecoder(scheme)
+
+	newHandler := func(objs ...client.Object) *protection.Handler {
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		return protection.NewHandler(c, decoder)
+	}
+
+	deleteRequest := func(kind string, obj client.Object) admission.Request {
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Delete,
+			Kind:      metav1.GroupVersionKind{Kind: kind},
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+			OldObject: runtime.RawExtension{Raw: raw},
+		}}
+	}
+
+	statefulSet := func(cluster *v1.RavenDBCluster, tag string) *appsv1.StatefulSet {
+		return &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+			Name:      "ravendb-" + tag,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "ravendb-operator",
+				"app.kubernetes.io/instance":   cluster.Name,
+				"nodeTag":                      tag,
+			},
+		}}
+	}
+
+	pvc := func(cluster *v1.RavenDBCluster, name, tag string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    map[string]string{"nodeTag": tag},
+		}}
+	}
+
+	t.Run("denies statefulset delete when cluster is protected", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("prot-sts")
+		cluster.Spec.DeletionProtection = true
+		resp := newHandler(cluster).Handle(ctx, deleteRequest("StatefulSet", statefulSet(cluster, "A")))
+		require.False(t, resp.Allowed)
+		require.Contains(t, resp.Result.Message, "spec.deletionProtection is enabled")
+	})
+
+	t.Run("allows statefulset delete when cluster is not protected", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("unprot-sts")
+		resp := newHandler(cluster).Handle(ctx, deleteRequest("StatefulSet", statefulSet(cluster, "A")))
+		require.True(t, resp.Allowed)
+	})
+
+	t.Run("allows delete of orphaned statefulset", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("prot-orphan")
+		cluster.Spec.DeletionProtection = true
+		resp := newHandler(cluster).Handle(ctx, deleteRequest("StatefulSet", statefulSet(cluster, "Z")))
+		require.True(t, resp.Allowed)
+	})
+
+	t.Run("allows statefulset delete when cluster is gone", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("gone-sts")
+		cluster.Spec.DeletionProtection = true
+		resp := newHandler().Handle(ctx, deleteRequest("StatefulSet", statefulSet(cluster, "A")))
+		require.True(t, resp.Allowed)
+	})
+
+	t.Run("denies pvc delete when cluster is protected", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("prot-pvc")
+		cluster.Spec.DeletionProtection = true
+		resp := newHandler(cluster).Handle(ctx, deleteRequest("PersistentVolumeClaim", pvc(cluster, "ravendb-data-ravendb-B-0", "B")))
+		require.False(t, resp.Allowed)
+		require.Contains(t, resp.Result.Message, "prot-pvc")
+	})
+
+	t.Run("allows unrelated pvc delete", func(t *testing.T) {
+		cluster := baseClusterLetsEncrypt("prot-other-pvc")
+		cluster.Spec.DeletionProtection = true
+		resp := newHandler(cluster).Handle(ctx, deleteRequest("PersistentVolumeClaim", pvc(cluster, "scratch-B-0", "B")))
+		require.True(t, resp.Allowed)
+	})
+}
+
+func TestValidateLicenseLimits(t *testing.T) {
+	t.Run("reject growing beyond the cluster size", func(t *testing.T) {
+		errs := validator.ValidateLicenseLimits(3, 4, 3)
+		require.Len(t, errs, 1)
+		require.Contains(t, errs[0], "4 nodes exceed the license's cluster size of 3")
+	})
+
+	t.Run("accept within limits or unknown limits", func(t *testing.T) {
+		require.Empty(t, validator.ValidateLicenseLimits(2, 3, 3))
+		require.Empty(t, validator.ValidateLicenseLimits(3, 5, 0))
+	})
+
+	t.Run("accept shrinking an over-limit cluster", func(t *testing.T) {
+		require.Empty(t, validator.ValidateLicenseLimits(5, 4, 3))
+	})
+}
+
 // TODO: add client and ca certs tests.
 
 func ptr(s string) *string { return &s }
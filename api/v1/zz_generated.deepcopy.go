@@ -82,6 +82,71 @@ func (in *AdditionalVolume) DeepCopy() *AdditionalVolume {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertSummary) DeepCopyInto(out *AlertSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertSummary.
+func (in *AlertSummary) DeepCopy() *AlertSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsSpec) DeepCopyInto(out *AlertsSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsSpec.
+func (in *AlertsSpec) DeepCopy() *AlertsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertsStatus) DeepCopyInto(out *AlertsStatus) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AlertSummary, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertsStatus.
+func (in *AlertsStatus) DeepCopy() *AlertsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureBackupDestination) DeepCopyInto(out *AzureBackupDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureBackupDestination.
+func (in *AzureBackupDestination) DeepCopy() *AzureBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureExternalAccessContext) DeepCopyInto(out *AzureExternalAccessContext) {
 	*out = *in
@@ -117,6 +182,226 @@ func (in *AzureNodeMapping) DeepCopy() *AzureNodeMapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupDestination) DeepCopyInto(out *BackupDestination) {
+	*out = *in
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(S3BackupDestination)
+		**out = **in
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureBackupDestination)
+		**out = **in
+	}
+	if in.Local != nil {
+		in, out := &in.Local, &out.Local
+		*out = new(LocalBackupDestination)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupDestination.
+func (in *BackupDestination) DeepCopy() *BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupRetention) DeepCopyInto(out *BackupRetention) {
+	*out = *in
+	out.MinimumBackupAge = in.MinimumBackupAge
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupRetention.
+func (in *BackupRetention) DeepCopy() *BackupRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerRef) DeepCopyInto(out *CertManagerIssuerRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerRef.
+func (in *CertManagerIssuerRef) DeepCopy() *CertManagerIssuerRef {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerSpec) DeepCopyInto(out *CertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerSpec.
+func (in *CertManagerSpec) DeepCopy() *CertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRenewalStatus) DeepCopyInto(out *CertificateRenewalStatus) {
+	*out = *in
+	if in.LastRenewalTime != nil {
+		in, out := &in.LastRenewalTime, &out.LastRenewalTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeCertificateRenewalStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRenewalStatus.
+func (in *CertificateRenewalStatus) DeepCopy() *CertificateRenewalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRenewalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotationStatus) DeepCopyInto(out *CertificateRotationStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeCertificateRotationStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotationStatus.
+func (in *CertificateRotationStatus) DeepCopy() *CertificateRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateStatus) DeepCopyInto(out *ClientCertificateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateStatus.
+func (in *ClientCertificateStatus) DeepCopy() *ClientCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseBackupRunStatus) DeepCopyInto(out *DatabaseBackupRunStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseBackupRunStatus.
+func (in *DatabaseBackupRunStatus) DeepCopy() *DatabaseBackupRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseBackupRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseSummary) DeepCopyInto(out *DatabaseSummary) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rehabs != nil {
+		in, out := &in.Rehabs, &out.Rehabs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseSummary.
+func (in *DatabaseSummary) DeepCopy() *DatabaseSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalAccessConfiguration) DeepCopyInto(out *ExternalAccessConfiguration) {
 	*out = *in
@@ -147,6 +432,46 @@ func (in *ExternalAccessConfiguration) DeepCopy() *ExternalAccessConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HealthSpec) DeepCopyInto(out *HealthSpec) {
+	*out = *in
+	if in.RestartThreshold != nil {
+		in, out := &in.RestartThreshold, &out.RestartThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RestartWindow != nil {
+		in, out := &in.RestartWindow, &out.RestartWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MinFreeStoragePercent != nil {
+		in, out := &in.MinFreeStoragePercent, &out.MinFreeStoragePercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.CertificateExpiryWarningDays != nil {
+		in, out := &in.CertificateExpiryWarningDays, &out.CertificateExpiryWarningDays
+		*out = new(int32)
+		**out = **in
+	}
+	if in.LicenseExpiryWarningDays != nil {
+		in, out := &in.LicenseExpiryWarningDays, &out.LicenseExpiryWarningDays
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HealthSpec.
+func (in *HealthSpec) DeepCopy() *HealthSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HealthSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IngressControllerContext) DeepCopyInto(out *IngressControllerContext) {
 	*out = *in
@@ -169,6 +494,72 @@ func (in *IngressControllerContext) DeepCopy() *IngressControllerContext {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseStatus) DeepCopyInto(out *LicenseStatus) {
+	*out = *in
+	if in.Expiration != nil {
+		in, out := &in.Expiration, &out.Expiration
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseStatus.
+func (in *LicenseStatus) DeepCopy() *LicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LicenseUpdateStatus) DeepCopyInto(out *LicenseUpdateStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.RestartedAt != nil {
+		in, out := &in.RestartedAt, &out.RestartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Nodes != nil {
+		in, out := &in.Nodes, &out.Nodes
+		*out = make([]NodeLicenseStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LicenseUpdateStatus.
+func (in *LicenseUpdateStatus) DeepCopy() *LicenseUpdateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LicenseUpdateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalBackupDestination) DeepCopyInto(out *LocalBackupDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalBackupDestination.
+func (in *LocalBackupDestination) DeepCopy() *LocalBackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalBackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LogSettings) DeepCopyInto(out *LogSettings) {
 	*out = *in
@@ -180,37 +571,456 @@ func (in *LogSettings) DeepCopyInto(out *LogSettings) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSettings.
-func (in *LogSettings) DeepCopy() *LogSettings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSettings.
+func (in *LogSettings) DeepCopy() *LogSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogsSpec) DeepCopyInto(out *LogsSpec) {
+	*out = *in
+	if in.RavenDB != nil {
+		in, out := &in.RavenDB, &out.RavenDB
+		*out = new(LogSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(LogSettings)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSpec.
+func (in *LogsSpec) DeepCopy() *LogsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LogsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringSpec.
+func (in *MonitoringSpec) DeepCopy() *MonitoringSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCertificateRenewalStatus) DeepCopyInto(out *NodeCertificateRenewalStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRenewalTime != nil {
+		in, out := &in.LastRenewalTime, &out.LastRenewalTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCertificateRenewalStatus.
+func (in *NodeCertificateRenewalStatus) DeepCopy() *NodeCertificateRenewalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCertificateRenewalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeCertificateRotationStatus) DeepCopyInto(out *NodeCertificateRotationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeCertificateRotationStatus.
+func (in *NodeCertificateRotationStatus) DeepCopy() *NodeCertificateRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeCertificateRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLicenseStatus) DeepCopyInto(out *NodeLicenseStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLicenseStatus.
+func (in *NodeLicenseStatus) DeepCopy() *NodeLicenseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLicenseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProvidedClientCertificate) DeepCopyInto(out *ProvidedClientCertificate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProvidedClientCertificate.
+func (in *ProvidedClientCertificate) DeepCopy() *ProvidedClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(ProvidedClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackup) DeepCopyInto(out *RavenDBBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackup.
+func (in *RavenDBBackup) DeepCopy() *RavenDBBackup {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBBackup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupList) DeepCopyInto(out *RavenDBBackupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RavenDBBackup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupList.
+func (in *RavenDBBackupList) DeepCopy() *RavenDBBackupList {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBBackupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupRun) DeepCopyInto(out *RavenDBBackupRun) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupRun.
+func (in *RavenDBBackupRun) DeepCopy() *RavenDBBackupRun {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupRun)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBBackupRun) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupRunList) DeepCopyInto(out *RavenDBBackupRunList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RavenDBBackupRun, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupRunList.
+func (in *RavenDBBackupRunList) DeepCopy() *RavenDBBackupRunList {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupRunList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBBackupRunList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupRunSpec) DeepCopyInto(out *RavenDBBackupRunSpec) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupRunSpec.
+func (in *RavenDBBackupRunSpec) DeepCopy() *RavenDBBackupRunSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupRunSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupRunStatus) DeepCopyInto(out *RavenDBBackupRunStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseBackupRunStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupRunStatus.
+func (in *RavenDBBackupRunStatus) DeepCopy() *RavenDBBackupRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupSpec) DeepCopyInto(out *RavenDBBackupSpec) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(BackupRetention)
+		**out = **in
+	}
+	in.Destination.DeepCopyInto(&out.Destination)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupSpec.
+func (in *RavenDBBackupSpec) DeepCopy() *RavenDBBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBBackupStatus) DeepCopyInto(out *RavenDBBackupStatus) {
+	*out = *in
+	if in.LastFullBackup != nil {
+		in, out := &in.LastFullBackup, &out.LastFullBackup
+		*out = (*in).DeepCopy()
+	}
+	if in.LastIncrementalBackup != nil {
+		in, out := &in.LastIncrementalBackup, &out.LastIncrementalBackup
+		*out = (*in).DeepCopy()
+	}
+	if in.LastSuccessTime != nil {
+		in, out := &in.LastSuccessTime, &out.LastSuccessTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastErrorTime != nil {
+		in, out := &in.LastErrorTime, &out.LastErrorTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBBackupStatus.
+func (in *RavenDBBackupStatus) DeepCopy() *RavenDBBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBClientCertificate) DeepCopyInto(out *RavenDBClientCertificate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClientCertificate.
+func (in *RavenDBClientCertificate) DeepCopy() *RavenDBClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBClientCertificate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBClientCertificateList) DeepCopyInto(out *RavenDBClientCertificateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RavenDBClientCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClientCertificateList.
+func (in *RavenDBClientCertificateList) DeepCopy() *RavenDBClientCertificateList {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBClientCertificateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBClientCertificateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBClientCertificateSpec) DeepCopyInto(out *RavenDBClientCertificateSpec) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make(map[string]DatabaseAccess, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Provided != nil {
+		in, out := &in.Provided, &out.Provided
+		*out = new(ProvidedClientCertificate)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClientCertificateSpec.
+func (in *RavenDBClientCertificateSpec) DeepCopy() *RavenDBClientCertificateSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(LogSettings)
+	out := new(RavenDBClientCertificateSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogsSpec) DeepCopyInto(out *LogsSpec) {
+func (in *RavenDBClientCertificateStatus) DeepCopyInto(out *RavenDBClientCertificateStatus) {
 	*out = *in
-	if in.RavenDB != nil {
-		in, out := &in.RavenDB, &out.RavenDB
-		*out = new(LogSettings)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Audit != nil {
-		in, out := &in.Audit, &out.Audit
-		*out = new(LogSettings)
-		(*in).DeepCopyInto(*out)
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogsSpec.
-func (in *LogsSpec) DeepCopy() *LogsSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClientCertificateStatus.
+func (in *RavenDBClientCertificateStatus) DeepCopy() *RavenDBClientCertificateStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LogsSpec)
+	out := new(RavenDBClientCertificateStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -312,6 +1122,33 @@ func (in *RavenDBClusterSpec) DeepCopyInto(out *RavenDBClusterSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsSpec)
+		**out = **in
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(MonitoringSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(HealthSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustedClientCertificates != nil {
+		in, out := &in.TrustedClientCertificates, &out.TrustedClientCertificates
+		*out = make([]TrustedClientCertificate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClusterSpec.
@@ -341,6 +1178,55 @@ func (in *RavenDBClusterStatus) DeepCopyInto(out *RavenDBClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(AlertsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.License != nil {
+		in, out := &in.License, &out.License
+		*out = new(LicenseStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LicenseUpdate != nil {
+		in, out := &in.LicenseUpdate, &out.LicenseUpdate
+		*out = new(LicenseUpdateStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateRenewal != nil {
+		in, out := &in.CertificateRenewal, &out.CertificateRenewal
+		*out = new(CertificateRenewalStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCertificate != nil {
+		in, out := &in.ClientCertificate, &out.ClientCertificate
+		*out = new(ClientCertificateStatus)
+		**out = **in
+	}
+	if in.TrustedClientCertificates != nil {
+		in, out := &in.TrustedClientCertificates, &out.TrustedClientCertificates
+		*out = make([]TrustedClientCertificateStatus, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBClusterStatus.
@@ -353,6 +1239,127 @@ func (in *RavenDBClusterStatus) DeepCopy() *RavenDBClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBDatabase) DeepCopyInto(out *RavenDBDatabase) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBDatabase.
+func (in *RavenDBDatabase) DeepCopy() *RavenDBDatabase {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBDatabase)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBDatabase) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBDatabaseList) DeepCopyInto(out *RavenDBDatabaseList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RavenDBDatabase, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBDatabaseList.
+func (in *RavenDBDatabaseList) DeepCopy() *RavenDBDatabaseList {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBDatabaseList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBDatabaseList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBDatabaseSpec) DeepCopyInto(out *RavenDBDatabaseSpec) {
+	*out = *in
+	if in.ReplicationFactor != nil {
+		in, out := &in.ReplicationFactor, &out.ReplicationFactor
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Topology != nil {
+		in, out := &in.Topology, &out.Topology
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBDatabaseSpec.
+func (in *RavenDBDatabaseSpec) DeepCopy() *RavenDBDatabaseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBDatabaseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBDatabaseStatus) DeepCopyInto(out *RavenDBDatabaseStatus) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Promotables != nil {
+		in, out := &in.Promotables, &out.Promotables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rehabs != nil {
+		in, out := &in.Rehabs, &out.Rehabs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBDatabaseStatus.
+func (in *RavenDBDatabaseStatus) DeepCopy() *RavenDBDatabaseStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBDatabaseStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RavenDBNode) DeepCopyInto(out *RavenDBNode) {
 	*out = *in
@@ -377,6 +1384,23 @@ func (in *RavenDBNode) DeepCopy() *RavenDBNode {
 func (in *RavenDBNodeStatus) DeepCopyInto(out *RavenDBNodeStatus) {
 	*out = *in
 	in.LastAttemptTime.DeepCopyInto(&out.LastAttemptTime)
+	if in.Connected != nil {
+		in, out := &in.Connected, &out.Connected
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Restarts != nil {
+		in, out := &in.Restarts, &out.Restarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RecentRestarts != nil {
+		in, out := &in.RecentRestarts, &out.RecentRestarts
+		*out = make([]metav1.Time, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBNodeStatus.
@@ -389,6 +1413,119 @@ func (in *RavenDBNodeStatus) DeepCopy() *RavenDBNodeStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBRestore) DeepCopyInto(out *RavenDBRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBRestore.
+func (in *RavenDBRestore) DeepCopy() *RavenDBRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBRestoreList) DeepCopyInto(out *RavenDBRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RavenDBRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBRestoreList.
+func (in *RavenDBRestoreList) DeepCopy() *RavenDBRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RavenDBRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBRestoreSpec) DeepCopyInto(out *RavenDBRestoreSpec) {
+	*out = *in
+	in.Source.DeepCopyInto(&out.Source)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBRestoreSpec.
+func (in *RavenDBRestoreSpec) DeepCopy() *RavenDBRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RavenDBRestoreStatus) DeepCopyInto(out *RavenDBRestoreStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RavenDBRestoreStatus.
+func (in *RavenDBRestoreStatus) DeepCopy() *RavenDBRestoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RavenDBRestoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3BackupDestination) DeepCopyInto(out *S3BackupDestination) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3BackupDestination.
+func (in *S3BackupDestination) DeepCopy() *S3BackupDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(S3BackupDestination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageSpec) DeepCopyInto(out *StorageSpec) {
 	*out = *in
@@ -421,6 +1558,43 @@ func (in *StorageSpec) DeepCopy() *StorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedClientCertificate) DeepCopyInto(out *TrustedClientCertificate) {
+	*out = *in
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = make(map[string]DatabaseAccess, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedClientCertificate.
+func (in *TrustedClientCertificate) DeepCopy() *TrustedClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustedClientCertificateStatus) DeepCopyInto(out *TrustedClientCertificateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustedClientCertificateStatus.
+func (in *TrustedClientCertificateStatus) DeepCopy() *TrustedClientCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustedClientCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSource) DeepCopyInto(out *VolumeSource) {
 	*out = *in
@@ -25,19 +25,23 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/internal/controller"
 	"ravendb-operator/pkg/director"
+	"ravendb-operator/pkg/webhook/protection"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -59,6 +63,7 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var pruneDryRun bool
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -70,6 +75,8 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&pruneDryRun, "prune-dry-run", false,
+		"If set, resources no longer produced by a cluster spec are only logged instead of deleted.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -139,6 +146,12 @@ func main() {
 		// if you are doing or is intended to do any operation such as perform cleanups
 		// after the manager stops then its usage might be unsafe.
 		// LeaderElectionReleaseOnCancel: true,
+
+		// Secrets are read by name and never cached: the controllers only watch their metadata,
+		// so the operator doesn't keep every Secret of the cluster in memory.
+		Client: client.Options{
+			Cache: &client.CacheOptions{DisableFor: []client.Object{&corev1.Secret{}}},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
@@ -146,18 +159,57 @@ func main() {
 	}
 
 	if err = (&controller.RavenDBClusterReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Director: director.NewDefaultDirector(),
+		Client:      mgr.GetClient(),
+		Scheme:      mgr.GetScheme(),
+		Director:    director.NewDefaultDirector(),
+		PruneDryRun: pruneDryRun,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "RavenDBCluster")
 		os.Exit(1)
 	}
+	if err = (&controller.RavenDBClientCertificateReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBClientCertificate")
+		os.Exit(1)
+	}
+	if err = (&controller.RavenDBDatabaseReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBDatabase")
+		os.Exit(1)
+	}
+	if err = (&controller.RavenDBBackupReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBBackup")
+		os.Exit(1)
+	}
+	if err = (&controller.RavenDBRestoreReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBRestore")
+		os.Exit(1)
+	}
+	if err = (&controller.RavenDBBackupRunReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "RavenDBBackupRun")
+		os.Exit(1)
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&ravendbv1.RavenDBCluster{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "RavenDBCluster")
 			os.Exit(1)
 		}
+		mgr.GetWebhookServer().Register(protection.Path, &webhook.Admission{
+			Handler: protection.NewHandler(mgr.GetClient(), admission.NewDecoder(mgr.GetScheme())),
+		})
 	}
 	// +kubebuilder:scaffold:builder
 
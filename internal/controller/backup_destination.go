@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// keys of the destination credentials secrets
+const (
+	s3AccessKeyIdKey     = "accessKeyId"
+	s3SecretAccessKeyKey = "secretAccessKey"
+	s3SessionTokenKey    = "sessionToken"
+	azureAccountNameKey  = "accountName"
+	azureAccountKeyKey   = "accountKey"
+	azureSasTokenKey     = "sasToken"
+)
+
+// backupSettings turns a destination into RavenDB's settings, credentials included.
+func backupSettings(ctx context.Context, c client.Reader, namespace string, dest ravendbv1.BackupDestination) (upgrade.BackupSettings, error) {
+	var creds map[string][]byte
+	if ref := dest.CredentialsSecretRef(); ref != "" {
+		var secret corev1.Secret
+		if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ref}, &secret); err != nil {
+			return upgrade.BackupSettings{}, fmt.Errorf("get credentials secret %q: %w", ref, err)
+		}
+		creds = secret.Data
+	}
+	value := func(key string) string { return strings.TrimSpace(string(creds[key])) }
+	require := func(keys ...string) error {
+		for _, k := range keys {
+			if value(k) == "" {
+				return fmt.Errorf("credentials secret %q has no %s", dest.CredentialsSecretRef(), k)
+			}
+		}
+		return nil
+	}
+
+	switch {
+	case dest.S3 != nil:
+		if err := require(s3AccessKeyIdKey, s3SecretAccessKeyKey); err != nil {
+			return upgrade.BackupSettings{}, err
+		}
+		return upgrade.BackupSettings{S3Settings: &upgrade.S3Settings{
+			BucketName:       dest.S3.BucketName,
+			RemoteFolderName: dest.S3.RemoteFolderName,
+			AwsRegionName:    dest.S3.Region,
+			AwsAccessKey:     value(s3AccessKeyIdKey),
+			AwsSecretKey:     value(s3SecretAccessKeyKey),
+			AwsSessionToken:  value(s3SessionTokenKey),
+			CustomServerUrl:  dest.S3.CustomServerURL,
+			ForcePathStyle:   dest.S3.ForcePathStyle,
+		}}, nil
+	case dest.Azure != nil:
+		if err := require(azureAccountNameKey); err != nil {
+			return upgrade.BackupSettings{}, err
+		}
+		if value(azureAccountKeyKey) == "" && value(azureSasTokenKey) == "" {
+			return upgrade.BackupSettings{}, fmt.Errorf("credentials secret %q has neither %s nor %s", dest.CredentialsSecretRef(), azureAccountKeyKey, azureSasTokenKey)
+		}
+		return upgrade.BackupSettings{AzureSettings: &upgrade.AzureSettings{
+			StorageContainer: dest.Azure.StorageContainer,
+			RemoteFolderName: dest.Azure.RemoteFolderName,
+			AccountName:      value(azureAccountNameKey),
+			AccountKey:       value(azureAccountKeyKey),
+			SasToken:         value(azureSasTokenKey),
+		}}, nil
+	case dest.Local != nil:
+		return upgrade.BackupSettings{LocalSettings: &upgrade.LocalSettings{FolderPath: dest.Local.FolderPath}}, nil
+	}
+	return upgrade.BackupSettings{}, fmt.Errorf("no backup destination set")
+}
+
+// configurationHash identifies what was sent to RavenDB, so it's only sent again when the spec
+// or the credentials changed.
+func configurationHash(v any) string {
+	data, _ := json.Marshal(v)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
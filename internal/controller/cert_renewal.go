@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// reconcileCertificateRenewal watches the Let's Encrypt certificates RavenDB renews itself.
+// update-cert.sh writes a renewed server.pfx into the node's certSecretRef, so a new thumbprint
+// in a secret is a renewal. Once bootstrapped, every node's public URL is also checked for the
+// certificate it serves: a node serving another one than its secret holds has diverged, and
+// would come back with the stale certificate after a restart.
+//
+// Renewals and divergence are recorded in status.certificateRenewal and raised as events when
+// they're first seen.
+func (r *RavenDBClusterReconciler) reconcileCertificateRenewal(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) {
+	if cluster.Spec.Mode != ravendbv1.ModeLetsEncrypt {
+		cluster.Status.CertificateRenewal = nil
+		return
+	}
+
+	st := cluster.Status.CertificateRenewal
+	if st == nil {
+		st = &ravendbv1.CertificateRenewalStatus{}
+	}
+	prevByTag := map[string]ravendbv1.NodeCertificateRenewalStatus{}
+	for _, n := range st.Nodes {
+		prevByTag[n.Tag] = n
+	}
+
+	// the handshake only needs the nodes' URLs
+	var hcc *upgrade.HealthCheckContext
+	if cluster.IsBootstrapped() {
+		hcc = upgrade.NewChecks(nil, cluster)
+	}
+
+	now := metav1.Now()
+	nodes := make([]ravendbv1.NodeCertificateRenewalStatus, 0, len(cluster.Spec.Nodes))
+	for _, node := range cluster.Spec.Nodes {
+		prev := prevByTag[node.Tag]
+		ns := ravendbv1.NodeCertificateRenewalStatus{
+			Tag:             node.Tag,
+			SecretName:      common.NodeCertSecretName(cluster, node),
+			LastRenewalTime: prev.LastRenewalTime,
+		}
+
+		cert, err := r.readNodeCertificate(ctx, cluster.Namespace, ns.SecretName)
+		if err != nil {
+			// keep what we knew, the secret may just be in the middle of an update
+			ns.SecretThumbprint, ns.NotAfter = prev.SecretThumbprint, prev.NotAfter
+			ns.Error = err.Error()
+			nodes = append(nodes, ns)
+			continue
+		}
+		ns.SecretThumbprint = certutil.Thumbprint(cert)
+		notAfter := metav1.NewTime(cert.NotAfter)
+		ns.NotAfter = &notAfter
+
+		if prev.SecretThumbprint != "" && prev.SecretThumbprint != ns.SecretThumbprint {
+			ns.LastRenewalTime = &now
+			st.LastRenewalTime = &now
+			logger.Info("node certificate renewed", "node", node.Tag, "secret", ns.SecretName, "thumbprint", ns.SecretThumbprint)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRenewed",
+					"node %s: secret %s holds the renewed certificate %s, valid until %s",
+					node.Tag, ns.SecretName, ns.SecretThumbprint, cert.NotAfter.UTC().Format(time.RFC3339))
+			}
+		}
+
+		if hcc != nil {
+			served, err := hcc.ServedCertificate(ctx, node.Tag)
+			if err != nil {
+				ns.ServedThumbprint = prev.ServedThumbprint
+				ns.Error = err.Error()
+			} else {
+				ns.ServedThumbprint = certutil.Thumbprint(served)
+			}
+			ns.Diverged = ns.ServedThumbprint != "" && ns.ServedThumbprint != ns.SecretThumbprint
+		}
+
+		if ns.Diverged && !prev.Diverged {
+			logger.Info("node serves another certificate than its secret holds", "node", node.Tag, "served", ns.ServedThumbprint, "secret", ns.SecretThumbprint)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CertificateDiverged",
+					"node %s serves certificate %s but secret %s holds %s", node.Tag, ns.ServedThumbprint, ns.SecretName, ns.SecretThumbprint)
+			}
+		}
+		nodes = append(nodes, ns)
+	}
+
+	st.Nodes = nodes
+	cluster.Status.CertificateRenewal = st
+}
+
+func (r *RavenDBClusterReconciler) readNodeCertificate(ctx context.Context, namespace, name string) (*x509.Certificate, error) {
+	if name == "" {
+		return nil, fmt.Errorf("no certSecretRef")
+	}
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, fmt.Errorf("get certificate secret %q: %w", name, err)
+	}
+	pfx := secret.Data[serverPFXKey]
+	if len(pfx) == 0 {
+		return nil, fmt.Errorf("secret %q has no %s", name, serverPFXKey)
+	}
+	cert, err := certutil.ParsePFXCertificate(pfx, "")
+	if err != nil {
+		return nil, fmt.Errorf("secret %q: %w", name, err)
+	}
+	return cert, nil
+}
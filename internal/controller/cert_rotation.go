@@ -0,0 +1,320 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// while a rotation is in progress we poll the nodes' served certificates this often.
+const requeueDuringCertRotation = 15 * time.Second
+
+const serverPFXKey = "server.pfx"
+
+// reconcileCertificateRotation drives a None-mode cluster certificate rotation, requested by
+// pointing clusterCertSecretRef at another secret or bumping certificateRotationGeneration:
+//  1. the new server.pfx is handed to RavenDB's cluster certificate replacement,
+//  2. every node's public URL is checked until it serves the new certificate,
+//  3. the new server.pfx is copied into the secrets the node pods still mount, so a restart
+//     doesn't bring the old certificate back.
+//
+// Progress is kept in status.certificateRotation. With spec.certManager the cert-manager
+// actor replaces renewed certificates itself. It reports whether a rotation is in progress.
+func (r *RavenDBClusterReconciler) reconcileCertificateRotation(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) bool {
+	if cluster.Spec.Mode != ravendbv1.ModeNone || cluster.Spec.ClusterCertSecretRef == nil || cluster.Spec.CertManager != nil {
+		cluster.Status.CertificateRotation = nil
+		return false
+	}
+
+	rot := cluster.Status.CertificateRotation
+	if rot == nil || !cluster.IsBootstrapped() {
+		cluster.Status.CertificateRotation = r.rotationBaseline(ctx, cluster)
+		return false
+	}
+
+	if rot.Phase != ravendbv1.CertificateRotationInProgress && !cluster.CertificateRotationRequested() {
+		return false
+	}
+
+	now := metav1.Now()
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, cluster)
+	if err != nil {
+		if rot.Phase != ravendbv1.CertificateRotationInProgress {
+			rot.Phase = ravendbv1.CertificateRotationPending
+		}
+		rot.Message = "cannot reach the cluster: " + err.Error()
+		return true
+	}
+	hcc := upgrade.NewChecks(httpc, cluster)
+
+	// RavenDB replaces one certificate at a time, a newer request waits for this one.
+	if rot.Phase != ravendbv1.CertificateRotationInProgress {
+		r.startCertificateRotation(ctx, cluster, hcc, logger, now)
+		if rot = cluster.Status.CertificateRotation; rot.Phase != ravendbv1.CertificateRotationInProgress {
+			return false
+		}
+	}
+
+	var waiting []string
+	for i := range rot.Nodes {
+		n := &rot.Nodes[i]
+		served, err := hcc.ServedCertificate(ctx, n.Tag)
+		if err != nil {
+			n.Confirmed, n.ServedThumbprint, n.Error = false, "", err.Error()
+			waiting = append(waiting, n.Tag)
+			continue
+		}
+		n.ServedThumbprint, n.Error = certutil.Thumbprint(served), ""
+		n.Confirmed = n.ServedThumbprint == rot.Thumbprint
+		if !n.Confirmed {
+			waiting = append(waiting, n.Tag)
+		}
+	}
+	if len(waiting) > 0 {
+		rot.Message = "waiting for nodes " + strings.Join(waiting, ", ") + " to serve the new certificate"
+		return true
+	}
+
+	if err := r.syncMountedCertSecrets(ctx, cluster, rot.SecretName); err != nil {
+		rot.Message = "update mounted certificate secrets: " + err.Error()
+		return true
+	}
+
+	rot.Phase = ravendbv1.CertificateRotationCompleted
+	rot.Message = "every node serves the new certificate"
+	rot.CompletedAt = &now
+	logger.Info("cluster certificate rotation completed", "secret", rot.SecretName, "thumbprint", rot.Thumbprint)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRotationCompleted",
+			"every node serves certificate %s from secret %s", rot.Thumbprint, rot.SecretName)
+	}
+	return false
+}
+
+// rotationBaseline records the certificate the cluster runs with, so only later spec
+// changes rotate.
+func (r *RavenDBClusterReconciler) rotationBaseline(ctx context.Context, cluster *ravendbv1.RavenDBCluster) *ravendbv1.CertificateRotationStatus {
+	base := &ravendbv1.CertificateRotationStatus{
+		SecretName: *cluster.Spec.ClusterCertSecretRef,
+		Generation: cluster.Spec.CertificateRotationGeneration,
+		Phase:      ravendbv1.CertificateRotationCompleted,
+	}
+	if prev := cluster.Status.CertificateRotation; prev != nil && prev.SecretName == base.SecretName {
+		base.Thumbprint = prev.Thumbprint
+	}
+	if _, thumbprint, err := r.readServerCertificate(ctx, cluster, base.SecretName); err == nil {
+		base.Thumbprint = thumbprint
+	}
+	return base
+}
+
+func (r *RavenDBClusterReconciler) startCertificateRotation(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, logger logr.Logger, now metav1.Time) {
+	prev := cluster.Status.CertificateRotation
+	next := &ravendbv1.CertificateRotationStatus{
+		SecretName:         *cluster.Spec.ClusterCertSecretRef,
+		Generation:         cluster.Spec.CertificateRotationGeneration,
+		PreviousSecretName: prev.SecretName,
+		Phase:              ravendbv1.CertificateRotationFailed,
+		StartedAt:          &now,
+	}
+	// a retried rotation keeps the secret the nodes served from before the first attempt
+	if prev.Phase != ravendbv1.CertificateRotationCompleted && prev.PreviousSecretName != "" {
+		next.PreviousSecretName = prev.PreviousSecretName
+	}
+	cluster.Status.CertificateRotation = next
+
+	pfx, thumbprint, err := r.readServerCertificate(ctx, cluster, next.SecretName)
+	if err != nil {
+		next.Message = err.Error()
+		r.rotationFailed(cluster, logger, err)
+		return
+	}
+	next.Thumbprint = thumbprint
+
+	if servesEverywhere(ctx, cluster, hcc, thumbprint) {
+		next.Phase = ravendbv1.CertificateRotationCompleted
+		next.Message = "the cluster already uses this certificate"
+		next.CompletedAt = &now
+		return
+	}
+
+	if err := hcc.ReplaceClusterCertificate(ctx, cluster.Name, pfx, ""); err != nil {
+		next.Message = err.Error()
+		r.rotationFailed(cluster, logger, err)
+		return
+	}
+
+	next.Phase = ravendbv1.CertificateRotationInProgress
+	next.Message = "RavenDB accepted the certificate, waiting for every node to confirm it"
+	for _, n := range cluster.Spec.Nodes {
+		next.Nodes = append(next.Nodes, ravendbv1.NodeCertificateRotationStatus{Tag: n.Tag})
+	}
+	logger.Info("cluster certificate rotation started", "secret", next.SecretName, "thumbprint", thumbprint)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateRotationStarted",
+			"replacing the cluster certificate with %s from secret %s", thumbprint, next.SecretName)
+	}
+}
+
+func servesEverywhere(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, thumbprint string) bool {
+	for _, n := range cluster.Spec.Nodes {
+		served, err := hcc.ServedCertificate(ctx, n.Tag)
+		if err != nil || certutil.Thumbprint(served) != thumbprint {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *RavenDBClusterReconciler) rotationFailed(cluster *ravendbv1.RavenDBCluster, logger logr.Logger, err error) {
+	logger.Error(err, "cluster certificate rotation failed")
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CertificateRotationFailed", "%v", err)
+	}
+}
+
+func (r *RavenDBClusterReconciler) readServerCertificate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, name string) ([]byte, string, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &secret); err != nil {
+		return nil, "", fmt.Errorf("get cluster certificate secret %q: %w", name, err)
+	}
+	pfx := secret.Data[serverPFXKey]
+	if len(pfx) == 0 {
+		return nil, "", fmt.Errorf("secret %q has no %s", name, serverPFXKey)
+	}
+	cert, err := certutil.ParsePFXCertificate(pfx, "")
+	if err != nil {
+		return nil, "", fmt.Errorf("secret %q: %w", name, err)
+	}
+	return pfx, certutil.Thumbprint(cert), nil
+}
+
+// syncMountedCertSecrets copies server.pfx from the rotated secret into the certificate
+// secret each node's StatefulSet still mounts. The StatefulSets themselves only move to the
+// new secret name when the upgrader next rolls them. With spec.rollOnSecretChange the
+// changed secrets roll the nodes, one at a time.
+func (r *RavenDBClusterReconciler) syncMountedCertSecrets(ctx context.Context, cluster *ravendbv1.RavenDBCluster, source string) error {
+	var src corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: source}, &src); err != nil {
+		return err
+	}
+	pfx := src.Data[serverPFXKey]
+
+	for _, n := range cluster.Spec.Nodes {
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: common.Prefix + n.Tag}, &sts); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		mounted := mountedSecretName(&sts, common.CertVolumeName)
+		if mounted == "" || mounted == source {
+			continue
+		}
+
+		var dst corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: mounted}, &dst); err != nil {
+			return err
+		}
+		if bytes.Equal(dst.Data[serverPFXKey], pfx) {
+			continue
+		}
+		patch := client.MergeFrom(dst.DeepCopy())
+		if dst.Data == nil {
+			dst.Data = map[string][]byte{}
+		}
+		dst.Data[serverPFXKey] = pfx
+		if err := r.Patch(ctx, &dst, patch); err != nil {
+			return fmt.Errorf("secret %q: %w", mounted, err)
+		}
+	}
+	return nil
+}
+
+func mountedSecretName(sts *appsv1.StatefulSet, volume string) string {
+	for _, v := range sts.Spec.Template.Spec.Volumes {
+		if v.Name == volume && v.Secret != nil {
+			return v.Secret.SecretName
+		}
+	}
+	return ""
+}
+
+// reconcileCertManagerReplacement hands a server certificate cert-manager renewed, which the
+// cert-manager actor marked pending, to RavenDB's cluster certificate replacement. The mark
+// survives failures and operator restarts, so a failed call is retried; it doesn't hold up
+// the rest of the reconcile. It reports whether a replacement is still pending.
+func (r *RavenDBClusterReconciler) reconcileCertManagerReplacement(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) bool {
+	if cluster.Spec.CertManager == nil || cluster.Spec.ClusterCertSecretRef == nil || !cluster.IsBootstrapped() {
+		return false
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: *cluster.Spec.ClusterCertSecretRef}, &secret); err != nil {
+		if !kerrors.IsNotFound(err) {
+			logger.Error(err, "failed to read the server certificate secret")
+		}
+		return false
+	}
+	if secret.Annotations[common.CertReplacePendingAnnotation] != "true" {
+		return false
+	}
+
+	err := func() error {
+		httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, cluster)
+		if err != nil {
+			return err
+		}
+		return upgrade.NewChecks(httpc, cluster).ReplaceClusterCertificate(ctx, cluster.Name, secret.Data[serverPFXKey], "")
+	}()
+	if err != nil {
+		logger.Error(err, "failed to hand the renewed server certificate to RavenDB, will retry")
+		if r.Recorder != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "CertificateReplacementFailed", "renewed server certificate: %v", err)
+		}
+		return true
+	}
+	logger.Info("renewed server certificate handed to RavenDB for cluster-wide replacement")
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "CertificateReplacementRequested", "renewed server certificate handed to RavenDB for cluster-wide replacement")
+	}
+
+	patch := client.MergeFrom(secret.DeepCopy())
+	delete(secret.Annotations, common.CertReplacePendingAnnotation)
+	if err := r.Patch(ctx, &secret, patch); err != nil {
+		logger.Error(err, "failed to clear the certificate replacement mark")
+		return true
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"maps"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// reconcileClientCertificates keeps the client certificates RavenDB trusts in line with the spec:
+//   - the operator's own certificate: the bootstrapper registers clientCertSecretRef once. When it
+//     points at another secret, that certificate is registered with the old one, the operator
+//     switches over once the new one answers. The old one is revoked on a later reconcile, once
+//     status.clientCertificate, which names the secret BuildHTTPSClientFromCluster uses, has
+//     been saved with the new secret.
+//   - spec.trustedClientCertificates are registered (or edited) with their clearance and
+//     permissions; entries dropped from the list are revoked.
+func (r *RavenDBClusterReconciler) reconcileClientCertificates(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) {
+	own := cluster.Status.ClientCertificate
+	if own == nil || !cluster.IsBootstrapped() {
+		// the bootstrapper registers whatever clientCertSecretRef holds
+		own = &ravendbv1.ClientCertificateStatus{SecretName: cluster.Spec.ClientCertSecretRef}
+		if cert, err := upgrade.ClientCertificate(ctx, r.Client, cluster, own.SecretName); err == nil {
+			own.Thumbprint = certutil.Thumbprint(cert)
+		}
+		cluster.Status.ClientCertificate = own
+		return
+	}
+
+	if own.SecretName == cluster.Spec.ClientCertSecretRef && own.RevokeThumbprint == "" &&
+		len(cluster.Spec.TrustedClientCertificates) == 0 && len(cluster.Status.TrustedClientCertificates) == 0 {
+		return
+	}
+
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, cluster)
+	if err != nil {
+		own.Message = "cannot reach the cluster: " + err.Error()
+		return
+	}
+	hcc := upgrade.NewChecks(httpc, cluster)
+
+	registered, err := hcc.Certificates(ctx)
+	if err != nil {
+		own.Message = err.Error()
+		return
+	}
+
+	if own.SecretName != cluster.Spec.ClientCertSecretRef {
+		if next, err := r.rotateOperatorClientCertificate(ctx, cluster, hcc, registered, logger); err != nil {
+			own.Message = fmt.Sprintf("still using %s: %v", own.SecretName, err)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "ClientCertificateRotationFailed", "%v", err)
+			}
+		} else {
+			hcc = next
+		}
+	} else {
+		own.Message = ""
+		// status came in naming clientCertSecretRef, so the switch is persisted and a restarted
+		// operator won't go back to the previous certificate: it can be revoked now.
+		if own.RevokeThumbprint != "" {
+			if err := hcc.DeleteCertificate(ctx, own.RevokeThumbprint); err != nil {
+				own.Message = "revoke previous client certificate: " + err.Error()
+			} else {
+				logger.Info("revoked previous operator client certificate", "thumbprint", own.RevokeThumbprint)
+				own.RevokeThumbprint = ""
+			}
+		}
+	}
+
+	cluster.Status.TrustedClientCertificates = r.reconcileTrustedClientCertificates(ctx, cluster, hcc, registered, logger)
+}
+
+// rotateOperatorClientCertificate registers the certificate of clientCertSecretRef, checks it
+// authenticates and switches status.clientCertificate over to it. It returns the checks
+// bound to the new certificate.
+func (r *RavenDBClusterReconciler) rotateOperatorClientCertificate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, registered []upgrade.RegisteredCertificate, logger logr.Logger) (*upgrade.HealthCheckContext, error) {
+	own := cluster.Status.ClientCertificate
+	secretName := cluster.Spec.ClientCertSecretRef
+
+	cert, err := upgrade.ClientCertificate(ctx, r.Client, cluster, secretName)
+	if err != nil {
+		return nil, err
+	}
+	thumbprint := certutil.Thumbprint(cert)
+
+	if findRegistered(registered, thumbprint) == nil {
+		if err := hcc.RegisterClientCertificate(ctx, secretName, cert, ravendbv1.ClearanceClusterAdmin, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	httpc, err := upgrade.BuildHTTPSClientWithClientCert(ctx, r.Client, cluster, secretName)
+	if err != nil {
+		return nil, err
+	}
+	next := upgrade.NewChecks(httpc, cluster)
+	if _, err := next.Certificates(ctx); err != nil {
+		return nil, fmt.Errorf("new client certificate %s isn't accepted yet: %w", thumbprint, err)
+	}
+
+	if own.Thumbprint != "" && own.Thumbprint != thumbprint {
+		own.RevokeThumbprint = own.Thumbprint
+	}
+	own.SecretName, own.Thumbprint, own.Message = secretName, thumbprint, ""
+
+	logger.Info("operator client certificate rotated", "secret", secretName, "thumbprint", thumbprint)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ClientCertificateRotated",
+			"the operator now authenticates with %s from secret %s", thumbprint, secretName)
+	}
+	return next, nil
+}
+
+func (r *RavenDBClusterReconciler) reconcileTrustedClientCertificates(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, registered []upgrade.RegisteredCertificate, logger logr.Logger) []ravendbv1.TrustedClientCertificateStatus {
+	var out []ravendbv1.TrustedClientCertificateStatus
+	desired := map[string]bool{}
+	prevByName := map[string]ravendbv1.TrustedClientCertificateStatus{}
+	for _, prev := range cluster.Status.TrustedClientCertificates {
+		prevByName[prev.Name] = prev
+	}
+
+	for _, t := range cluster.Spec.TrustedClientCertificates {
+		st := ravendbv1.TrustedClientCertificateStatus{Name: t.Name}
+
+		cert, err := r.readTrustedCertificate(ctx, cluster.Namespace, t)
+		if err != nil {
+			// an unreadable secret doesn't revoke what was registered from it
+			if prev, ok := prevByName[t.Name]; ok {
+				st.Thumbprint, st.Registered = prev.Thumbprint, prev.Registered
+				desired[prev.Thumbprint] = true
+			}
+			st.Error = err.Error()
+			out = append(out, st)
+			continue
+		}
+		st.Thumbprint = certutil.Thumbprint(cert)
+		desired[st.Thumbprint] = true
+
+		switch existing := findRegistered(registered, st.Thumbprint); {
+		case existing == nil:
+			err = hcc.RegisterClientCertificate(ctx, t.Name, cert, t.GetClearance(), t.Permissions)
+		case existing.Name != t.Name || existing.SecurityClearance != t.GetClearance() || !maps.Equal(existing.Permissions, t.Permissions):
+			err = hcc.EditClientCertificate(ctx, st.Thumbprint, t.Name, t.GetClearance(), t.Permissions)
+		}
+		if err != nil {
+			st.Error = err.Error()
+		} else {
+			st.Registered = true
+		}
+		out = append(out, st)
+	}
+
+	own := cluster.Status.ClientCertificate.Thumbprint
+	for _, prev := range cluster.Status.TrustedClientCertificates {
+		if !prev.Registered || prev.Thumbprint == "" || desired[prev.Thumbprint] || prev.Thumbprint == own {
+			continue
+		}
+		if err := hcc.DeleteCertificate(ctx, prev.Thumbprint); err != nil {
+			// keep it listed so the revocation is retried
+			out = append(out, ravendbv1.TrustedClientCertificateStatus{Name: prev.Name, Thumbprint: prev.Thumbprint, Registered: true, Error: err.Error()})
+			continue
+		}
+		logger.Info("revoked trusted client certificate", "name", prev.Name, "thumbprint", prev.Thumbprint)
+	}
+	return out
+}
+
+func (r *RavenDBClusterReconciler) readTrustedCertificate(ctx context.Context, namespace string, t ravendbv1.TrustedClientCertificate) (*x509.Certificate, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: t.SecretRef}, &secret); err != nil {
+		return nil, fmt.Errorf("get secret %q: %w", t.SecretRef, err)
+	}
+	data := secret.Data[t.GetKey()]
+	if len(data) == 0 {
+		return nil, fmt.Errorf("secret %q has no %s", t.SecretRef, t.GetKey())
+	}
+	cert, err := certutil.ParsePEMCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("secret %q: %w", t.SecretRef, err)
+	}
+	return cert, nil
+}
+
+func findRegistered(registered []upgrade.RegisteredCertificate, thumbprint string) *upgrade.RegisteredCertificate {
+	for i := range registered {
+		if registered[i].Thumbprint == thumbprint {
+			return &registered[i]
+		}
+	}
+	return nil
+}
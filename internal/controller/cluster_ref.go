@@ -0,0 +1,80 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// the resources below point at a RavenDBCluster through spec.clusterRef. the cluster (or its
+// API) not being there yet isn't an event we get notified about in all cases, so they poll.
+const requeueClusterRefWait = 30 * time.Second
+
+// clusterChecks returns the checks bound to the RavenDBCluster clusterRef names in namespace,
+// or why there are none yet: the cluster is missing, still bootstrapping or unreachable.
+func clusterChecks(ctx context.Context, c client.Client, namespace, clusterRef string) (*upgrade.HealthCheckContext, *ravendbv1.RavenDBCluster, string) {
+	var cluster ravendbv1.RavenDBCluster
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterRef}, &cluster); err != nil {
+		return nil, nil, fmt.Sprintf("cluster %q: %v", clusterRef, err)
+	}
+	if !cluster.IsBootstrapped() {
+		return nil, &cluster, fmt.Sprintf("waiting for cluster %q to bootstrap", cluster.Name)
+	}
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, c, &cluster)
+	if err != nil {
+		return nil, &cluster, fmt.Sprintf("cannot reach cluster %q: %v", cluster.Name, err)
+	}
+	return upgrade.NewChecks(httpc, &cluster), &cluster, ""
+}
+
+// requestsReferencing maps an object to the resources of list's kind whose field index
+// holds its name, in the same namespace.
+func requestsReferencing(c client.Client, newList func() client.ObjectList, index string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		list := newList()
+		if err := c.List(ctx, list,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{index: obj.GetName()},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list objects referencing object", "index", index, "name", obj.GetName())
+			return nil
+		}
+
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return nil
+		}
+		reqs := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			if o, ok := item.(client.Object); ok {
+				reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(o)})
+			}
+		}
+		return reqs
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// how long the nodes get to report an activated license before we restart them instead.
+const licenseActivationTimeout = 2 * time.Minute
+
+// reconcileLicenseUpdate brings a replaced license secret into the running cluster, which
+// otherwise only reads the license file when a node starts:
+//  1. a license.json id other than status.licenseUpdate.id is activated through
+//     /admin/license/activate,
+//  2. every node's /license/status is checked until it reports the new id,
+//  3. if the activation fails, or isn't confirmed within licenseActivationTimeout, the nodes
+//     are restarted one at a time by the upgrader, through its gates (see LicenseRestartId),
+//  4. if some node still doesn't report it licenseActivationTimeout after every node was
+//     restarted and ready again, the update Failed. Only another license secret starts over.
+//
+// It reports whether an update is in progress.
+func (r *RavenDBClusterReconciler) reconcileLicenseUpdate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) bool {
+	if cluster.Spec.LicenseSecretRef == "" {
+		cluster.Status.LicenseUpdate = nil
+		return false
+	}
+
+	st := cluster.Status.LicenseUpdate
+	inProgress := st != nil && !st.Done()
+
+	raw, id, err := r.readLicense(ctx, cluster)
+	if err != nil {
+		// LicensesValid reports it; an update already started carries on with what it has
+		if inProgress {
+			st.Message = err.Error()
+		}
+		return inProgress
+	}
+
+	if st == nil || !cluster.IsBootstrapped() {
+		cluster.Status.LicenseUpdate = &ravendbv1.LicenseUpdateStatus{Id: id, Phase: ravendbv1.LicenseUpdateCompleted}
+		return false
+	}
+
+	now := metav1.Now()
+	started := false
+	if st.Id != id {
+		st = &ravendbv1.LicenseUpdateStatus{Id: id, Phase: ravendbv1.LicenseUpdateInProgress, StartedAt: &now}
+		cluster.Status.LicenseUpdate = st
+		started = true
+		logger.Info("license secret changed", "id", id)
+	}
+	if st.Done() {
+		return false
+	}
+
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, cluster)
+	if err != nil {
+		st.Message = "cannot reach the cluster: " + err.Error()
+		return true
+	}
+	return r.advanceLicenseUpdate(ctx, cluster, upgrade.NewChecks(httpc, cluster), st, raw, started, logger, now)
+}
+
+// advanceLicenseUpdate takes the update of st a step further and reports whether it's still in
+// progress. started is set when the license secret just changed, raw being its license.json.
+func (r *RavenDBClusterReconciler) advanceLicenseUpdate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, st *ravendbv1.LicenseUpdateStatus, raw []byte, started bool, logger logr.Logger, now metav1.Time) bool {
+	// the nodes may already run it, e.g. restarted by rollOnSecretChange
+	if r.confirmLicense(ctx, cluster, hcc, st) {
+		r.licenseUpdated(cluster, st, logger, now)
+		return false
+	}
+
+	switch {
+	case started:
+		if err := hcc.ActivateLicense(ctx, raw); err != nil {
+			r.restartForLicense(cluster, st, logger, err.Error())
+			return true
+		}
+		st.Message = "license activated, waiting for every node to report it"
+		logger.Info("license activated through the API", "id", st.Id)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "LicenseActivated", "license %s activated, waiting for every node to report it", st.Id)
+		}
+
+	case st.Phase == ravendbv1.LicenseUpdateInProgress && st.StartedAt != nil && now.Sub(st.StartedAt.Time) > licenseActivationTimeout:
+		r.restartForLicense(cluster, st, logger, fmt.Sprintf("not reported by every node within %s", licenseActivationTimeout))
+
+	case st.Phase == ravendbv1.LicenseUpdateRestarting:
+		restarted, err := r.licenseRestarted(ctx, cluster, st.Id)
+		if err != nil {
+			st.Message = "cannot read the nodes' StatefulSets: " + err.Error()
+			return true
+		}
+		switch {
+		case !restarted:
+			st.RestartedAt = nil
+		case st.RestartedAt == nil:
+			st.RestartedAt = &now
+		case now.Sub(st.RestartedAt.Time) > licenseActivationTimeout:
+			r.licenseUpdateFailed(cluster, st, logger)
+			return false
+		}
+	}
+	return true
+}
+
+// licenseRestarted reports whether every node's StatefulSet was restarted to load license id
+// and its pod is ready again.
+func (r *RavenDBClusterReconciler) licenseRestarted(ctx context.Context, cluster *ravendbv1.RavenDBCluster, id string) (bool, error) {
+	for _, n := range cluster.Spec.Nodes {
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: common.Prefix + n.Tag}, &sts); err != nil {
+			return false, client.IgnoreNotFound(err)
+		}
+		if sts.Spec.Template.Annotations[common.LicenseRestartAnnotation] != id {
+			return false, nil
+		}
+		replicas := int32(1)
+		if sts.Spec.Replicas != nil {
+			replicas = *sts.Spec.Replicas
+		}
+		if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdatedReplicas != replicas || sts.Status.ReadyReplicas != replicas {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// confirmLicense records the license each node runs with and reports whether all run st.Id.
+func (r *RavenDBClusterReconciler) confirmLicense(ctx context.Context, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext, st *ravendbv1.LicenseUpdateStatus) bool {
+	all := true
+	st.Nodes = make([]ravendbv1.NodeLicenseStatus, 0, len(cluster.Spec.Nodes))
+	for _, node := range cluster.Spec.Nodes {
+		ns := ravendbv1.NodeLicenseStatus{Tag: node.Tag}
+		if lic, err := hcc.LicenseStatus(ctx, node.Tag); err != nil {
+			ns.Error = err.Error()
+		} else {
+			ns.ActiveId = lic.Id
+			ns.Confirmed = strings.EqualFold(lic.Id, st.Id)
+		}
+		all = all && ns.Confirmed
+		st.Nodes = append(st.Nodes, ns)
+	}
+	return all
+}
+
+func (r *RavenDBClusterReconciler) licenseUpdated(cluster *ravendbv1.RavenDBCluster, st *ravendbv1.LicenseUpdateStatus, logger logr.Logger, now metav1.Time) {
+	st.Phase = ravendbv1.LicenseUpdateCompleted
+	st.Message = ""
+	st.CompletedAt = &now
+	logger.Info("license updated on every node", "id", st.Id)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "LicenseUpdated", "every node runs license %s", st.Id)
+	}
+}
+
+func (r *RavenDBClusterReconciler) restartForLicense(cluster *ravendbv1.RavenDBCluster, st *ravendbv1.LicenseUpdateStatus, logger logr.Logger, reason string) {
+	st.Phase = ravendbv1.LicenseUpdateRestarting
+	st.Message = "restarting the nodes to load the license: " + reason
+	logger.Info("license not applied through the API, restarting the nodes", "id", st.Id, "reason", reason)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "LicenseActivationFailed", "license %s: %s; restarting the nodes one at a time", st.Id, reason)
+	}
+}
+
+func (r *RavenDBClusterReconciler) licenseUpdateFailed(cluster *ravendbv1.RavenDBCluster, st *ravendbv1.LicenseUpdateStatus, logger logr.Logger) {
+	var unconfirmed []string
+	for _, n := range st.Nodes {
+		if !n.Confirmed {
+			unconfirmed = append(unconfirmed, n.Tag)
+		}
+	}
+	nodes := strings.Join(unconfirmed, ", ")
+
+	st.Phase = ravendbv1.LicenseUpdateFailed
+	st.Message = fmt.Sprintf("node %s still doesn't run the license after every node was restarted", nodes)
+	logger.Info("license not loaded after restarting every node", "id", st.Id, "nodes", nodes)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "LicenseUpdateFailed", "license %s: node %s still doesn't run it after every node was restarted", st.Id, nodes)
+	}
+}
+
+// readLicense returns license.json of licenseSecretRef and its id.
+func (r *RavenDBClusterReconciler) readLicense(ctx context.Context, cluster *ravendbv1.RavenDBCluster) ([]byte, string, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Spec.LicenseSecretRef}, &secret); err != nil {
+		return nil, "", fmt.Errorf("get license secret %q: %w", cluster.Spec.LicenseSecretRef, err)
+	}
+
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		if strings.HasSuffix(k, ".json") {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, "", fmt.Errorf("secret %q has no .json key", secret.Name)
+	}
+	sort.Strings(keys)
+
+	raw := secret.Data[keys[0]]
+	lic, err := upgrade.ParseLicense(raw)
+	if err != nil {
+		return nil, "", fmt.Errorf("secret %q: %w", secret.Name, err)
+	}
+	if lic.Id == "" {
+		return nil, "", fmt.Errorf("secret %q: license has no Id", secret.Name)
+	}
+	return raw, lic.Id, nil
+}
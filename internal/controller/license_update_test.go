@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+// restartedNode is the StatefulSet of a node restarted to load license id, ready again.
+func restartedNode(tag, id string) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: common.Prefix + tag, Namespace: "ravendb", Generation: 2},
+		Spec: appsv1.StatefulSetSpec{
+			Template: corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{common.LicenseRestartAnnotation: id}}},
+		},
+		Status: appsv1.StatefulSetStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, ReadyReplicas: 1},
+	}
+}
+
+func restartingLicense(restartedAt *metav1.Time) *ravendbv1.RavenDBCluster {
+	return &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBClusterSpec{Nodes: []ravendbv1.RavenDBNode{{Tag: "A"}, {Tag: "B"}}},
+		Status: ravendbv1.RavenDBClusterStatus{LicenseUpdate: &ravendbv1.LicenseUpdateStatus{
+			Id: "new", Phase: ravendbv1.LicenseUpdateRestarting, RestartedAt: restartedAt,
+		}},
+	}
+}
+
+func TestAdvanceLicenseUpdate_FailsWhenRestartedNodesDontRunTheLicense(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Id":"old"}`))
+	}))
+	r := &RavenDBClusterReconciler{Client: fakeClient(t, restartedNode("A", "new"), restartedNode("B", "new"))}
+	now := metav1.Now()
+
+	cluster := restartingLicense(nil)
+	st := cluster.Status.LicenseUpdate
+	require.True(t, r.advanceLicenseUpdate(context.Background(), cluster, hcc, st, nil, false, logr.Discard(), now))
+	require.Equal(t, ravendbv1.LicenseUpdateRestarting, st.Phase, "the nodes get time to report it")
+	require.NotNil(t, st.RestartedAt)
+
+	later := metav1.NewTime(now.Add(licenseActivationTimeout + time.Second))
+	require.False(t, r.advanceLicenseUpdate(context.Background(), cluster, hcc, st, nil, false, logr.Discard(), later))
+	require.Equal(t, ravendbv1.LicenseUpdateFailed, st.Phase)
+	require.Contains(t, st.Message, "node A, B")
+	require.Empty(t, cluster.LicenseRestartId(), "a failed update restarts nothing more")
+}
+
+func TestAdvanceLicenseUpdate_WaitsForEveryNodeToRestart(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"Id":"old"}`))
+	}))
+	pending := restartedNode("B", "new")
+	pending.Status.ReadyReplicas = 0
+	r := &RavenDBClusterReconciler{Client: fakeClient(t, restartedNode("A", "new"), pending)}
+
+	long := metav1.NewTime(time.Now().Add(-time.Hour))
+	cluster := restartingLicense(&long)
+	st := cluster.Status.LicenseUpdate
+	require.True(t, r.advanceLicenseUpdate(context.Background(), cluster, hcc, st, nil, false, logr.Discard(), metav1.Now()))
+	require.Equal(t, ravendbv1.LicenseUpdateRestarting, st.Phase)
+	require.Nil(t, st.RestartedAt, "a node restarting again starts the wait over")
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/metrics"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// mirrors the evaluated status into the per-cluster gauges. nodes listed in previous, the
+// status before this reconcile, but no longer in it were dropped from the spec and lose
+// their series.
+func recordClusterMetrics(cluster *ravendbv1.RavenDBCluster, previous []ravendbv1.RavenDBNodeStatus) {
+	ns, name := cluster.Namespace, cluster.Name
+
+	for _, c := range cluster.Status.Conditions {
+		metrics.SetCondition(ns, name, c.Type, string(c.Status))
+	}
+
+	for _, n := range cluster.Status.Nodes {
+		metrics.SetNodeUpgradeState(ns, name, n.Tag, string(n.Status))
+	}
+	for _, p := range previous {
+		if !slices.ContainsFunc(cluster.Status.Nodes, func(n ravendbv1.RavenDBNodeStatus) bool { return strings.EqualFold(n.Tag, p.Tag) }) {
+			metrics.ForgetNode(ns, name, p.Tag)
+		}
+	}
+
+	if c, ok := cluster.GetCondition(ravendbv1.ConditionBootstrapCompleted); ok && c.Status == metav1.ConditionTrue {
+		metrics.SetBootstrapDuration(ns, name, c.LastTransitionTime.Sub(cluster.CreationTimestamp.Time))
+	}
+}
+
+func reconcileOutcome(res ctrl.Result, err error) string {
+	switch {
+	case err != nil:
+		return metrics.OutcomeError
+	case res.Requeue || res.RequeueAfter > 0:
+		return metrics.OutcomeRequeue
+	}
+	return metrics.OutcomeSuccess
+}
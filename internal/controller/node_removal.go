@@ -0,0 +1,105 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// removeDroppedNodes takes the nodes dropped from spec.nodes, whose StatefulSet the cluster still
+// controls, out of the RavenDB cluster. It then stops counting them towards its quorum before
+// their StatefulSet and Service are pruned. It returns the dropped tags the cluster still has:
+// their objects are kept until a later reconcile sees them gone. Without the cluster's topology
+// it can't tell, and returns an error.
+func (r *RavenDBClusterReconciler) removeDroppedNodes(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) ([]string, error) {
+	if !cluster.IsBootstrapped() {
+		return nil, nil
+	}
+
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	hcc := upgrade.NewChecks(httpc, cluster)
+
+	var tags []string
+	for _, n := range cluster.Spec.Nodes {
+		tags = append(tags, n.Tag)
+	}
+	topo, err := hcc.ClusterTopology(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, tag := range droppedNodes(tags, topo.Nodes()) {
+		// only nodes the operator ran are its to remove
+		var sts appsv1.StatefulSet
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: common.Prefix + strings.ToLower(tag)}, &sts); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !metav1.IsControlledBy(&sts, cluster) {
+			continue
+		}
+		dropped = append(dropped, tag)
+
+		if r.PruneDryRun {
+			logger.Info("dry-run: would remove node dropped from the spec from the cluster", "tag", tag)
+			continue
+		}
+		if err := hcc.RemoveClusterNode(ctx, tag); err != nil {
+			logger.Error(err, "failed to remove node from the cluster", "tag", tag)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "NodeRemovalFailed", "%v", err)
+			}
+			continue
+		}
+		logger.Info("removed node dropped from the spec from the cluster", "tag", tag)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "NodeRemoved", "removed node %s from the RavenDB cluster, its resources go next", tag)
+		}
+	}
+	return dropped, nil
+}
+
+// droppedNodes are the nodes of the cluster topology that aren't in the spec.
+func droppedNodes(specTags, clusterTags []string) []string {
+	var out []string
+	for _, tag := range clusterTags {
+		if !slices.ContainsFunc(specTags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			out = append(out, tag)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+/*
+The RavenDBBackup flow
+---
+1) wait for the referenced cluster to bootstrap and for the database to exist.
+2) build the task from the spec and the destination's credentials secret. it's sent to RavenDB
+   when it's new, when RavenDB lost it, or when the spec or credentials changed since
+   (status.configurationHash). status.taskName is saved before the task is created, so a task
+   of that name found later is ours; one of the same name created otherwise is refused.
+3) poll the task's backup status: the last full and incremental backups, and the last error.
+   a backup that failed after the last successful one makes the phase Failed.
+4) on delete a finalizer removes the task. the backups stay where they are.
+*/
+
+// backups run on a cron schedule RavenDB keeps; this is how often we look at how they went
+const requeueBackupStatus = time.Minute
+
+// RavenDBBackupReconciler reconciles a RavenDBBackup object
+type RavenDBBackupReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbbackups,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbbackups/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbbackups/finalizers,verbs=update
+func (r *RavenDBBackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var b ravendbv1.RavenDBBackup
+	if err := r.Get(ctx, req.NamespacedName, &b); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !b.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &b)
+	}
+
+	if controllerutil.AddFinalizer(&b, common.BackupFinalizer) {
+		if err := r.Update(ctx, &b); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := b.DeepCopy()
+	result, err := r.reconcileBackup(ctx, &b)
+	if err != nil {
+		logger.Error(err, "backup reconcile failed")
+		b.Status.Phase = ravendbv1.BackupFailed
+		b.Status.Message = err.Error()
+		r.event(&b, corev1.EventTypeWarning, "BackupTaskFailed", "%v", err)
+	}
+	b.Status.ObservedGeneration = b.Generation
+
+	if !reflect.DeepEqual(original.Status, b.Status) {
+		if perr := r.Status().Patch(ctx, &b, client.MergeFrom(original)); perr != nil {
+			if kerrors.IsConflict(perr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, perr
+		}
+	}
+	return result, err
+}
+
+func (r *RavenDBBackupReconciler) reconcileBackup(ctx context.Context, b *ravendbv1.RavenDBBackup) (ctrl.Result, error) {
+	hcc, _, waiting := clusterChecks(ctx, r.Client, b.Namespace, b.Spec.ClusterRef)
+	if hcc == nil {
+		b.Status.Phase = ravendbv1.BackupPending
+		b.Status.Message = waiting
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+	return r.syncBackup(ctx, b, hcc)
+}
+
+// syncBackup keeps the backup task of b in the cluster hcc talks to.
+func (r *RavenDBBackupReconciler) syncBackup(ctx context.Context, b *ravendbv1.RavenDBBackup, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	database := b.Spec.DatabaseName
+	rec, err := hcc.DatabaseRecord(ctx, database)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if rec == nil {
+		b.Status.Phase = ravendbv1.BackupPending
+		b.Status.Message = fmt.Sprintf("waiting for database %q", database)
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+
+	settings, err := backupSettings(ctx, r.Client, b.Namespace, b.Spec.Destination)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	conf := upgrade.PeriodicBackupConfiguration{
+		Name:                       b.GetTaskName(),
+		Disabled:                   b.Spec.Disabled,
+		BackupType:                 string(b.GetType()),
+		FullBackupFrequency:        b.Spec.FullBackupFrequency,
+		IncrementalBackupFrequency: b.Spec.IncrementalBackupFrequency,
+		RetentionPolicy:            upgrade.RetentionPolicy{Disabled: true},
+		BackupSettings:             settings,
+	}
+	if ret := b.Spec.Retention; ret != nil {
+		conf.RetentionPolicy = upgrade.RetentionPolicy{MinimumBackupAgeToKeep: upgrade.TimeSpan(ret.MinimumBackupAge.Duration)}
+	}
+	hash := configurationHash(conf)
+
+	tasks, err := hcc.BackupTasks(ctx, database)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	taskId := b.Status.TaskId
+	if _, ok := tasks[taskId]; !ok {
+		taskId = 0
+		for id, name := range tasks {
+			if name == conf.Name {
+				taskId = id
+				break
+			}
+		}
+		switch {
+		case taskId != 0 && b.Status.TaskName != conf.Name:
+			// the finalizer would delete a task someone else set up
+			b.Status.Phase = ravendbv1.BackupFailed
+			b.Status.Message = fmt.Sprintf("database %q already has a backup task named %q that this RavenDBBackup didn't create; set spec.taskName to another name", database, conf.Name)
+			r.event(b, corev1.EventTypeWarning, "BackupTaskExists", b.Status.Message)
+			return ctrl.Result{}, nil
+		case taskId == 0 && b.Status.TaskName != conf.Name:
+			// saved first: a task created by a request whose answer got lost is then still ours
+			b.Status.TaskName = conf.Name
+			b.Status.Phase = ravendbv1.BackupPending
+			b.Status.Message = "creating the backup task"
+			return ctrl.Result{Requeue: true}, nil
+		}
+	}
+
+	if taskId == 0 || taskId != b.Status.TaskId || hash != b.Status.ConfigurationHash {
+		conf.TaskId = taskId
+		id, err := hcc.PutPeriodicBackup(ctx, database, conf)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		b.Status.TaskId = id
+		b.Status.TaskName = conf.Name
+		b.Status.ConfigurationHash = hash
+		r.event(b, corev1.EventTypeNormal, "BackupTaskConfigured", "configured backup task %q (id %d) of database %q", conf.Name, id, database)
+	}
+
+	st, err := hcc.PeriodicBackupStatus(ctx, database, b.Status.TaskId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	previousError := b.Status.LastErrorTime
+	b.Status.LastFullBackup = metaTime(st.LastFullBackup)
+	b.Status.LastIncrementalBackup = metaTime(st.LastIncrementalBackup)
+	b.Status.LastSuccessTime = b.Status.LastFullBackup
+	if inc := b.Status.LastIncrementalBackup; inc != nil && (b.Status.LastSuccessTime == nil || b.Status.LastSuccessTime.Before(inc)) {
+		b.Status.LastSuccessTime = inc
+	}
+	b.Status.LastError = st.LastError
+	b.Status.LastErrorTime = metaTime(st.LastErrorTime)
+
+	if b.Status.Failing() {
+		b.Status.Phase = ravendbv1.BackupFailed
+		b.Status.Message = "last backup failed: " + b.Status.LastError
+		if previousError == nil || !previousError.Equal(b.Status.LastErrorTime) {
+			r.event(b, corev1.EventTypeWarning, "BackupFailed", "backup of database %q failed: %s", database, b.Status.LastError)
+		}
+		return ctrl.Result{RequeueAfter: requeueBackupStatus}, nil
+	}
+
+	b.Status.Phase = ravendbv1.BackupReady
+	b.Status.Message = ""
+	return ctrl.Result{RequeueAfter: requeueBackupStatus}, nil
+}
+
+func (r *RavenDBBackupReconciler) finalize(ctx context.Context, b *ravendbv1.RavenDBBackup) error {
+	if !controllerutil.ContainsFinalizer(b, common.BackupFinalizer) {
+		return nil
+	}
+
+	if b.Status.TaskId != 0 {
+		var cluster ravendbv1.RavenDBCluster
+		err := r.Get(ctx, client.ObjectKey{Namespace: b.Namespace, Name: b.Spec.ClusterRef}, &cluster)
+		switch {
+		case kerrors.IsNotFound(err):
+			// nothing left to remove it from
+		case err != nil:
+			return err
+		case cluster.DeletionTimestamp.IsZero():
+			httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, &cluster)
+			if err != nil {
+				return err
+			}
+			if err := upgrade.NewChecks(httpc, &cluster).DeleteBackupTask(ctx, b.Spec.DatabaseName, b.Status.TaskId); err != nil {
+				r.event(b, corev1.EventTypeWarning, "BackupTaskDeleteFailed", "%v", err)
+				return err
+			}
+			log.FromContext(ctx).Info("deleted backup task", "database", b.Spec.DatabaseName, "taskId", b.Status.TaskId)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(b, common.BackupFinalizer)
+	return r.Update(ctx, b)
+}
+
+func (r *RavenDBBackupReconciler) event(b *ravendbv1.RavenDBBackup, eventType, reason, format string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(b, eventType, reason, format, args...)
+	}
+}
+
+func metaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	mt := metav1.NewTime(*t)
+	return &mt
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBBackupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+
+	ctx := context.Background()
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &ravendbv1.RavenDBBackup{}, common.ClusterRefIndex, func(obj client.Object) []string {
+		return []string{obj.(*ravendbv1.RavenDBBackup).Spec.ClusterRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &ravendbv1.RavenDBBackup{}, common.SecretRefsIndex, func(obj client.Object) []string {
+		if ref := obj.(*ravendbv1.RavenDBBackup).Spec.Destination.CredentialsSecretRef(); ref != "" {
+			return []string{ref}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBBackup{}).
+		Watches(&ravendbv1.RavenDBCluster{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newBackupList, common.ClusterRefIndex))).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newBackupList, common.SecretRefsIndex))).
+		Complete(r)
+}
+
+func newBackupList() client.ObjectList { return &ravendbv1.RavenDBBackupList{} }
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// backupServer serves a database Orders whose backup tasks are tasks, and counts the
+// backup tasks it was sent.
+func backupServer(t *testing.T, tasks string, puts *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/admin/periodic-backup"):
+			*puts++
+			_, _ = w.Write([]byte(`{"TaskId":9}`))
+		case req.Method != http.MethodGet:
+			t.Errorf("unexpected %s %s", req.Method, req.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		case req.URL.Path == "/admin/databases":
+			existingDatabase("Normal")(w, req)
+		case strings.HasSuffix(req.URL.Path, "/tasks"):
+			_, _ = w.Write([]byte(`{"OngoingTasksList":[` + tasks + `]}`))
+		case strings.HasSuffix(req.URL.Path, "/periodic-backup/status"):
+			_, _ = w.Write([]byte(`{"Status":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func testBackup() *ravendbv1.RavenDBBackup {
+	return &ravendbv1.RavenDBBackup{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-nightly", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBBackupSpec{
+			ClusterRef:          "raven",
+			DatabaseName:        "Orders",
+			FullBackupFrequency: "0 2 * * *",
+			Destination:         ravendbv1.BackupDestination{Local: &ravendbv1.LocalBackupDestination{FolderPath: "/backups/orders"}},
+		},
+	}
+}
+
+func TestSyncBackup_RefusesATaskItDidNotCreate(t *testing.T) {
+	puts := 0
+	hcc := fakeRavenDB(t, backupServer(t, `{"TaskId":7,"TaskName":"orders-nightly","TaskType":"Backup"}`, &puts))
+	b := testBackup()
+
+	r := &RavenDBBackupReconciler{Client: fakeClient(t)}
+	_, err := r.syncBackup(context.Background(), b, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.BackupFailed, b.Status.Phase)
+	require.Contains(t, b.Status.Message, "spec.taskName")
+	require.Zero(t, b.Status.TaskId, "the finalizer must not delete a task that isn't ours")
+	require.Zero(t, puts)
+}
+
+func TestSyncBackup_SavesTheTaskNameBeforeCreating(t *testing.T) {
+	puts := 0
+	hcc := fakeRavenDB(t, backupServer(t, ``, &puts))
+	b := testBackup()
+	r := &RavenDBBackupReconciler{Client: fakeClient(t)}
+
+	result, err := r.syncBackup(context.Background(), b, hcc)
+	require.NoError(t, err)
+	require.True(t, result.Requeue)
+	require.Equal(t, "orders-nightly", b.Status.TaskName)
+	require.Zero(t, puts)
+
+	_, err = r.syncBackup(context.Background(), b, hcc)
+	require.NoError(t, err)
+	require.Equal(t, 1, puts)
+	require.Equal(t, int64(9), b.Status.TaskId)
+	require.Equal(t, ravendbv1.BackupReady, b.Status.Phase)
+}
+
+func TestSyncBackup_TakesBackItsOwnTaskWhenTheIdWasLost(t *testing.T) {
+	puts := 0
+	hcc := fakeRavenDB(t, backupServer(t, `{"TaskId":7,"TaskName":"orders-nightly","TaskType":"Backup"}`, &puts))
+	b := testBackup()
+	b.Status.TaskName = "orders-nightly"
+
+	_, err := (&RavenDBBackupReconciler{Client: fakeClient(t)}).syncBackup(context.Background(), b, hcc)
+	require.NoError(t, err)
+	require.Equal(t, 1, puts, "the task is configured from the spec")
+	require.Equal(t, ravendbv1.BackupReady, b.Status.Phase)
+}
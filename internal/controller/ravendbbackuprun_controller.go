@@ -0,0 +1,323 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+/*
+The RavenDBBackupRun flow
+---
+1) the run is made owned by its cluster, so it goes away with it.
+2) once the cluster is bootstrapped, the start time and a Pending status per database are saved first,
+   so a run is never started twice. then a one-time backup of every database is started to the destination.
+   a database that doesn't exist, or refuses, fails on its own; the others go on. a database whose
+   record can't be read stays Pending and is retried.
+3) poll each backup operation on the node running it until it completed or faulted. the run ends
+   Succeeded when every database did, Failed otherwise. it's never started again.
+4) a finished run deletes the oldest finished runs owned by the same cluster beyond historyLimit.
+*/
+
+// RavenDBBackupRunReconciler reconciles a RavenDBBackupRun object
+type RavenDBBackupRunReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbbackupruns,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbbackupruns/status,verbs=get;update;patch
+func (r *RavenDBBackupRunReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var run ravendbv1.RavenDBBackupRun
+	if err := r.Get(ctx, req.NamespacedName, &run); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !run.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if run.Status.Done() {
+		return ctrl.Result{}, r.pruneHistory(ctx, &run)
+	}
+
+	if err := r.adopt(ctx, &run); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	original := run.DeepCopy()
+	result, err := r.reconcileRun(ctx, &run)
+	if err != nil {
+		logger.Error(err, "backup run reconcile failed")
+		run.Status.Message = err.Error()
+		r.event(&run, corev1.EventTypeWarning, "BackupRunError", "%v", err)
+	}
+	run.Status.ObservedGeneration = run.Generation
+
+	if !reflect.DeepEqual(original.Status, run.Status) {
+		if perr := r.Status().Patch(ctx, &run, client.MergeFrom(original)); perr != nil {
+			if kerrors.IsConflict(perr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, perr
+		}
+	}
+	if err == nil && run.Status.Done() {
+		return result, r.pruneHistory(ctx, &run)
+	}
+	return result, err
+}
+
+// adopt makes the cluster an owner of the run. A cluster that isn't there yet adopts it later.
+func (r *RavenDBBackupRunReconciler) adopt(ctx context.Context, run *ravendbv1.RavenDBBackupRun) error {
+	if clusterOwner(run) != "" {
+		return nil
+	}
+	var cluster ravendbv1.RavenDBCluster
+	if err := r.Get(ctx, client.ObjectKey{Namespace: run.Namespace, Name: run.Spec.ClusterRef}, &cluster); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if err := controllerutil.SetOwnerReference(&cluster, run, r.Scheme); err != nil {
+		return err
+	}
+	return r.Update(ctx, run)
+}
+
+func (r *RavenDBBackupRunReconciler) reconcileRun(ctx context.Context, run *ravendbv1.RavenDBBackupRun) (ctrl.Result, error) {
+	hcc, _, waiting := clusterChecks(ctx, r.Client, run.Namespace, run.Spec.ClusterRef)
+	if hcc == nil {
+		run.Status.Phase = ravendbv1.BackupRunPending
+		run.Status.Message = waiting
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+	return r.runBackups(ctx, run, hcc)
+}
+
+// runBackups saves the databases to back up on the first pass, then starts their backups and
+// polls them until every database finished.
+func (r *RavenDBBackupRunReconciler) runBackups(ctx context.Context, run *ravendbv1.RavenDBBackupRun, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	if run.Status.StartTime == nil {
+		// saved before any backup is started, so a run whose status wasn't written never
+		// starts its backups twice
+		now := metav1.Now()
+		run.Status.StartTime = &now
+		run.Status.Databases = nil
+		for _, name := range run.Spec.Databases {
+			run.Status.Databases = append(run.Status.Databases, ravendbv1.DatabaseBackupRunStatus{Name: name, Phase: ravendbv1.BackupRunPending})
+		}
+		run.Status.Phase = ravendbv1.BackupRunRunning
+		run.Status.Message = "starting the backups"
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	if err := r.startBackups(ctx, run, hcc); err != nil {
+		return ctrl.Result{}, err
+	}
+	for i := range run.Status.Databases {
+		if err := r.pollBackup(ctx, &run.Status.Databases[i], hcc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	switch outcome := run.Status.Outcome(); outcome {
+	case ravendbv1.BackupRunRunning:
+		run.Status.Phase = ravendbv1.BackupRunRunning
+		run.Status.Message = ""
+		return ctrl.Result{RequeueAfter: requeueOperationPoll}, nil
+	default:
+		now := metav1.Now()
+		run.Status.Phase = outcome
+		run.Status.CompletionTime = &now
+		if outcome == ravendbv1.BackupRunFailed {
+			run.Status.Message = "failed: " + strings.Join(failedDatabases(run.Status.Databases), ", ")
+			r.event(run, corev1.EventTypeWarning, "BackupRunFailed", "backup of %s failed", strings.Join(failedDatabases(run.Status.Databases), ", "))
+		} else {
+			run.Status.Message = ""
+			r.event(run, corev1.EventTypeNormal, "BackupRunSucceeded", "backed up %s", strings.Join(run.Spec.Databases, ", "))
+		}
+		return ctrl.Result{}, nil
+	}
+}
+
+// startBackups starts the backup of every database still Pending. A database record that can't
+// be read is returned as an error, so the reconcile retries it.
+func (r *RavenDBBackupRunReconciler) startBackups(ctx context.Context, run *ravendbv1.RavenDBBackupRun, hcc *upgrade.HealthCheckContext) error {
+	var settings *upgrade.BackupSettings
+	for i := range run.Status.Databases {
+		st := &run.Status.Databases[i]
+		if st.Phase != ravendbv1.BackupRunPending {
+			continue
+		}
+		if settings == nil {
+			s, err := backupSettings(ctx, r.Client, run.Namespace, run.Spec.Destination)
+			if err != nil {
+				return err
+			}
+			settings = &s
+		}
+
+		rec, err := hcc.DatabaseRecord(ctx, st.Name)
+		if err != nil {
+			return err
+		}
+		if rec == nil {
+			st.Phase, st.Message = ravendbv1.BackupRunFailed, "no such database"
+		} else if id, tag, err := hcc.StartBackup(ctx, st.Name, string(run.GetType()), *settings); err != nil {
+			st.Phase, st.Message = ravendbv1.BackupRunFailed, err.Error()
+		} else {
+			st.Phase, st.OperationId, st.NodeTag = ravendbv1.BackupRunRunning, id, tag
+			r.event(run, corev1.EventTypeNormal, "BackupStarted", "backing up database %q on node %s (operation %d)", st.Name, tag, id)
+		}
+	}
+	return nil
+}
+
+func (r *RavenDBBackupRunReconciler) pollBackup(ctx context.Context, st *ravendbv1.DatabaseBackupRunStatus, hcc *upgrade.HealthCheckContext) error {
+	if st.Phase != ravendbv1.BackupRunRunning {
+		return nil
+	}
+	op, err := hcc.OperationState(ctx, st.NodeTag, st.Name, st.OperationId)
+	if err != nil {
+		return err
+	}
+	if op == nil {
+		st.Phase, st.Message = ravendbv1.BackupRunFailed, fmt.Sprintf("node %s lost track of operation %d", st.NodeTag, st.OperationId)
+		return nil
+	}
+
+	if op.Message != "" {
+		st.Message = op.Message
+	}
+	switch op.Status {
+	case upgrade.OperationCompleted:
+		st.Phase = ravendbv1.BackupRunSucceeded
+	case upgrade.OperationFaulted, upgrade.OperationCanceled:
+		st.Phase = ravendbv1.BackupRunFailed
+		st.Message = strings.ToLower(op.Status) + ": " + op.Message
+	}
+	return nil
+}
+
+// pruneHistory deletes the oldest finished runs owned by the same cluster, keeping the run's
+// historyLimit of them.
+func (r *RavenDBBackupRunReconciler) pruneHistory(ctx context.Context, run *ravendbv1.RavenDBBackupRun) error {
+	owner := clusterOwner(run)
+	if owner == "" {
+		return nil
+	}
+
+	var list ravendbv1.RavenDBBackupRunList
+	if err := r.List(ctx, &list,
+		client.InNamespace(run.Namespace),
+		client.MatchingFields{common.ClusterRefIndex: run.Spec.ClusterRef},
+	); err != nil {
+		return err
+	}
+
+	var finished []ravendbv1.RavenDBBackupRun
+	for _, item := range list.Items {
+		if item.Status.Done() && item.DeletionTimestamp.IsZero() && clusterOwner(&item) == owner {
+			finished = append(finished, item)
+		}
+	}
+	if len(finished) <= run.GetHistoryLimit() {
+		return nil
+	}
+
+	sort.Slice(finished, func(i, j int) bool {
+		return finishedAt(&finished[i]).After(finishedAt(&finished[j]).Time)
+	})
+	for i := range finished[run.GetHistoryLimit():] {
+		old := &finished[run.GetHistoryLimit()+i]
+		if err := r.Delete(ctx, old); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		log.FromContext(ctx).Info("deleted old backup run", "name", old.Name)
+	}
+	return nil
+}
+
+func (r *RavenDBBackupRunReconciler) event(run *ravendbv1.RavenDBBackupRun, eventType, reason, format string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(run, eventType, reason, format, args...)
+	}
+}
+
+func clusterOwner(run *ravendbv1.RavenDBBackupRun) types.UID {
+	for _, ref := range run.OwnerReferences {
+		if ref.Kind == "RavenDBCluster" && ref.Name == run.Spec.ClusterRef {
+			return ref.UID
+		}
+	}
+	return ""
+}
+
+func finishedAt(run *ravendbv1.RavenDBBackupRun) metav1.Time {
+	if run.Status.CompletionTime != nil {
+		return *run.Status.CompletionTime
+	}
+	return run.CreationTimestamp
+}
+
+func failedDatabases(dbs []ravendbv1.DatabaseBackupRunStatus) []string {
+	var out []string
+	for _, db := range dbs {
+		if db.Phase == ravendbv1.BackupRunFailed {
+			out = append(out, db.Name)
+		}
+	}
+	return out
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBBackupRunReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ravendbv1.RavenDBBackupRun{}, common.ClusterRefIndex, func(obj client.Object) []string {
+		return []string{obj.(*ravendbv1.RavenDBBackupRun).Spec.ClusterRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBBackupRun{}).
+		Watches(&ravendbv1.RavenDBCluster{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newBackupRunList, common.ClusterRefIndex))).
+		Complete(r)
+}
+
+func newBackupRunList() client.ObjectList { return &ravendbv1.RavenDBBackupRunList{} }
@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// backupRunServer serves a database Orders whose backup runs as operation 5 on node A, in
+// the state the operation returns. Any other database doesn't exist.
+func backupRunServer(t *testing.T, starts *int, operation *string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		switch {
+		case *operation == "unavailable":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case req.Method == http.MethodPost && req.URL.Path == "/databases/Orders/admin/backup":
+			*starts++
+			_, _ = w.Write([]byte(`{"OperationId":5,"ResponsibleNode":"A"}`))
+		case req.Method != http.MethodGet:
+			t.Errorf("unexpected %s %s", req.Method, req.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		case req.URL.Path == "/admin/databases" && req.URL.Query().Get("name") == "Orders":
+			existingDatabase("Normal")(w, req)
+		case req.URL.Path == "/databases/Orders/operations/state" && req.URL.Query().Get("id") == "5":
+			_, _ = w.Write([]byte(*operation))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}
+}
+
+func testBackupRun(databases ...string) *ravendbv1.RavenDBBackupRun {
+	return &ravendbv1.RavenDBBackupRun{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-now", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBBackupRunSpec{
+			ClusterRef:  "raven",
+			Databases:   databases,
+			Destination: ravendbv1.BackupDestination{Local: &ravendbv1.LocalBackupDestination{FolderPath: "/backups"}},
+		},
+	}
+}
+
+func TestRunBackups_SucceedsOnceTheOperationCompleted(t *testing.T) {
+	starts, operation := 0, `{"Status":"InProgress"}`
+	hcc := fakeRavenDB(t, backupRunServer(t, &starts, &operation))
+	run := testBackupRun("Orders")
+	r := &RavenDBBackupRunReconciler{Client: fakeClient(t)}
+
+	result, err := r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.True(t, result.Requeue)
+	require.Zero(t, starts, "the run is saved before any backup starts")
+	require.NotNil(t, run.Status.StartTime)
+	require.Equal(t, ravendbv1.BackupRunPending, run.Status.Databases[0].Phase)
+
+	result, err = r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.NotZero(t, result.RequeueAfter)
+	require.Equal(t, ravendbv1.BackupRunRunning, run.Status.Phase)
+	require.Equal(t, int64(5), run.Status.Databases[0].OperationId)
+	require.Equal(t, "A", run.Status.Databases[0].NodeTag)
+
+	_, err = r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.BackupRunRunning, run.Status.Phase)
+
+	operation = `{"Status":"Completed"}`
+	result, err = r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.Zero(t, result.RequeueAfter)
+	require.Equal(t, ravendbv1.BackupRunSucceeded, run.Status.Phase)
+	require.NotNil(t, run.Status.CompletionTime)
+	require.Equal(t, 1, starts, "a run is started once")
+}
+
+func TestRunBackups_AMissingDatabaseFailsOnItsOwn(t *testing.T) {
+	starts, operation := 0, `{"Status":"Completed"}`
+	hcc := fakeRavenDB(t, backupRunServer(t, &starts, &operation))
+	run := testBackupRun("Orders", "Invoices")
+	r := &RavenDBBackupRunReconciler{Client: fakeClient(t)}
+
+	for range 2 {
+		_, err := r.runBackups(context.Background(), run, hcc)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, starts, "Orders is backed up anyway")
+	require.Equal(t, ravendbv1.BackupRunSucceeded, run.Status.Databases[0].Phase)
+	require.Equal(t, ravendbv1.BackupRunFailed, run.Status.Databases[1].Phase)
+	require.Equal(t, "no such database", run.Status.Databases[1].Message)
+	require.Equal(t, ravendbv1.BackupRunFailed, run.Status.Phase)
+	require.True(t, strings.HasSuffix(run.Status.Message, "Invoices"), run.Status.Message)
+}
+
+func TestRunBackups_RetriesADatabaseWhoseRecordCantBeRead(t *testing.T) {
+	starts, operation := 0, `unavailable`
+	hcc := fakeRavenDB(t, backupRunServer(t, &starts, &operation))
+	run := testBackupRun("Orders")
+	r := &RavenDBBackupRunReconciler{Client: fakeClient(t)}
+
+	_, err := r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	_, err = r.runBackups(context.Background(), run, hcc)
+	require.Error(t, err)
+	require.Equal(t, ravendbv1.BackupRunPending, run.Status.Databases[0].Phase, "not a final failure")
+
+	operation = `{"Status":"InProgress"}`
+	_, err = r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.Equal(t, 1, starts)
+	require.Equal(t, ravendbv1.BackupRunRunning, run.Status.Databases[0].Phase)
+}
+
+func TestRunBackups_FailsWhenTheNodeLostTheOperation(t *testing.T) {
+	starts, operation := 0, `{"Status":"InProgress"}`
+	hcc := fakeRavenDB(t, backupRunServer(t, &starts, &operation))
+	run := testBackupRun("Orders")
+	r := &RavenDBBackupRunReconciler{Client: fakeClient(t)}
+
+	for range 2 {
+		_, err := r.runBackups(context.Background(), run, hcc)
+		require.NoError(t, err)
+	}
+
+	operation = `null`
+	_, err := r.runBackups(context.Background(), run, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.BackupRunFailed, run.Status.Phase)
+	require.Contains(t, run.Status.Databases[0].Message, "lost track of operation 5")
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+	"ravendb-operator/pkg/upgrade"
+)
+
+/*
+The RavenDBClientCertificate flow
+---
+1) wait for the referenced cluster to exist and finish bootstrapping.
+2) talk to it with the cluster's own client certificate (BuildHTTPSClientFromCluster) and:
+   - with spec.provided: register the PEM certificate of that secret. if the secret has the
+     private key too, the PFX is written to spec.secretName.
+   - otherwise: have RavenDB generate the certificate and write the PFX it returns to
+     spec.secretName. as long as that secret holds the certificate, it's reused (and registered
+     again if someone deleted it in RavenDB).
+   either way the clearance and permissions of the registered certificate follow the spec, and a
+   certificate that was replaced (new provided certificate, lost secret) is revoked.
+   the ClusterAdmin and Operator clearances are refused unless the cluster sets
+   spec.allowPrivilegedClientCertificates.
+3) status reports the thumbprint, expiry and the secret.
+4) on delete a finalizer revokes the certificate in RavenDB. a deleted cluster has nothing to revoke.
+*/
+
+const clientPFXKey = "client.pfx"
+
+// RavenDBClientCertificateReconciler reconciles a RavenDBClientCertificate object
+type RavenDBClientCertificateReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclientcertificates,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclientcertificates/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclientcertificates/finalizers,verbs=update
+func (r *RavenDBClientCertificateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var cc ravendbv1.RavenDBClientCertificate
+	if err := r.Get(ctx, req.NamespacedName, &cc); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !cc.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &cc)
+	}
+
+	if controllerutil.AddFinalizer(&cc, common.ClientCertificateFinalizer) {
+		if err := r.Update(ctx, &cc); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := cc.DeepCopy()
+	result, err := r.reconcileCertificate(ctx, &cc)
+	if err != nil {
+		logger.Error(err, "client certificate reconcile failed")
+		cc.Status.Phase = ravendbv1.ClientCertificateFailed
+		cc.Status.Message = err.Error()
+		if r.Recorder != nil {
+			r.Recorder.Eventf(&cc, corev1.EventTypeWarning, "ClientCertificateFailed", "%v", err)
+		}
+	}
+	cc.Status.ObservedGeneration = cc.Generation
+
+	if !reflect.DeepEqual(original.Status, cc.Status) {
+		if perr := r.Status().Patch(ctx, &cc, client.MergeFrom(original)); perr != nil {
+			if kerrors.IsConflict(perr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, perr
+		}
+	}
+	return result, err
+}
+
+func (r *RavenDBClientCertificateReconciler) reconcileCertificate(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate) (ctrl.Result, error) {
+	hcc, cluster, waiting := clusterChecks(ctx, r.Client, cc.Namespace, cc.Spec.ClusterRef)
+	if hcc == nil {
+		cc.Status.Phase = ravendbv1.ClientCertificatePending
+		cc.Status.Message = waiting
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+	return r.syncCertificate(ctx, cc, cluster, hcc)
+}
+
+// syncCertificate registers the certificate of cc in the cluster hcc talks to.
+func (r *RavenDBClientCertificateReconciler) syncCertificate(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate, cluster *ravendbv1.RavenDBCluster, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	// the cluster watch brings us back once the cluster opts in
+	if cc.GetClearance().Privileged() && !cluster.Spec.AllowPrivilegedClientCertificates {
+		cc.Status.Phase = ravendbv1.ClientCertificateFailed
+		cc.Status.Message = fmt.Sprintf("clearance %s needs spec.allowPrivilegedClientCertificates on RavenDBCluster %s", cc.GetClearance(), cluster.Name)
+		if r.Recorder != nil {
+			r.Recorder.Event(cc, corev1.EventTypeWarning, "ClearanceRefused", cc.Status.Message)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	registered, err := hcc.Certificates(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	var cert *x509.Certificate
+	var pfx []byte
+	if cc.Spec.Provided != nil {
+		cert, pfx, err = r.providedCertificate(ctx, cc)
+	} else {
+		cert, pfx, err = r.generatedCertificate(ctx, cc, hcc, registered)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	thumbprint := certutil.Thumbprint(cert)
+
+	switch existing := findRegistered(registered, thumbprint); {
+	case existing == nil && pfx != nil && cc.Spec.Provided == nil:
+		// just generated, RavenDB registered it with the spec's clearance and permissions
+	case existing == nil:
+		err = hcc.RegisterClientCertificate(ctx, cc.GetCertificateName(), cert, cc.GetClearance(), cc.Spec.Permissions)
+	case existing.Name != cc.GetCertificateName() || existing.SecurityClearance != cc.GetClearance() || !maps.Equal(existing.Permissions, cc.Spec.Permissions):
+		err = hcc.EditClientCertificate(ctx, thumbprint, cc.GetCertificateName(), cc.GetClearance(), cc.Spec.Permissions)
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if old := cc.Status.Thumbprint; old != "" && old != thumbprint {
+		if err := hcc.DeleteCertificate(ctx, old); err != nil {
+			return ctrl.Result{}, fmt.Errorf("revoke replaced certificate: %w", err)
+		}
+	}
+	cc.Status.Thumbprint = thumbprint
+	notAfter := metav1.NewTime(cert.NotAfter)
+	cc.Status.NotAfter = &notAfter
+
+	if pfx != nil {
+		if err := r.writeSecret(ctx, cc, pfx); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	cc.Status.Phase = ravendbv1.ClientCertificateReady
+	cc.Status.Message = ""
+	return ctrl.Result{}, nil
+}
+
+// providedCertificate reads spec.provided. The PFX is only returned when the private key is
+// there and the secret we own doesn't hold this certificate yet.
+func (r *RavenDBClientCertificateReconciler) providedCertificate(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate) (*x509.Certificate, []byte, error) {
+	p := cc.Spec.Provided
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cc.Namespace, Name: p.SecretRef}, &secret); err != nil {
+		return nil, nil, fmt.Errorf("get provided certificate secret %q: %w", p.SecretRef, err)
+	}
+	certPEM := secret.Data[p.GetCertKey()]
+	if len(certPEM) == 0 {
+		return nil, nil, fmt.Errorf("secret %q has no %s", p.SecretRef, p.GetCertKey())
+	}
+	cert, err := certutil.ParsePEMCertificate(certPEM)
+	if err != nil {
+		return nil, nil, fmt.Errorf("secret %q: %w", p.SecretRef, err)
+	}
+
+	keyPEM := secret.Data[p.GetPrivateKeyKey()]
+	if len(keyPEM) == 0 {
+		return cert, nil, nil
+	}
+	if current, _ := r.ownedCertificate(ctx, cc); current != nil && current.Equal(cert) {
+		return cert, nil, nil
+	}
+	pfx, err := certutil.PEMToPFX(certPEM, keyPEM, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("secret %q: %w", p.SecretRef, err)
+	}
+	return cert, pfx, nil
+}
+
+// generatedCertificate reuses the certificate in the secret we own and only has RavenDB
+// generate a new one when there's none. A new one comes back with its PFX.
+func (r *RavenDBClientCertificateReconciler) generatedCertificate(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate, hcc *upgrade.HealthCheckContext, registered []upgrade.RegisteredCertificate) (*x509.Certificate, []byte, error) {
+	if current, err := r.ownedCertificate(ctx, cc); err != nil {
+		return nil, nil, err
+	} else if current != nil {
+		return current, nil, nil
+	}
+
+	pfx, err := hcc.GenerateClientCertificate(ctx, cc.GetCertificateName(), cc.GetClearance(), cc.Spec.Permissions)
+	if err != nil {
+		return nil, nil, err
+	}
+	cert, err := certutil.ParsePFXCertificate(pfx, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("generated certificate: %w", err)
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(cc, corev1.EventTypeNormal, "ClientCertificateGenerated",
+			"RavenDB generated certificate %s, stored in secret %s", certutil.Thumbprint(cert), cc.GetSecretName())
+	}
+	return cert, pfx, nil
+}
+
+// ownedCertificate returns the certificate in spec.secretName, when that secret is ours.
+// A missing secret, or one without a readable client.pfx, gives nil.
+func (r *RavenDBClientCertificateReconciler) ownedCertificate(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate) (*x509.Certificate, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cc.Namespace, Name: cc.GetSecretName()}, &secret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !metav1.IsControlledBy(&secret, cc) {
+		return nil, fmt.Errorf("secret %q exists and isn't owned by this RavenDBClientCertificate", secret.Name)
+	}
+	cert, err := certutil.ParsePFXCertificate(secret.Data[clientPFXKey], "")
+	if err != nil {
+		return nil, nil
+	}
+	return cert, nil
+}
+
+func (r *RavenDBClientCertificateReconciler) writeSecret(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate, pfx []byte) error {
+	desired := resource.BuildClientCertificateSecret(cc, pfx)
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: desired.Name, Namespace: desired.Namespace}}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, secret, func() error {
+		if !secret.CreationTimestamp.IsZero() && !metav1.IsControlledBy(secret, cc) {
+			return fmt.Errorf("secret %q exists and isn't owned by this RavenDBClientCertificate", secret.Name)
+		}
+		secret.Labels = desired.Labels
+		secret.Type = desired.Type
+		secret.Data = desired.Data
+		return controllerutil.SetControllerReference(cc, secret, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("write secret %q: %w", desired.Name, err)
+	}
+
+	// a renamed secret leaves the old one behind otherwise
+	if prev := cc.Status.SecretName; prev != "" && prev != desired.Name {
+		var old corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{Namespace: cc.Namespace, Name: prev}, &old); err == nil && metav1.IsControlledBy(&old, cc) {
+			if err := r.Delete(ctx, &old); client.IgnoreNotFound(err) != nil {
+				return err
+			}
+		}
+	}
+	cc.Status.SecretName = desired.Name
+	return nil
+}
+
+func (r *RavenDBClientCertificateReconciler) finalize(ctx context.Context, cc *ravendbv1.RavenDBClientCertificate) error {
+	if !controllerutil.ContainsFinalizer(cc, common.ClientCertificateFinalizer) {
+		return nil
+	}
+
+	if cc.Status.Thumbprint != "" {
+		var cluster ravendbv1.RavenDBCluster
+		err := r.Get(ctx, client.ObjectKey{Namespace: cc.Namespace, Name: cc.Spec.ClusterRef}, &cluster)
+		switch {
+		case kerrors.IsNotFound(err):
+			// nothing left to revoke it from
+		case err != nil:
+			return err
+		case cluster.DeletionTimestamp.IsZero():
+			httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, &cluster)
+			if err != nil {
+				return err
+			}
+			if err := upgrade.NewChecks(httpc, &cluster).DeleteCertificate(ctx, cc.Status.Thumbprint); err != nil {
+				if r.Recorder != nil {
+					r.Recorder.Eventf(cc, corev1.EventTypeWarning, "ClientCertificateRevokeFailed", "%v", err)
+				}
+				return err
+			}
+			log.FromContext(ctx).Info("revoked client certificate", "thumbprint", cc.Status.Thumbprint)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(cc, common.ClientCertificateFinalizer)
+	return r.Update(ctx, cc)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBClientCertificateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+
+	ctx := context.Background()
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &ravendbv1.RavenDBClientCertificate{}, common.ClusterRefIndex, func(obj client.Object) []string {
+		return []string{obj.(*ravendbv1.RavenDBClientCertificate).Spec.ClusterRef}
+	}); err != nil {
+		return err
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &ravendbv1.RavenDBClientCertificate{}, common.SecretRefsIndex, func(obj client.Object) []string {
+		if p := obj.(*ravendbv1.RavenDBClientCertificate).Spec.Provided; p != nil {
+			return []string{p.SecretRef}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBClientCertificate{}).
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		Watches(&ravendbv1.RavenDBCluster{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newClientCertificateList, common.ClusterRefIndex))).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newClientCertificateList, common.SecretRefsIndex))).
+		Complete(r)
+}
+
+func newClientCertificateList() client.ObjectList { return &ravendbv1.RavenDBClientCertificateList{} }
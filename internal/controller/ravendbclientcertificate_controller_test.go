@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+func TestSyncCertificate_RefusesPrivilegedClearanceWithoutOptIn(t *testing.T) {
+	for _, clearance := range []ravendbv1.SecurityClearance{ravendbv1.ClearanceClusterAdmin, ravendbv1.ClearanceOperator} {
+		t.Run(string(clearance), func(t *testing.T) {
+			hcc := fakeRavenDB(t, func(w http.ResponseWriter, req *http.Request) {
+				t.Errorf("unexpected %s %s", req.Method, req.URL)
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+			cluster := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"}}
+			cc := &ravendbv1.RavenDBClientCertificate{
+				ObjectMeta: metav1.ObjectMeta{Name: "admin", Namespace: "ravendb"},
+				Spec:       ravendbv1.RavenDBClientCertificateSpec{ClusterRef: "raven", Clearance: clearance},
+			}
+
+			r := &RavenDBClientCertificateReconciler{Client: fakeClient(t)}
+			_, err := r.syncCertificate(context.Background(), cc, cluster, hcc)
+			require.NoError(t, err)
+			require.Equal(t, ravendbv1.ClientCertificateFailed, cc.Status.Phase)
+			require.Contains(t, cc.Status.Message, "allowPrivilegedClientCertificates")
+			require.Empty(t, cc.Status.Thumbprint)
+		})
+	}
+}
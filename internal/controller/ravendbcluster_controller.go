@@ -19,9 +19,11 @@ package controller
 import (
 	"context"
 	"reflect"
+	"time"
 
 	"ravendb-operator/pkg/common"
 	"ravendb-operator/pkg/director"
+	"ravendb-operator/pkg/metrics"
 	"ravendb-operator/pkg/upgrade"
 
 	ravendbv1 "ravendb-operator/api/v1"
@@ -39,6 +41,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -83,6 +86,36 @@ Step by step (detailed flow)
    - we set an OwnerReference from every child object back to this RavenDBCluster.
    (meaning: 1. if the CR is deleted, K8s automatically cleans up our owned children
              2. when the collector look at the cluster later (status purposes), it can easily filter our objs)
+   - after the actors ran, objects we control that the current spec no longer produces (e.g the Ingress
+     after switching to aws-nlb, or the Service/StatefulSet of a dropped node) are pruned.
+     with --prune-dry-run they are only logged.
+   - with spec.monitoring the monitoring actor adds a ServiceMonitor or PodMonitor per node (and, for
+     ServiceMonitors, a metrics Service per node) that scrapes RavenDB over mTLS. the prometheus-operator
+     CRDs have to be installed for that; without them the actor fails and says so.
+   - with spec.certManager (None mode) the cert-manager actor runs first: it requests the server and client
+     Certificates and converts the issued TLS secrets into server.pfx/client.pfx/ca.crt under the secret
+     names the spec references. those are rewritten only when cert-manager renewed the certificate; a renewed
+     server certificate is then handed to RavenDB's cluster certificate replacement.
+   - a certificate rotation (None mode: clusterCertSecretRef pointed at another secret, or
+     certificateRotationGeneration bumped) hands the new server.pfx to RavenDB's cluster certificate
+     replacement, then checks each node's public URL until it serves the new certificate. once all do, the
+     certificate is copied into the secrets the pods still mount. status.certificateRotation tracks it, and we
+     requeue every few seconds meanwhile. before bootstrap the status only records the certificate in use.
+   - in LetsEncrypt mode RavenDB renews the node certificates itself and update-cert.sh writes them into
+     the nodes' secrets. a new certificate in a secret is recorded in status.certificateRenewal as a renewal,
+     and each node's served certificate is compared with its secret; a node that diverges gets a warning.
+   - the operator's client certificate and spec.trustedClientCertificates are kept registered in RavenDB.
+     a new clientCertSecretRef is registered using the old one, used from the moment it answers, and the
+     old one is revoked; status.clientCertificate names the secret our HTTPS client uses meanwhile.
+     trusted certificates dropped from the spec are revoked.
+   - a license secret holding another license.json id than status.licenseUpdate is activated through
+     /admin/license/activate and confirmed on every node's /license/status. if RavenDB refuses it, or the
+     nodes don't report it within two minutes, the upgrader restarts the nodes one at a time, through its
+     gates, to load the license file (pod template annotation ravendb.io/license-restart). a node that
+     still doesn't report it two minutes after every node restarted fails the update, with an event.
+   - if the CR carries ravendb.io/reconcile-paused=true this whole step (actors + upgrader) is skipped,
+     so nothing we own is touched during manual recovery. steps 3-6 still run and the ReconcilePaused
+     condition reports the paused state.
 
    we apply resources using SSA:
    - SSA is a smart merge done by the K8s API https://kubernetes.io/docs/reference/using-api/server-side-apply/ .
@@ -94,27 +127,61 @@ Step by step (detailed flow)
    - the collector lists what's in the cluster that we own (StatefulSets, Jobs, Services,
      Ingresses, Pods, PVCs) plus relevant Secrets.
    - it translates raw K8s objects into simple "facts" (names, phases, ready flags, etc.).
+   - once bootstrapped, it also asks RavenDB over mTLS for /cluster/topology (roles,
+     connectivity, versions), /databases (per-database summary) and snapshots the nodes'
+     notification centers (active alerts). since those change without a K8s event we resync periodically.
+     the notification centers are read at most once a minute per cluster; reconciles in between keep
+     the alerts of the last read.
 
 4) work out health and phase
    - the evaluator looks at the facts and sets conditions like:
-     StorageReady, CertificatesReady, LicensesValid, NodesHealthy, ExternalAccessReady
-     (if configured), BootstrapCompleted, Progressing, Degraded.
+     StorageReady, CertificatesReady, LicensesValid, NodesHealthy, ClusterFormed, DatabasesHealthy,
+     ExternalAccessReady (if configured), BootstrapCompleted, Progressing, Degraded, ReconcilePaused,
+     CertificatesExpiring (server.pfx/client.pfx/ca.crt are decoded; status.certificates lists them),
+     LicenseExpiring and LicenseLimitsExceeded (status.license has license.json's id and the type,
+     expiration and limits RavenDB reports).
    - then we roll them up into a single Phase
        Ready -> Running
        else if Degraded -> Error
        else if Progressing -> Deploying
        else -> Deploying.
+   - Degraded follows spec.health: restarts within a window (the history is kept on status.nodes),
+     CrashLoopBackOff, recent OOM kills, low free storage reported by RavenDB and PVCs below their request.
 
 5) persist status with conflict handling
    compare original.Status vs instance.Status (DeepEqual).
    - if Status changed, we patch only the Status subresource against our earlier snapshot.
    - If the API says "conflict" (someone updated Status at the same time), we requeue and try again, instead of overwriting.
+   - actors report whether an apply actually changed anything (resourceVersion moved). only then we emit a
+     ResourceUpdated event and requeue shortly to observe the rollout; a no-op reconcile does not requeue.
 
 6) emit events on changes
    - if any condition's Status/Reason/Message changed, we log it and publish a K8s Event.
+   - every RavenDB alert that wasn't active before becomes a RavenDBAlert Event.
+
+Metrics
+---
+pkg/metrics registers its collectors on the controller-runtime registry, so they're served by the
+manager's metrics endpoint next to the built-in ones: every reconcile's outcome, the evaluated
+conditions, each node's upgrade state and the bootstrap duration per cluster; the upgrader adds
+gate results and durations. a deleted cluster's series are dropped on the NotFound reconcile.
+
+Watches
+---
+Besides the objects we own, we watch the Secrets and ConfigMaps the spec references (license,
+certificates, additional volumes, and the TLS secrets cert-manager issues into). Those carry no
+owner reference to the cluster, so clusters are field-indexed by the names they reference and a
+change maps back to every cluster using it. Secrets are only watched by their metadata and read
+uncached, so their content isn't kept in memory. With spec.rollOnSecretChange the upgrader also rolls nodes whose mounted secrets changed.
 
 */
 
+const requeueAfterResourceChange = 5 * time.Second
+
+// RavenDB's own view (topology, ...) changes without any K8s event, so once we
+// read it we come back periodically.
+const resyncAfterRavenDBPoll = 1 * time.Minute
+
 // RavenDBClusterReconciler reconciles a RavenDBCluster object
 type RavenDBClusterReconciler struct {
 	client.Client
@@ -123,9 +190,14 @@ type RavenDBClusterReconciler struct {
 	Upgrader   upgrade.Upgrader
 	Recorder   record.EventRecorder
 	BaseTiming upgrade.Timing
+
+	// PruneDryRun makes the orphan cleanup only log what it would delete.
+	PruneDryRun bool
+
+	alerts *health.AlertHistory
 }
 
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclusters/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbclusters/finalizers,verbs=update
@@ -140,48 +212,84 @@ type RavenDBClusterReconciler struct {
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch;update
 // +kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch;update
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
-func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=servicemonitors;podmonitors,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
+func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, reterr error) {
 	logger := log.FromContext(ctx)
 
 	var instance ravendbv1.RavenDBCluster
 	if err := r.Get(ctx, req.NamespacedName, &instance); err != nil {
 		if kerrors.IsNotFound(err) {
+			metrics.ForgetCluster(req.Namespace, req.Name)
+			r.alerts.Forget(req.NamespacedName)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, err
 	}
 
+	defer func() {
+		metrics.ObserveReconcile(req.Namespace, req.Name, reconcileOutcome(result, reterr))
+	}()
+
 	original := instance.DeepCopy()
 	prevConditions := append([]metav1.Condition(nil), original.Status.Conditions...)
 
-	_, err := r.Director.ExecutePerCluster(ctx, &instance, r.Client, r.Scheme)
-	if err != nil {
-		logger.Error(err, "failed to execute cluster-level actors")
-		return ctrl.Result{}, err
-	}
+	resourcesChanged := false
+	rotating, updatingLicense := false, false
 
-	applyNode := func(node ravendbv1.RavenDBNode) error {
-		_, err := r.Director.ExecutePerNode(ctx, &instance, node, r.Client, r.Scheme)
-		return err
-	}
+	if common.IsReconcilePaused(&instance) {
+		logger.Info("reconcile paused, skipping actors and upgrader", "annotation", common.ReconcilePausedAnnotation)
+	} else {
+		changed, err := r.Director.ExecutePerCluster(ctx, &instance, r.Client, r.Scheme)
+		if err != nil {
+			logger.Error(err, "failed to execute cluster-level actors")
+			return ctrl.Result{}, err
+		}
+		if changed {
+			resourcesChanged = true
+			r.emitResourceUpdated(&instance, logger, "cluster-level resources were created or updated")
+		}
 
-	r.Upgrader.SetTiming(upgrade.ReadTimingFromAnnotations(&instance, r.BaseTiming))
+		rotating = r.reconcileCertificateRotation(ctx, &instance, logger)
+		if r.reconcileCertManagerReplacement(ctx, &instance, logger) {
+			rotating = true
+		}
+		r.reconcileCertificateRenewal(ctx, &instance, logger)
+		r.reconcileClientCertificates(ctx, &instance, logger)
+		updatingLicense = r.reconcileLicenseUpdate(ctx, &instance, logger)
+
+		applyNode := func(node ravendbv1.RavenDBNode) error {
+			changed, err := r.Director.ExecutePerNode(ctx, &instance, node, r.Client, r.Scheme)
+			if changed {
+				resourcesChanged = true
+				r.emitResourceUpdated(&instance, logger, "resources of node "+node.Tag+" were created or updated")
+			}
+			return err
+		}
 
-	nodeStatuses, err := r.Upgrader.Run(ctx, &instance, r.Client, applyNode)
-	if err != nil {
-		logger.Error(err, "rolling upgrade failed")
-		if r.Recorder != nil {
-			r.Recorder.Eventf(&instance, corev1.EventTypeWarning, "RollingUpgradeFailed", "%v", err)
+		r.Upgrader.SetTiming(upgrade.ReadTimingFromAnnotations(&instance, r.BaseTiming))
+
+		nodeStatuses, err := r.Upgrader.Run(ctx, &instance, r.Client, applyNode)
+		if err != nil {
+			logger.Error(err, "rolling upgrade failed")
+			if r.Recorder != nil {
+				r.Recorder.Eventf(&instance, corev1.EventTypeWarning, "RollingUpgradeFailed", "%v", err)
+			}
+		}
+		instance.Status.Nodes = nodeStatuses
+
+		if r.pruneOrphans(ctx, &instance, logger) {
+			resourcesChanged = true
 		}
 	}
-	instance.Status.Nodes = nodeStatuses
 
-	resFacts, err := health.NewResourceCollector().Collect(ctx, r.Client, &instance)
+	resFacts, err := health.NewResourceCollectorWithAlertHistory(r.alerts).Collect(ctx, r.Client, &instance)
 	if err != nil {
 		logger.Error(err, "resource translation failed")
 	}
-	ev := health.NewEvaluator()
+	ev := health.NewEvaluatorWithAlertHistory(r.alerts)
 	ev.Evaluate(ctx, &instance, resFacts, metav1.Now())
+	recordClusterMetrics(&instance, original.Status.Nodes)
 
 	statusChanged := !reflect.DeepEqual(original.Status, instance.Status)
 	if statusChanged {
@@ -193,10 +301,63 @@ func (r *RavenDBClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 		}
 		emitConditionTransitions(&instance, prevConditions, logger, r.Recorder)
 	}
+	emitNewAlerts(&instance, r.alerts.Commit(req.NamespacedName), logger, r.Recorder)
+
+	// something we own was just written; come back soon to observe how it settles
+	// instead of waiting for the next watch event.
+	if resourcesChanged {
+		return ctrl.Result{RequeueAfter: requeueAfterResourceChange}, nil
+	}
+
+	if rotating || updatingLicense {
+		return ctrl.Result{RequeueAfter: requeueDuringCertRotation}, nil
+	}
+
+	if resFacts != nil && resFacts.RavenDB != nil {
+		return ctrl.Result{RequeueAfter: resyncAfterRavenDBPoll}, nil
+	}
 
 	return ctrl.Result{}, nil
 }
 
+func (r *RavenDBClusterReconciler) emitResourceUpdated(cluster *ravendbv1.RavenDBCluster, logger logr.Logger, msg string) {
+	logger.Info("resources changed", "detail", msg)
+	if r.Recorder != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ResourceUpdated", msg)
+	}
+}
+
+// pruneOrphans reports whether anything was actually deleted. Nodes dropped from the spec
+// leave the RavenDB cluster first; nothing is pruned while it's unknown whether they did.
+func (r *RavenDBClusterReconciler) pruneOrphans(ctx context.Context, cluster *ravendbv1.RavenDBCluster, logger logr.Logger) bool {
+	keepNodes, err := r.removeDroppedNodes(ctx, cluster, logger)
+	if err != nil {
+		logger.Info("skipping orphan cleanup until the cluster topology can be read", "reason", err.Error())
+		return false
+	}
+
+	pruned, err := r.Director.Prune(ctx, cluster, r.Client, r.PruneDryRun, keepNodes)
+	if err != nil {
+		logger.Error(err, "orphan cleanup failed")
+		if r.Recorder != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeWarning, "PruneFailed", "%v", err)
+		}
+	}
+
+	for _, ref := range pruned {
+		if r.PruneDryRun {
+			logger.Info("dry-run: would prune resource no longer produced by the spec", "resource", ref)
+			continue
+		}
+		logger.Info("pruned resource no longer produced by the spec", "resource", ref)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(cluster, corev1.EventTypeNormal, "ResourcePruned", "deleted %s, no longer produced by the spec", ref)
+		}
+	}
+
+	return len(pruned) > 0 && !r.PruneDryRun
+}
+
 func emitConditionTransitions(cluster *ravendbv1.RavenDBCluster, prevConditions []metav1.Condition, logger logr.Logger, rec record.EventRecorder) {
 
 	previousByType := make(map[string]metav1.Condition, len(prevConditions))
@@ -225,6 +386,24 @@ func emitConditionTransitions(cluster *ravendbv1.RavenDBCluster, prevConditions
 	}
 }
 
+// publishes an event for every RavenDB alert that was just raised, including the ones
+// beyond what status.alerts lists.
+func emitNewAlerts(cluster *ravendbv1.RavenDBCluster, raised []ravendbv1.AlertSummary, logger logr.Logger, rec record.EventRecorder) {
+	for _, a := range raised {
+		logger.Info("RavenDB alert raised", "node", a.Node, "database", a.Database, "alert", a.AlertType)
+
+		eventType := corev1.EventTypeWarning
+		if a.Severity != string(ravendbv1.AlertSeverityWarning) && a.Severity != string(ravendbv1.AlertSeverityError) {
+			eventType = corev1.EventTypeNormal
+		}
+		scope := "node " + a.Node
+		if a.Database != "" {
+			scope += " database " + a.Database
+		}
+		rec.Eventf(cluster, eventType, "RavenDBAlert", "%s: [%s] %s: %s", scope, a.Severity, a.Title, a.Message)
+	}
+}
+
 func getEventSeverity(cur metav1.Condition) string {
 	switch ravendbv1.ClusterConditionType(cur.Type) {
 
@@ -243,6 +422,13 @@ func getEventSeverity(cur metav1.Condition) string {
 	case ravendbv1.ConditionProgressing:
 		return corev1.EventTypeNormal
 
+	case ravendbv1.ConditionReconcilePaused, ravendbv1.ConditionCertificatesExpiring,
+		ravendbv1.ConditionLicenseExpiring, ravendbv1.ConditionLicenseLimitsExceeded:
+		if cur.Status == metav1.ConditionTrue {
+			return corev1.EventTypeWarning
+		}
+		return corev1.EventTypeNormal
+
 	default:
 		if cur.Status == metav1.ConditionFalse {
 			return corev1.EventTypeWarning
@@ -254,15 +440,28 @@ func getEventSeverity(cur metav1.Condition) string {
 // SetupWithManager sets up the controller with the Manager.
 func (r *RavenDBClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+	r.alerts = health.NewAlertHistory()
 	timing := upgrade.DefaultTiming()
 	r.Upgrader = upgrade.NewUpgrader(timing)
 	r.BaseTiming = timing
 
 	r.Upgrader.SetEmitter(upgrade.NewGateEventEmitter(r.Client, r.Recorder))
 
+	ctx := context.Background()
+	indexer := mgr.GetFieldIndexer()
+	if err := indexer.IndexField(ctx, &ravendbv1.RavenDBCluster{}, common.SecretRefsIndex, indexSecretRefs); err != nil {
+		return err
+	}
+	if err := indexer.IndexField(ctx, &ravendbv1.RavenDBCluster{}, common.ConfigMapRefsIndex, indexConfigMapRefs); err != nil {
+		return err
+	}
+	if err := health.RegisterIndexes(ctx, indexer); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&ravendbv1.RavenDBCluster{},
-			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{})),
 		).
 		Owns(&batchv1.Job{}).
 		Owns(&appsv1.StatefulSet{}).
@@ -270,6 +469,8 @@ func (r *RavenDBClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&networkingv1.Ingress{}).
 		Owns(&corev1.Pod{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&corev1.Secret{}).
+		Owns(&corev1.Secret{}, builder.OnlyMetadata).
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.clustersReferencing(common.SecretRefsIndex))).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.clustersReferencing(common.ConfigMapRefsIndex))).
 		Complete(r)
 }
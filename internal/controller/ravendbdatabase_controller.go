@@ -0,0 +1,399 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+	"ravendb-operator/pkg/upgrade"
+)
+
+/*
+The RavenDBDatabase flow
+---
+1) wait for the referenced cluster to exist and finish bootstrapping.
+2) talk to it with the cluster's own client certificate (BuildHTTPSClientFromCluster) and:
+   - create the database when there's no such record, on spec.topology or on replicationFactor
+     nodes RavenDB picks. an encrypted database first gets its key (generated into the key secret
+     unless that exists) distributed to the topology nodes.
+   - a database that already exists and wasn't created by this resource (status.databaseName) is
+     only taken over with spec.adoptExisting. status.databaseName is saved before creating.
+   - otherwise add and remove nodes until the database group matches spec.topology, or has
+     replicationFactor nodes. nodes are removed only once every node that stays is a member.
+   - put spec.settings, when set, on the record when they differ, and reload the database.
+3) status reflects the database group; the phase is Ready once every node is a member.
+4) on delete a finalizer deletes the database when deletionPolicy is Delete. Retain, or a deleted
+   cluster, leaves it be.
+*/
+
+// new members take a while to catch up; RavenDB doesn't tell us when they did
+const requeueDatabaseTopology = 15 * time.Second
+
+// RavenDBDatabaseReconciler reconciles a RavenDBDatabase object
+type RavenDBDatabaseReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbdatabases/finalizers,verbs=update
+func (r *RavenDBDatabaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var db ravendbv1.RavenDBDatabase
+	if err := r.Get(ctx, req.NamespacedName, &db); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !db.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &db)
+	}
+
+	if controllerutil.AddFinalizer(&db, common.DatabaseFinalizer) {
+		if err := r.Update(ctx, &db); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	original := db.DeepCopy()
+	result, err := r.reconcileDatabase(ctx, &db)
+	if err != nil {
+		logger.Error(err, "database reconcile failed")
+		db.Status.Phase = ravendbv1.DatabaseFailed
+		db.Status.Message = err.Error()
+		if r.Recorder != nil {
+			r.Recorder.Eventf(&db, corev1.EventTypeWarning, "DatabaseFailed", "%v", err)
+		}
+	}
+	db.Status.ObservedGeneration = db.Generation
+
+	if !reflect.DeepEqual(original.Status, db.Status) {
+		if perr := r.Status().Patch(ctx, &db, client.MergeFrom(original)); perr != nil {
+			if kerrors.IsConflict(perr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, perr
+		}
+	}
+	return result, err
+}
+
+func (r *RavenDBDatabaseReconciler) reconcileDatabase(ctx context.Context, db *ravendbv1.RavenDBDatabase) (ctrl.Result, error) {
+	hcc, _, waiting := clusterChecks(ctx, r.Client, db.Namespace, db.Spec.ClusterRef)
+	if hcc == nil {
+		db.Status.Phase = ravendbv1.DatabasePending
+		db.Status.Message = waiting
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+	return r.syncDatabase(ctx, db, hcc)
+}
+
+// syncDatabase brings the database in RavenDB, through hcc, in line with the spec.
+func (r *RavenDBDatabaseReconciler) syncDatabase(ctx context.Context, db *ravendbv1.RavenDBDatabase, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	name := db.GetDatabaseName()
+	rec, err := hcc.DatabaseRecord(ctx, name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if db.Status.DatabaseName == "" {
+		if rec != nil && !db.Spec.AdoptExisting {
+			db.Status.Phase = ravendbv1.DatabaseFailed
+			db.Status.Message = fmt.Sprintf("database %q already exists and isn't managed by this resource; set adoptExisting to take it over", name)
+			r.event(db, corev1.EventTypeWarning, "DatabaseExists", "%s", db.Status.Message)
+			return ctrl.Result{}, nil
+		}
+		// the name is saved before the database is created, so a lost status update can't make
+		// the operator refuse a database it created itself
+		db.Status.DatabaseName = name
+		if rec == nil {
+			db.Status.Phase = ravendbv1.DatabasePending
+			db.Status.Message = "creating the database"
+			return ctrl.Result{Requeue: true}, nil
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseAdopted", "adopted existing database %q", name)
+	}
+
+	if rec == nil {
+		if err := r.createDatabase(ctx, db, hcc); err != nil {
+			return ctrl.Result{}, err
+		}
+		db.Status.Phase = ravendbv1.DatabasePending
+		db.Status.Message = "database created, waiting for its nodes"
+		return ctrl.Result{RequeueAfter: requeueDatabaseTopology}, nil
+	}
+
+	if rec.Encrypted != db.Spec.Encrypted {
+		return ctrl.Result{}, fmt.Errorf("database %q exists with encrypted=%t, it can't be changed to %t", name, rec.Encrypted, db.Spec.Encrypted)
+	}
+
+	changed, err := r.reconcileTopology(ctx, db, hcc, rec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if db.Spec.Settings != nil && !maps.Equal(rec.Settings, db.Spec.Settings) {
+		if err := hcc.PutDatabaseSettings(ctx, name, db.Spec.Settings); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := hcc.ReloadDatabase(ctx, name); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseSettingsApplied", "applied %d settings to database %q and reloaded it", len(db.Spec.Settings), name)
+	}
+
+	t := rec.Topology
+	db.Status.ReplicationFactor = int32(t.ReplicationFactor)
+	db.Status.Members = t.Members
+	db.Status.Promotables = t.Promotables
+	db.Status.Rehabs = t.Rehabs
+
+	if changed || !topologySettled(db, t) {
+		db.Status.Phase = ravendbv1.DatabasePending
+		db.Status.Message = fmt.Sprintf("waiting for the database group: members=%v promotables=%v rehabs=%v",
+			t.Members, t.Promotables, t.Rehabs)
+		return ctrl.Result{RequeueAfter: requeueDatabaseTopology}, nil
+	}
+
+	db.Status.Phase = ravendbv1.DatabaseReady
+	db.Status.Message = ""
+	return ctrl.Result{}, nil
+}
+
+func (r *RavenDBDatabaseReconciler) createDatabase(ctx context.Context, db *ravendbv1.RavenDBDatabase, hcc *upgrade.HealthCheckContext) error {
+	name := db.GetDatabaseName()
+	if db.Spec.Encrypted {
+		key, err := r.encryptionKey(ctx, db, hcc, true)
+		if err != nil {
+			return err
+		}
+		if err := hcc.DistributeSecret(ctx, name, key, db.Spec.Topology); err != nil {
+			return err
+		}
+	}
+
+	if err := hcc.CreateDatabase(ctx, name, db.GetReplicationFactor(), db.Spec.Topology, db.Spec.Encrypted, db.Spec.Settings); err != nil {
+		return err
+	}
+	r.event(db, corev1.EventTypeNormal, "DatabaseCreated", "created database %q", name)
+	return nil
+}
+
+// reconcileTopology adds and removes nodes of the database group, and reports whether it did.
+func (r *RavenDBDatabaseReconciler) reconcileTopology(ctx context.Context, db *ravendbv1.RavenDBDatabase, hcc *upgrade.HealthCheckContext, rec *upgrade.DatabaseRecord) (bool, error) {
+	name := db.GetDatabaseName()
+	add, remove := topologyChanges(db, rec.Topology)
+
+	for _, tag := range add {
+		if db.Spec.Encrypted {
+			key, err := r.encryptionKey(ctx, db, hcc, false)
+			if err != nil {
+				return false, err
+			}
+			if err := hcc.DistributeSecret(ctx, name, key, []string{tag}); err != nil {
+				return false, err
+			}
+		}
+		if err := hcc.AddDatabaseNode(ctx, name, tag); err != nil {
+			return false, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseNodeAdded", "added node %s to database %q", orAny(tag), name)
+	}
+
+	for _, tag := range remove {
+		if err := hcc.RemoveDatabaseNode(ctx, name, tag); err != nil {
+			return false, err
+		}
+		r.event(db, corev1.EventTypeNormal, "DatabaseNodeRemoved", "removed node %s from database %q", tag, name)
+	}
+	return len(add) > 0 || len(remove) > 0, nil
+}
+
+// topologyChanges returns the nodes to add to the database group, or else the ones to remove.
+// Without spec.topology only the number of nodes matters; removing prefers nodes that aren't
+// members. Removals wait until every node that stays is a member: promotables are still
+// catching up and rehabs are behind, so removing earlier could leave the data on fewer nodes
+// than before. A node that's leaving doesn't need to be a member, or a dead one couldn't go.
+func topologyChanges(db *ravendbv1.RavenDBDatabase, t upgrade.DatabaseTopology) (add, remove []string) {
+	nodes := t.Nodes()
+	if len(db.Spec.Topology) > 0 {
+		for _, tag := range db.Spec.Topology {
+			if !containsTag(nodes, tag) {
+				add = append(add, tag)
+			}
+		}
+		for _, tag := range nodes {
+			if !containsTag(db.Spec.Topology, tag) {
+				remove = append(remove, tag)
+			}
+		}
+	} else if want := db.GetReplicationFactor(); len(nodes) < want {
+		for range want - len(nodes) {
+			add = append(add, "")
+		}
+	} else if len(nodes) > want {
+		members := slices.Clone(t.Members)
+		slices.Reverse(members)
+		candidates := append(append(slices.Clone(t.Rehabs), t.Promotables...), members...)
+		remove = candidates[:len(nodes)-want]
+	}
+
+	if len(add) > 0 {
+		return add, nil
+	}
+	for _, tag := range nodes {
+		if !containsTag(remove, tag) && !containsTag(t.Members, tag) {
+			return nil, nil
+		}
+	}
+	return nil, remove
+}
+
+// encryptionKey reads the key secret. Only a database that doesn't exist yet may get a new key.
+func (r *RavenDBDatabaseReconciler) encryptionKey(ctx context.Context, db *ravendbv1.RavenDBDatabase, hcc *upgrade.HealthCheckContext, generate bool) (string, error) {
+	secretName := db.GetEncryptionKeySecretRef()
+	var secret corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: db.Namespace, Name: secretName}, &secret)
+	switch {
+	case err == nil:
+		key := strings.TrimSpace(string(secret.Data[common.EncryptionKeySecretKey]))
+		if key == "" {
+			return "", fmt.Errorf("encryption key secret %q has no %s", secretName, common.EncryptionKeySecretKey)
+		}
+		return key, nil
+	case !kerrors.IsNotFound(err):
+		return "", err
+	case !generate:
+		return "", fmt.Errorf("encryption key secret %q is missing, database %q can't be placed on a new node", secretName, db.GetDatabaseName())
+	}
+
+	key, err := hcc.GenerateSecret(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := r.Create(ctx, resource.BuildEncryptionKeySecret(db, key)); err != nil {
+		return "", fmt.Errorf("write encryption key secret %q: %w", secretName, err)
+	}
+	r.event(db, corev1.EventTypeNormal, "EncryptionKeyGenerated", "generated the encryption key of database %q into secret %s", db.GetDatabaseName(), secretName)
+	return key, nil
+}
+
+func (r *RavenDBDatabaseReconciler) finalize(ctx context.Context, db *ravendbv1.RavenDBDatabase) error {
+	if !controllerutil.ContainsFinalizer(db, common.DatabaseFinalizer) {
+		return nil
+	}
+
+	if db.GetDeletionPolicy() == ravendbv1.DatabaseDeletionDelete && db.Status.DatabaseName != "" {
+		var cluster ravendbv1.RavenDBCluster
+		err := r.Get(ctx, client.ObjectKey{Namespace: db.Namespace, Name: db.Spec.ClusterRef}, &cluster)
+		switch {
+		case kerrors.IsNotFound(err):
+			// nothing left to delete it from
+		case err != nil:
+			return err
+		case cluster.DeletionTimestamp.IsZero():
+			httpc, err := upgrade.BuildHTTPSClientFromCluster(ctx, r.Client, &cluster)
+			if err != nil {
+				return err
+			}
+			if err := upgrade.NewChecks(httpc, &cluster).DeleteDatabase(ctx, db.Status.DatabaseName); err != nil {
+				r.event(db, corev1.EventTypeWarning, "DatabaseDeleteFailed", "%v", err)
+				return err
+			}
+			log.FromContext(ctx).Info("deleted database", "database", db.Status.DatabaseName)
+		}
+	}
+
+	controllerutil.RemoveFinalizer(db, common.DatabaseFinalizer)
+	return r.Update(ctx, db)
+}
+
+func (r *RavenDBDatabaseReconciler) event(db *ravendbv1.RavenDBDatabase, eventType, reason, format string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(db, eventType, reason, format, args...)
+	}
+}
+
+// topologySettled is true once every node of the database group is a member and the group is
+// the one the spec asks for.
+func topologySettled(db *ravendbv1.RavenDBDatabase, t upgrade.DatabaseTopology) bool {
+	if len(t.Promotables) > 0 || len(t.Rehabs) > 0 {
+		return false
+	}
+	if len(db.Spec.Topology) == 0 {
+		return len(t.Members) == db.GetReplicationFactor()
+	}
+	if len(t.Members) != len(db.Spec.Topology) {
+		return false
+	}
+	for _, tag := range db.Spec.Topology {
+		if !containsTag(t.Members, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+func orAny(tag string) string {
+	if tag == "" {
+		return "(picked by RavenDB)"
+	}
+	return tag
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBDatabaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ravendbv1.RavenDBDatabase{}, common.ClusterRefIndex, func(obj client.Object) []string {
+		return []string{obj.(*ravendbv1.RavenDBDatabase).Spec.ClusterRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBDatabase{}).
+		Watches(&ravendbv1.RavenDBCluster{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newDatabaseList, common.ClusterRefIndex))).
+		Complete(r)
+}
+
+func newDatabaseList() client.ObjectList { return &ravendbv1.RavenDBDatabaseList{} }
@@ -0,0 +1,160 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/upgrade"
+)
+
+// fakeRavenDB serves handler as every node of a cluster and returns the checks bound to it.
+func fakeRavenDB(t *testing.T, handler http.HandlerFunc) *upgrade.HealthCheckContext {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	cluster := &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Nodes: []ravendbv1.RavenDBNode{{Tag: "A", PublicServerUrl: srv.URL}, {Tag: "B", PublicServerUrl: srv.URL}},
+		},
+	}
+	return upgrade.NewChecks(srv.Client(), cluster)
+}
+
+// failOn fails the test on any request that isn't a GET.
+func failOn(t *testing.T, get http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s", req.Method, req.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		get(w, req)
+	}
+}
+
+func TestTopologyChanges(t *testing.T) {
+	pinned := func(tags ...string) *ravendbv1.RavenDBDatabase {
+		return &ravendbv1.RavenDBDatabase{Spec: ravendbv1.RavenDBDatabaseSpec{Topology: tags}}
+	}
+	sized := func(rf int32) *ravendbv1.RavenDBDatabase {
+		return &ravendbv1.RavenDBDatabase{Spec: ravendbv1.RavenDBDatabaseSpec{ReplicationFactor: &rf}}
+	}
+
+	tests := []struct {
+		name        string
+		db          *ravendbv1.RavenDBDatabase
+		topology    upgrade.DatabaseTopology
+		add, remove []string
+	}{
+		{
+			name:     "missing nodes are added first",
+			db:       pinned("A", "C"),
+			topology: upgrade.DatabaseTopology{Members: []string{"A", "B"}},
+			add:      []string{"C"},
+		},
+		{
+			name:     "removal waits for an added node to become a member",
+			db:       pinned("A", "C"),
+			topology: upgrade.DatabaseTopology{Members: []string{"A", "B"}, Promotables: []string{"C"}},
+		},
+		{
+			name:     "removal waits for a rehab that stays",
+			db:       pinned("A", "C"),
+			topology: upgrade.DatabaseTopology{Members: []string{"B", "C"}, Rehabs: []string{"A"}},
+		},
+		{
+			name:     "a leaving node doesn't have to be a member",
+			db:       pinned("A", "C"),
+			topology: upgrade.DatabaseTopology{Members: []string{"A", "C"}, Rehabs: []string{"B"}},
+			remove:   []string{"B"},
+		},
+		{
+			name:     "nodes are removed once every remaining one is a member",
+			db:       pinned("a", "c"),
+			topology: upgrade.DatabaseTopology{Members: []string{"A", "B", "C"}},
+			remove:   []string{"B"},
+		},
+		{
+			name:     "a lower replication factor removes non-members, then the last members",
+			db:       sized(1),
+			topology: upgrade.DatabaseTopology{Members: []string{"A", "B"}, Rehabs: []string{"C"}},
+			remove:   []string{"C", "B"},
+		},
+		{
+			name:     "a higher replication factor lets RavenDB pick the nodes",
+			db:       sized(3),
+			topology: upgrade.DatabaseTopology{Members: []string{"A"}},
+			add:      []string{"", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := topologyChanges(tt.db, tt.topology)
+			require.Equal(t, tt.add, add)
+			require.Equal(t, tt.remove, remove)
+		})
+	}
+}
+
+func TestSyncDatabase_DoesNotAdoptWithoutOptIn(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"DatabaseName":"Orders","Topology":{"Members":["A"],"ReplicationFactor":1}}`))
+	}))
+	db := &ravendbv1.RavenDBDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBDatabaseSpec{ClusterRef: "raven", DatabaseName: "Orders", DeletionPolicy: ravendbv1.DatabaseDeletionDelete},
+	}
+
+	r := &RavenDBDatabaseReconciler{}
+	_, err := r.syncDatabase(context.Background(), db, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.DatabaseFailed, db.Status.Phase)
+	require.Contains(t, db.Status.Message, "adoptExisting")
+	require.Empty(t, db.Status.DatabaseName, "a database that isn't ours must not be deleted by the finalizer")
+
+	db.Spec.AdoptExisting = true
+	_, err = r.syncDatabase(context.Background(), db, hcc)
+	require.NoError(t, err)
+	require.Equal(t, "Orders", db.Status.DatabaseName)
+	require.Equal(t, ravendbv1.DatabaseReady, db.Status.Phase)
+}
+
+func TestSyncDatabase_SavesTheNameBeforeCreating(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	db := &ravendbv1.RavenDBDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBDatabaseSpec{ClusterRef: "raven"},
+	}
+
+	result, err := (&RavenDBDatabaseReconciler{}).syncDatabase(context.Background(), db, hcc)
+	require.NoError(t, err)
+	require.True(t, result.Requeue)
+	require.Equal(t, "orders", db.Status.DatabaseName)
+	require.Equal(t, ravendbv1.DatabasePending, db.Status.Phase)
+}
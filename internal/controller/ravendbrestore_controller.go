@@ -0,0 +1,296 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
+)
+
+/*
+The RavenDBRestore flow
+---
+1) wait for the referenced cluster to bootstrap.
+2) a database a RavenDBDatabase manages is never restored over, its controller would fight us.
+   a database of that name that already exists fails the restore, unless allowOverwrite is set:
+   then it's deleted first, and the restore waits until it's gone. one that's being restored
+   (by anyone) is never deleted.
+3) status.startTime is saved first. then start RavenDB's restore operation from the source, with
+   the credentials secret of the source and the encryption key secret if there is one. status
+   keeps the operation id and its node. a restore that started but lost its operation id
+   (startTime without operationId and a database there) follows the database record instead.
+4) poll the operation on that node, publishing its last message as progress, until it completed
+   (Succeeded) or faulted (Failed). a restore that's done is never started again.
+*/
+
+// how often a running operation is looked at
+const requeueOperationPoll = 10 * time.Second
+
+// RavenDBRestoreReconciler reconciles a RavenDBRestore object
+type RavenDBRestoreReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ravendb.ravendb.io,resources=ravendbrestores/status,verbs=get;update;patch
+func (r *RavenDBRestoreReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+
+	var rs ravendbv1.RavenDBRestore
+	if err := r.Get(ctx, req.NamespacedName, &rs); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if !rs.DeletionTimestamp.IsZero() || rs.Status.Done() {
+		return ctrl.Result{}, nil
+	}
+
+	original := rs.DeepCopy()
+	result, err := r.reconcileRestore(ctx, &rs)
+	if err != nil {
+		// nothing terminal: the restore hasn't started, or we lost track of it for a moment
+		logger.Error(err, "restore reconcile failed")
+		rs.Status.Message = err.Error()
+		r.event(&rs, corev1.EventTypeWarning, "RestoreError", "%v", err)
+	}
+	rs.Status.ObservedGeneration = rs.Generation
+
+	if !reflect.DeepEqual(original.Status, rs.Status) {
+		if perr := r.Status().Patch(ctx, &rs, client.MergeFrom(original)); perr != nil {
+			if kerrors.IsConflict(perr) {
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, perr
+		}
+	}
+	return result, err
+}
+
+func (r *RavenDBRestoreReconciler) reconcileRestore(ctx context.Context, rs *ravendbv1.RavenDBRestore) (ctrl.Result, error) {
+	hcc, _, waiting := clusterChecks(ctx, r.Client, rs.Namespace, rs.Spec.ClusterRef)
+	if hcc == nil {
+		rs.Status.Phase = ravendbv1.RestorePending
+		rs.Status.Message = waiting
+		return ctrl.Result{RequeueAfter: requeueClusterRefWait}, nil
+	}
+
+	return r.restore(ctx, rs, hcc)
+}
+
+// restore moves the restore a step further, through hcc.
+func (r *RavenDBRestoreReconciler) restore(ctx context.Context, rs *ravendbv1.RavenDBRestore, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	if rs.Status.OperationId != 0 {
+		return r.pollRestore(ctx, rs, hcc)
+	}
+
+	name := rs.Spec.DatabaseName
+	rec, err := hcc.DatabaseRecord(ctx, name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if rs.Status.StartTime != nil {
+		// the restore may be running already, with the status update that recorded its
+		// operation lost. its database then exists, and isn't someone else's: it didn't
+		// exist when startTime was saved.
+		if rec != nil {
+			return r.followRecord(rs, rec), nil
+		}
+		return r.startRestore(ctx, rs, hcc)
+	}
+
+	owner, err := r.managedBy(ctx, rs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if owner != "" {
+		r.complete(rs, ravendbv1.RestoreFailed, fmt.Sprintf("database %q is managed by RavenDBDatabase %s; delete it with deletionPolicy Retain before restoring", name, owner))
+		r.event(rs, corev1.EventTypeWarning, "RestoreRefused", "database %q is managed by RavenDBDatabase %s", name, owner)
+		return ctrl.Result{}, nil
+	}
+
+	if rec != nil {
+		if !rs.Spec.AllowOverwrite {
+			r.complete(rs, ravendbv1.RestoreFailed, fmt.Sprintf("database %q already exists; set allowOverwrite to replace it", name))
+			r.event(rs, corev1.EventTypeWarning, "RestoreRefused", "database %q already exists", name)
+			return ctrl.Result{}, nil
+		}
+		if rec.RestoreInProgress() {
+			rs.Status.Phase = ravendbv1.RestorePending
+			rs.Status.Message = fmt.Sprintf("database %q is being restored by another operation", name)
+			return ctrl.Result{RequeueAfter: requeueOperationPoll}, nil
+		}
+		if err := hcc.DeleteDatabase(ctx, name); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.event(rs, corev1.EventTypeNormal, "DatabaseOverwritten", "deleted the existing database %q to restore over it", name)
+		rs.Status.Phase = ravendbv1.RestorePending
+		rs.Status.Message = fmt.Sprintf("waiting for the existing database %q to be deleted", name)
+		return ctrl.Result{RequeueAfter: requeueOperationPoll}, nil
+	}
+
+	// saved before RavenDB is asked to restore, so the database the restore creates is never
+	// taken for an existing one
+	now := metav1.Now()
+	rs.Status.StartTime = &now
+	rs.Status.Phase = ravendbv1.RestoreRunning
+	rs.Status.Message = "starting the restore"
+	return ctrl.Result{Requeue: true}, nil
+}
+
+func (r *RavenDBRestoreReconciler) startRestore(ctx context.Context, rs *ravendbv1.RavenDBRestore, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	name := rs.Spec.DatabaseName
+	source, err := backupSettings(ctx, r.Client, rs.Namespace, rs.Spec.Source)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	opts := upgrade.RestoreOptions{
+		DatabaseName:          name,
+		LastFileNameToRestore: rs.Spec.LastFileNameToRestore,
+		DisableOngoingTasks:   rs.Spec.DisableOngoingTasks,
+		SkipIndexes:           rs.Spec.SkipIndexes,
+	}
+	if ref := rs.Spec.EncryptionKeySecretRef; ref != "" {
+		var secret corev1.Secret
+		if err := r.Get(ctx, client.ObjectKey{Namespace: rs.Namespace, Name: ref}, &secret); err != nil {
+			return ctrl.Result{}, fmt.Errorf("get encryption key secret %q: %w", ref, err)
+		}
+		if opts.EncryptionKey = strings.TrimSpace(string(secret.Data[common.EncryptionKeySecretKey])); opts.EncryptionKey == "" {
+			return ctrl.Result{}, fmt.Errorf("encryption key secret %q has no %s", ref, common.EncryptionKeySecretKey)
+		}
+	}
+
+	id, tag, err := hcc.StartRestore(ctx, opts, source)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	rs.Status.OperationId = id
+	rs.Status.NodeTag = tag
+	rs.Status.Phase = ravendbv1.RestoreRunning
+	rs.Status.Message = ""
+	r.event(rs, corev1.EventTypeNormal, "RestoreStarted", "restoring database %q on node %s (operation %d)", name, tag, id)
+	return ctrl.Result{RequeueAfter: requeueOperationPoll}, nil
+}
+
+// followRecord follows a restore that lost its operation id through the record of the database
+// it creates: RestoreInProgress until it's done. A faulted restore removes the record.
+func (r *RavenDBRestoreReconciler) followRecord(rs *ravendbv1.RavenDBRestore, rec *upgrade.DatabaseRecord) ctrl.Result {
+	if rec.RestoreInProgress() {
+		rs.Status.Phase = ravendbv1.RestoreRunning
+		rs.Status.Message = "the restore operation id was lost, following the database record"
+		return ctrl.Result{RequeueAfter: requeueOperationPoll}
+	}
+	r.complete(rs, ravendbv1.RestoreSucceeded, "")
+	r.event(rs, corev1.EventTypeNormal, "RestoreSucceeded", "restored database %q", rs.Spec.DatabaseName)
+	return ctrl.Result{}
+}
+
+// managedBy names the RavenDBDatabase, if any, that manages the database being restored.
+func (r *RavenDBRestoreReconciler) managedBy(ctx context.Context, rs *ravendbv1.RavenDBRestore) (string, error) {
+	var dbs ravendbv1.RavenDBDatabaseList
+	if err := r.List(ctx, &dbs,
+		client.InNamespace(rs.Namespace),
+		client.MatchingFields{common.ClusterRefIndex: rs.Spec.ClusterRef},
+	); err != nil {
+		return "", err
+	}
+	for _, db := range dbs.Items {
+		if strings.EqualFold(db.GetDatabaseName(), rs.Spec.DatabaseName) {
+			return db.Name, nil
+		}
+	}
+	return "", nil
+}
+
+func (r *RavenDBRestoreReconciler) pollRestore(ctx context.Context, rs *ravendbv1.RavenDBRestore, hcc *upgrade.HealthCheckContext) (ctrl.Result, error) {
+	st, err := hcc.OperationState(ctx, rs.Status.NodeTag, "", rs.Status.OperationId)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if st == nil {
+		r.complete(rs, ravendbv1.RestoreFailed, fmt.Sprintf("node %s lost track of operation %d", rs.Status.NodeTag, rs.Status.OperationId))
+		r.event(rs, corev1.EventTypeWarning, "RestoreFailed", "%s", rs.Status.Message)
+		return ctrl.Result{}, nil
+	}
+	if st.Message != "" {
+		rs.Status.Progress = st.Message
+	}
+
+	switch st.Status {
+	case upgrade.OperationCompleted:
+		r.complete(rs, ravendbv1.RestoreSucceeded, "")
+		r.event(rs, corev1.EventTypeNormal, "RestoreSucceeded", "restored database %q", rs.Spec.DatabaseName)
+		return ctrl.Result{}, nil
+	case upgrade.OperationFaulted, upgrade.OperationCanceled:
+		r.complete(rs, ravendbv1.RestoreFailed, fmt.Sprintf("restore %s: %s", strings.ToLower(st.Status), st.Message))
+		r.event(rs, corev1.EventTypeWarning, "RestoreFailed", "%s", rs.Status.Message)
+		return ctrl.Result{}, nil
+	}
+
+	rs.Status.Phase = ravendbv1.RestoreRunning
+	rs.Status.Message = ""
+	return ctrl.Result{RequeueAfter: requeueOperationPoll}, nil
+}
+
+func (r *RavenDBRestoreReconciler) complete(rs *ravendbv1.RavenDBRestore, phase ravendbv1.RestorePhase, message string) {
+	now := metav1.Now()
+	rs.Status.Phase = phase
+	rs.Status.Message = message
+	rs.Status.CompletionTime = &now
+}
+
+func (r *RavenDBRestoreReconciler) event(rs *ravendbv1.RavenDBRestore, eventType, reason, format string, args ...any) {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(rs, eventType, reason, format, args...)
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RavenDBRestoreReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.Recorder = mgr.GetEventRecorderFor(common.Manager)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &ravendbv1.RavenDBRestore{}, common.ClusterRefIndex, func(obj client.Object) []string {
+		return []string{obj.(*ravendbv1.RavenDBRestore).Spec.ClusterRef}
+	}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&ravendbv1.RavenDBRestore{}).
+		Watches(&ravendbv1.RavenDBCluster{}, handler.EnqueueRequestsFromMapFunc(requestsReferencing(r.Client, newRestoreList, common.ClusterRefIndex))).
+		Complete(r)
+}
+
+func newRestoreList() client.ObjectList { return &ravendbv1.RavenDBRestoreList{} }
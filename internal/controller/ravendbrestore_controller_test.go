@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+// fakeClient is a fake client with the field indexes the controllers set up.
+func fakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ravendbv1.AddToScheme(scheme))
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithIndex(&ravendbv1.RavenDBDatabase{}, common.ClusterRefIndex, func(obj client.Object) []string {
+			return []string{obj.(*ravendbv1.RavenDBDatabase).Spec.ClusterRef}
+		}).
+		Build()
+}
+
+func existingDatabase(state string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"DatabaseName":"Orders","DatabaseState":"` + state + `","Topology":{"Members":["A"]}}`))
+	}
+}
+
+func testRestore(allowOverwrite bool) *ravendbv1.RavenDBRestore {
+	return &ravendbv1.RavenDBRestore{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders-restore", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBRestoreSpec{
+			ClusterRef:     "raven",
+			DatabaseName:   "Orders",
+			Source:         ravendbv1.BackupDestination{Local: &ravendbv1.LocalBackupDestination{FolderPath: "/backups/orders"}},
+			AllowOverwrite: allowOverwrite,
+		},
+	}
+}
+
+func TestRestore_RefusesToOverwriteWithoutAllowOverwrite(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, existingDatabase("Normal")))
+	rs := testRestore(false)
+
+	r := &RavenDBRestoreReconciler{Client: fakeClient(t)}
+	_, err := r.restore(context.Background(), rs, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.RestoreFailed, rs.Status.Phase)
+	require.Contains(t, rs.Status.Message, "allowOverwrite")
+	require.Nil(t, rs.Status.StartTime)
+}
+
+func TestRestore_RefusesDatabasesManagedByARavenDBDatabase(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, existingDatabase("Normal")))
+	managed := &ravendbv1.RavenDBDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBDatabaseSpec{ClusterRef: "raven", DatabaseName: "orders"},
+	}
+	rs := testRestore(true)
+
+	r := &RavenDBRestoreReconciler{Client: fakeClient(t, managed)}
+	_, err := r.restore(context.Background(), rs, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.RestoreFailed, rs.Status.Phase)
+	require.Contains(t, rs.Status.Message, "RavenDBDatabase orders")
+}
+
+func TestRestore_NeverDeletesADatabaseBeingRestored(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, existingDatabase("RestoreInProgress")))
+	rs := testRestore(true)
+
+	r := &RavenDBRestoreReconciler{Client: fakeClient(t)}
+	_, err := r.restore(context.Background(), rs, hcc)
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.RestorePending, rs.Status.Phase)
+	require.Nil(t, rs.Status.StartTime)
+}
+
+func TestRestore_SavesStartTimeBeforeStarting(t *testing.T) {
+	hcc := fakeRavenDB(t, failOn(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	rs := testRestore(false)
+
+	r := &RavenDBRestoreReconciler{Client: fakeClient(t)}
+	result, err := r.restore(context.Background(), rs, hcc)
+	require.NoError(t, err)
+	require.True(t, result.Requeue)
+	require.NotNil(t, rs.Status.StartTime)
+	require.Zero(t, rs.Status.OperationId)
+}
+
+func TestRestore_FollowsTheRecordWhenTheOperationIsLost(t *testing.T) {
+	rs := testRestore(true)
+	started := metav1.Now()
+	rs.Status.StartTime = &started
+	r := &RavenDBRestoreReconciler{Client: fakeClient(t)}
+
+	// the database exists because our restore created it: it's neither refused nor deleted
+	_, err := r.restore(context.Background(), rs, fakeRavenDB(t, failOn(t, existingDatabase("RestoreInProgress"))))
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.RestoreRunning, rs.Status.Phase)
+
+	_, err = r.restore(context.Background(), rs, fakeRavenDB(t, failOn(t, existingDatabase("Normal"))))
+	require.NoError(t, err)
+	require.Equal(t, ravendbv1.RestoreSucceeded, rs.Status.Phase)
+}
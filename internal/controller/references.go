@@ -0,0 +1,60 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// License, certificate and volume secrets/configmaps are created by users, so they carry
+// no owner reference back to the cluster. We index clusters by the names they reference
+// and map every change on such an object back to the clusters using it.
+
+func indexSecretRefs(obj client.Object) []string {
+	return common.ReferencedSecretNames(obj.(*ravendbv1.RavenDBCluster))
+}
+
+func indexConfigMapRefs(obj client.Object) []string {
+	return common.ReferencedConfigMapNames(obj.(*ravendbv1.RavenDBCluster))
+}
+
+func (r *RavenDBClusterReconciler) clustersReferencing(index string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var list ravendbv1.RavenDBClusterList
+		if err := r.List(ctx, &list,
+			client.InNamespace(obj.GetNamespace()),
+			client.MatchingFields{index: obj.GetName()},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list clusters referencing object", "index", index, "name", obj.GetName())
+			return nil
+		}
+
+		reqs := make([]reconcile.Request, 0, len(list.Items))
+		for _, c := range list.Items {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&c)})
+		}
+		return reqs
+	}
+}
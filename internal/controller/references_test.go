@@ -0,0 +1,89 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+)
+
+func referencingCluster(name, namespace string, mutate func(*ravendbv1.RavenDBClusterSpec)) *ravendbv1.RavenDBCluster {
+	cluster := &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Mode:                ravendbv1.ModeNone,
+			LicenseSecretRef:    "license",
+			ClientCertSecretRef: "client-cert",
+		},
+	}
+	if mutate != nil {
+		mutate(&cluster.Spec)
+	}
+	return cluster
+}
+
+func TestIndexSecretRefs(t *testing.T) {
+	nodeCert, ca := "node-a-cert", "ca"
+	cluster := referencingCluster("raven", "ravendb", func(s *ravendbv1.RavenDBClusterSpec) {
+		s.CACertSecretRef = &ca
+		s.Nodes = []ravendbv1.RavenDBNode{{Tag: "a", CertSecretRef: &nodeCert}, {Tag: "b", CertSecretRef: &nodeCert}}
+		s.TrustedClientCertificates = []ravendbv1.TrustedClientCertificate{{Name: "orders", SecretRef: "orders-cert"}}
+		s.StorageSpec.AdditionalVolumes = &[]ravendbv1.AdditionalVolume{
+			{VolumeSource: ravendbv1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "extra"}}},
+			{VolumeSource: ravendbv1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}}}},
+		}
+	})
+
+	require.ElementsMatch(t, []string{"license", "client-cert", "ca", "node-a-cert", "orders-cert", "extra"}, indexSecretRefs(cluster))
+	require.Equal(t, []string{"settings"}, indexConfigMapRefs(cluster))
+}
+
+func TestClustersReferencing(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ravendbv1.AddToScheme(scheme))
+
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			referencingCluster("raven", "ravendb", nil),
+			referencingCluster("other-license", "ravendb", func(s *ravendbv1.RavenDBClusterSpec) { s.LicenseSecretRef = "license-2" }),
+			referencingCluster("raven", "elsewhere", nil),
+		).
+		WithIndex(&ravendbv1.RavenDBCluster{}, common.SecretRefsIndex, indexSecretRefs).
+		Build()
+	r := &RavenDBClusterReconciler{Client: c}
+
+	license := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "license", Namespace: "ravendb"}}
+	reqs := r.clustersReferencing(common.SecretRefsIndex)(context.Background(), license)
+	require.Len(t, reqs, 1)
+	require.Equal(t, types.NamespacedName{Namespace: "ravendb", Name: "raven"}, reqs[0].NamespacedName)
+
+	unrelated := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "unrelated", Namespace: "ravendb"}}
+	require.Empty(t, r.clustersReferencing(common.SecretRefsIndex)(context.Background(), unrelated))
+}
@@ -39,3 +39,23 @@ type PerClusterActor interface {
 	ShouldAct(cluster *ravendbv1.RavenDBCluster) bool
 	Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error)
 }
+
+// PerClusterPrunable is implemented by per-cluster actors whose objects can become
+// obsolete after a spec change. NewList returns an empty list of the kind the actor
+// applies, Desired the names it applies while ShouldAct is true.
+type PerClusterPrunable interface {
+	NewList() client.ObjectList
+	Desired(cluster *ravendbv1.RavenDBCluster) []string
+}
+
+// PerClusterMultiPrunable is implemented by per-cluster actors that apply more than
+// one kind; each of its Prunables covers one kind.
+type PerClusterMultiPrunable interface {
+	Prunables() []PerClusterPrunable
+}
+
+// PerNodePrunable is the per-node counterpart of PerClusterPrunable.
+type PerNodePrunable interface {
+	NewList() client.ObjectList
+	Desired(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) []string
+}
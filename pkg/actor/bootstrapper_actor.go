@@ -52,12 +52,10 @@ func (actor *BootstrapperActor) Act(ctx context.Context, cluster *ravendbv1.Rave
 	}
 
 	if _, ok := bs.(*batchv1.Job); ok {
-		_, err := applyResourceSSA(ctx, client, bs, "ravendb-operator/job")
-		return false, err
+		return applyResourceSSA(ctx, client, bs, "ravendb-operator/job")
 	}
 
-	_, err = applyResourceSSA(ctx, client, bs, "ravendb-operator/cluster")
-	return false, err
+	return applyResourceSSA(ctx, client, bs, "ravendb-operator/cluster")
 }
 
 func (actor *BootstrapperActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
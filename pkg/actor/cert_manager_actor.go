@@ -0,0 +1,266 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const certManagerFieldOwner = "ravendb-operator/cert-manager"
+
+// certManagerActor requests the server and client certificates of a None-mode cluster from
+// cert-manager and converts the issued kubernetes.io/tls Secrets into the secrets the nodes
+// and the bootstrapper mount (server.pfx, client.pfx, ca.crt). A secret is only rewritten
+// when the certificate it came from changed, since every PFX encoding differs. A renewed
+// server certificate of a bootstrapped cluster is marked pending; the cluster controller
+// hands it to RavenDB's cluster certificate replacement (reconcileCertManagerReplacement).
+//
+// The converted secrets get a plain owner reference rather than a controller one: they're
+// garbage collected with the cluster, but never pruned while pods still mount them.
+type certManagerActor struct{}
+
+func NewCertManagerActor() PerClusterActor {
+	return &certManagerActor{}
+}
+
+func (a *certManagerActor) Name() string {
+	return "CertManagerActor"
+}
+
+func (a *certManagerActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
+	return cluster.Spec.Mode == ravendbv1.ModeNone && cluster.Spec.CertManager != nil
+}
+
+func (a *certManagerActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error) {
+	anyChanged := false
+
+	serverCert, err := resource.BuildServerCertificate(cluster)
+	if err != nil {
+		return false, fmt.Errorf("failed to build server Certificate: %w", err)
+	}
+	for _, cert := range []client.Object{serverCert, resource.BuildClientCertificate(cluster)} {
+		if err := controllerutil.SetControllerReference(cluster, cert, scheme); err != nil {
+			return false, fmt.Errorf("set owner ref on Certificate %s: %w", cert.GetName(), err)
+		}
+		changed, err := applyResourceSSA(ctx, c, cert, certManagerFieldOwner)
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return false, fmt.Errorf("apply Certificate %s: cert-manager CRDs (cert-manager.io) are not installed: %w", cert.GetName(), err)
+			}
+			return false, fmt.Errorf("apply Certificate %s: %w", cert.GetName(), err)
+		}
+		anyChanged = anyChanged || changed
+	}
+
+	serverTLS, err := getIssuedTLS(ctx, c, cluster.Namespace, resource.CertManagerTLSSecretName(common.CertManagerServerCert))
+	if err != nil {
+		return false, err
+	}
+	clientTLS, err := getIssuedTLS(ctx, c, cluster.Namespace, resource.CertManagerTLSSecretName(common.CertManagerClientCert))
+	if err != nil {
+		return false, err
+	}
+	if serverTLS == nil || clientTLS == nil {
+		// the issued secrets are referenced by the cluster, so their creation triggers the next reconcile
+		log.FromContext(ctx).Info("waiting for cert-manager to issue the cluster certificates")
+		return anyChanged, nil
+	}
+
+	caPEM, err := issuerCA(serverTLS)
+	if err != nil {
+		return false, err
+	}
+
+	changed, previous, err := a.syncSecret(ctx, c, cluster, scheme, *cluster.Spec.ClusterCertSecretRef, tlsHash(serverTLS),
+		func(hash string) (*corev1.Secret, error) {
+			pfx, err := certutil.PEMToPFX(serverTLS.Data[corev1.TLSCertKey], serverTLS.Data[corev1.TLSPrivateKeyKey], "")
+			if err != nil {
+				return nil, fmt.Errorf("convert server certificate: %w", err)
+			}
+			return resource.BuildServerCertSecret(cluster, pfx, hash), nil
+		})
+	if err != nil {
+		return false, err
+	}
+	anyChanged = anyChanged || changed
+	renewed := changed && previous
+
+	changed, _, err = a.syncSecret(ctx, c, cluster, scheme, cluster.Spec.ClientCertSecretRef, tlsHash(clientTLS),
+		func(hash string) (*corev1.Secret, error) {
+			pfx, err := certutil.PEMToPFX(clientTLS.Data[corev1.TLSCertKey], clientTLS.Data[corev1.TLSPrivateKeyKey], "")
+			if err != nil {
+				return nil, fmt.Errorf("convert client certificate: %w", err)
+			}
+			return resource.BuildClientCertSecret(cluster, pfx, hash), nil
+		})
+	if err != nil {
+		return false, err
+	}
+	anyChanged = anyChanged || changed
+
+	changed, _, err = a.syncSecret(ctx, c, cluster, scheme, *cluster.Spec.CACertSecretRef, hashOf(caPEM),
+		func(hash string) (*corev1.Secret, error) {
+			return resource.BuildCACertSecret(cluster, caPEM, hash), nil
+		})
+	if err != nil {
+		return false, err
+	}
+	anyChanged = anyChanged || changed
+
+	if renewed && cluster.IsBootstrapped() {
+		if err := a.markReplacePending(ctx, c, cluster); err != nil {
+			return false, err
+		}
+	}
+
+	return anyChanged, nil
+}
+
+// syncSecret writes the secret built for sourceHash unless the live one was already built
+// from it. previous reports whether it replaced a secret built from another certificate.
+func (a *certManagerActor) syncSecret(
+	ctx context.Context,
+	c client.Client,
+	cluster *ravendbv1.RavenDBCluster,
+	scheme *runtime.Scheme,
+	name, sourceHash string,
+	build func(hash string) (*corev1.Secret, error),
+) (changed, previous bool, err error) {
+	var live corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &live); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, false, fmt.Errorf("get Secret %s: %w", name, err)
+		}
+	} else {
+		if live.Labels[common.LabelManagedBy] != common.Manager || live.Labels[common.LabelInstance] != cluster.Name {
+			return false, false, fmt.Errorf("secret %s exists and wasn't created by the operator for this cluster; with spec.certManager the operator writes it, so remove it or reference another name", name)
+		}
+		if live.Annotations[common.CertSourceHashAnnotation] == sourceHash {
+			return false, false, nil
+		}
+		previous = true
+	}
+
+	desired, err := build(sourceHash)
+	if err != nil {
+		return false, false, err
+	}
+	if err := controllerutil.SetOwnerReference(cluster, desired, scheme); err != nil {
+		return false, false, fmt.Errorf("set owner ref on Secret %s: %w", name, err)
+	}
+	if _, err := applyResourceSSA(ctx, c, desired, certManagerFieldOwner); err != nil {
+		return false, false, fmt.Errorf("apply Secret %s: %w", name, err)
+	}
+	return true, previous, nil
+}
+
+func (a *certManagerActor) markReplacePending(ctx context.Context, c client.Client, cluster *ravendbv1.RavenDBCluster) error {
+	var secret corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: *cluster.Spec.ClusterCertSecretRef}, &secret); err != nil {
+		return fmt.Errorf("get server certificate secret: %w", err)
+	}
+	patch := client.MergeFrom(secret.DeepCopy())
+	metav1.SetMetaDataAnnotation(&secret.ObjectMeta, common.CertReplacePendingAnnotation, "true")
+	if err := c.Patch(ctx, &secret, patch); err != nil {
+		return fmt.Errorf("mark certificate replacement pending: %w", err)
+	}
+	return nil
+}
+
+// Prunables drops the Certificates once spec.certManager is removed. The secrets written
+// from them stay: the nodes keep using them as regular user-managed certificates.
+func (a *certManagerActor) Prunables() []PerClusterPrunable {
+	return []PerClusterPrunable{
+		&prunable{
+			newList: func() client.ObjectList { return unstructuredList(resource.CertificateGVK) },
+			desired: func(*ravendbv1.RavenDBCluster) []string {
+				return []string{common.CertManagerServerCert, common.CertManagerClientCert}
+			},
+		},
+	}
+}
+
+// getIssuedTLS returns the kubernetes.io/tls Secret cert-manager issued, or nil while it hasn't yet.
+func getIssuedTLS(ctx context.Context, c client.Client, ns, name string) (*corev1.Secret, error) {
+	var s corev1.Secret
+	if err := c.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, &s); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get Secret %s: %w", name, err)
+	}
+	if len(s.Data[corev1.TLSCertKey]) == 0 || len(s.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil, nil
+	}
+	return &s, nil
+}
+
+// issuerCA returns the issuer's CA: ca.crt when the issuer sets it, else the top of the chain.
+func issuerCA(tls *corev1.Secret) ([]byte, error) {
+	if ca := tls.Data["ca.crt"]; len(ca) > 0 {
+		return ca, nil
+	}
+
+	var last *pem.Block
+	count := 0
+	for rest := tls.Data[corev1.TLSCertKey]; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			last = block
+			count++
+		}
+	}
+	if count < 2 {
+		return nil, fmt.Errorf("secret %s has no ca.crt and no chain to take the CA from", tls.Name)
+	}
+	return pem.EncodeToMemory(last), nil
+}
+
+func tlsHash(tls *corev1.Secret) string {
+	return hashOf(tls.Data[corev1.TLSCertKey], tls.Data[corev1.TLSPrivateKeyKey])
+}
+
+func hashOf(parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
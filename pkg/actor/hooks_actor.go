@@ -76,7 +76,8 @@ func (a *HooksActor) Act(
 		return false, fmt.Errorf("set owner ref on bootstrapper hook ConfigMap: %w", err)
 	}
 
-	if _, err := applyResourceSSA(ctx, c, bootstrapperCM, "ravendb-operator/hooks"); err != nil {
+	bootstrapperChanged, err := applyResourceSSA(ctx, c, bootstrapperCM, "ravendb-operator/hooks")
+	if err != nil {
 		return false, fmt.Errorf("apply bootstrapper hook ConfigMap: %w", err)
 	}
 
@@ -104,9 +105,10 @@ func (a *HooksActor) Act(
 		return false, fmt.Errorf("set owner ref on cert hook ConfigMap: %w", err)
 	}
 
-	if _, err := applyResourceSSA(ctx, c, certHookCM, "ravendb-operator/hooks"); err != nil {
+	certHookChanged, err := applyResourceSSA(ctx, c, certHookCM, "ravendb-operator/hooks")
+	if err != nil {
 		return false, fmt.Errorf("apply cert hook ConfigMap: %w", err)
 	}
 
-	return false, nil
+	return bootstrapperChanged || certHookChanged, nil
 }
@@ -24,6 +24,7 @@ import (
 	"ravendb-operator/pkg/common"
 	"ravendb-operator/pkg/resource"
 
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -80,3 +81,11 @@ func (actor *ingressActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
 
 	return true
 }
+
+func (actor *ingressActor) NewList() client.ObjectList {
+	return &networkingv1.IngressList{}
+}
+
+func (actor *ingressActor) Desired(_ *ravendbv1.RavenDBCluster) []string {
+	return []string{common.App}
+}
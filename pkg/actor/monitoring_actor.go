@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package actor
+
+import (
+	"context"
+	"fmt"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// monitoringActor wires the nodes into Prometheus: a metrics Service per node (ServiceMonitor
+// only) and a monitor per node, scraping with the client certificate of spec.monitoring.
+type monitoringActor struct{}
+
+func NewMonitoringActor() PerClusterActor {
+	return &monitoringActor{}
+}
+
+func (a *monitoringActor) Name() string {
+	return "MonitoringActor"
+}
+
+func (a *monitoringActor) ShouldAct(cluster *ravendbv1.RavenDBCluster) bool {
+	return cluster.Spec.Monitoring != nil
+}
+
+func (a *monitoringActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, c client.Client, scheme *runtime.Scheme) (bool, error) {
+	anyChanged := false
+	apply := func(obj client.Object, what string) error {
+		if err := controllerutil.SetControllerReference(cluster, obj, scheme); err != nil {
+			return fmt.Errorf("set owner ref on %s: %w", what, err)
+		}
+		changed, err := applyResourceSSA(ctx, c, obj, "ravendb-operator/monitoring")
+		if err != nil {
+			if meta.IsNoMatchError(err) {
+				return fmt.Errorf("apply %s: prometheus-operator CRDs (monitoring.coreos.com) are not installed: %w", what, err)
+			}
+			return fmt.Errorf("apply %s: %w", what, err)
+		}
+		anyChanged = anyChanged || changed
+		return nil
+	}
+
+	kind := resource.MonitorKindOf(cluster)
+	for _, node := range cluster.Spec.Nodes {
+		if kind == ravendbv1.MonitorKindServiceMonitor {
+			if err := apply(resource.BuildMetricsService(cluster, node), "metrics Service of node "+node.Tag); err != nil {
+				return false, err
+			}
+		}
+
+		mon, err := resource.BuildMonitor(cluster, node)
+		if err != nil {
+			return false, fmt.Errorf("failed to build %s: %w", kind, err)
+		}
+		if err := apply(mon, string(kind)+" of node "+node.Tag); err != nil {
+			return false, err
+		}
+	}
+
+	return anyChanged, nil
+}
+
+// Prunables covers every kind the actor applies, so switching the monitor kind, dropping
+// a node or removing spec.monitoring cleans up after it.
+func (a *monitoringActor) Prunables() []PerClusterPrunable {
+	return []PerClusterPrunable{
+		&prunable{
+			newList: func() client.ObjectList { return &corev1.ServiceList{} },
+			desired: func(cluster *ravendbv1.RavenDBCluster) []string {
+				if resource.MonitorKindOf(cluster) != ravendbv1.MonitorKindServiceMonitor {
+					return nil
+				}
+				return perNodeNames(cluster, resource.MetricsServiceName)
+			},
+		},
+		&prunable{
+			newList: func() client.ObjectList { return unstructuredList(resource.ServiceMonitorGVK) },
+			desired: monitorNames(ravendbv1.MonitorKindServiceMonitor),
+		},
+		&prunable{
+			newList: func() client.ObjectList { return unstructuredList(resource.PodMonitorGVK) },
+			desired: monitorNames(ravendbv1.MonitorKindPodMonitor),
+		},
+	}
+}
+
+func monitorNames(kind ravendbv1.MonitorKind) func(*ravendbv1.RavenDBCluster) []string {
+	return func(cluster *ravendbv1.RavenDBCluster) []string {
+		if resource.MonitorKindOf(cluster) != kind {
+			return nil
+		}
+		return perNodeNames(cluster, func(node ravendbv1.RavenDBNode) string { return common.Prefix + node.Tag })
+	}
+}
+
+func perNodeNames(cluster *ravendbv1.RavenDBCluster, name func(ravendbv1.RavenDBNode) string) []string {
+	names := make([]string, 0, len(cluster.Spec.Nodes))
+	for _, node := range cluster.Spec.Nodes {
+		names = append(names, name(node))
+	}
+	return names
+}
+
+func unstructuredList(gvk schema.GroupVersionKind) client.ObjectList {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	return list
+}
+
+type prunable struct {
+	newList func() client.ObjectList
+	desired func(*ravendbv1.RavenDBCluster) []string
+}
+
+func (p *prunable) NewList() client.ObjectList { return p.newList() }
+
+func (p *prunable) Desired(cluster *ravendbv1.RavenDBCluster) []string { return p.desired(cluster) }
@@ -21,8 +21,10 @@ import (
 	"fmt"
 
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
 	"ravendb-operator/pkg/resource"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -56,3 +58,11 @@ func (actor *ServiceActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCl
 	}
 	return changed, nil
 }
+
+func (actor *ServiceActor) NewList() client.ObjectList {
+	return &corev1.ServiceList{}
+}
+
+func (actor *ServiceActor) Desired(_ *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) []string {
+	return []string{common.Prefix + node.Tag}
+}
@@ -61,6 +61,16 @@ func (actor *StatefulSetActor) Name() string {
 //	     	common.UpgradeImageAnnotation on the existing StatefulSet. Seeing that marker,
 //	    	we do not freeze: we leave the builder's new image in place. SSA then updates
 //	     	the PodTemplate and Kubernetes performs a controlled rollout for this node only.
+//
+// (3) With spec.rollOnSecretChange the pod template carries a hash of the mounted
+//
+//	certificate and license secrets. It follows the same freeze policy as the image,
+//	so a changed secret only rolls the node once the Upgrader has marked it.
+//
+// (4) A license RavenDB didn't take through the API is loaded by restarting the nodes: the
+//
+//	pod template carries the id of the license the node was last restarted for, frozen
+//	the same way until the Upgrader marks the node.
 func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode, kc client.Client, scheme *runtime.Scheme) (bool, error) {
 	sts, err := actor.builder.Build(ctx, cluster, node)
 	if err != nil {
@@ -72,6 +82,17 @@ func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.Raven
 		return false, fmt.Errorf("builder returned %T, expected *appsv1.StatefulSet", sts)
 	}
 
+	if cluster.Spec.RollOnSecretChange {
+		hash, err := common.NodeSecretsHash(ctx, kc, cluster, node)
+		if err != nil {
+			return false, fmt.Errorf("failed to hash node secrets: %w", err)
+		}
+		if desired.Spec.Template.Annotations == nil {
+			desired.Spec.Template.Annotations = map[string]string{}
+		}
+		desired.Spec.Template.Annotations[common.SecretsHashAnnotation] = hash
+	}
+
 	var existing appsv1.StatefulSet
 	key := client.ObjectKey{Namespace: cluster.Namespace, Name: desired.GetName()}
 	haveExisting := (kc.Get(ctx, key, &existing) == nil)
@@ -98,6 +119,31 @@ func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.Raven
 				desired.Spec.Template.Spec.Containers[0].Image = curImg
 			}
 		}
+
+		// (3)
+		_, marked := existing.Annotations[common.UpgradeImageAnnotation]
+		curHash, hasHash := existing.Spec.Template.Annotations[common.SecretsHashAnnotation]
+		if cluster.Spec.RollOnSecretChange && !marked && hasHash {
+			desired.Spec.Template.Annotations[common.SecretsHashAnnotation] = curHash
+		}
+
+		// (4)
+		licenseID, hasLicenseID := existing.Spec.Template.Annotations[common.LicenseRestartAnnotation]
+		if marked && cluster.LicenseRestartId() != "" {
+			licenseID, hasLicenseID = cluster.LicenseRestartId(), true
+		}
+		if hasLicenseID {
+			if desired.Spec.Template.Annotations == nil {
+				desired.Spec.Template.Annotations = map[string]string{}
+			}
+			desired.Spec.Template.Annotations[common.LicenseRestartAnnotation] = licenseID
+		}
+	} else if id := cluster.LicenseRestartId(); id != "" {
+		// a new node starts with the license file anyway
+		if desired.Spec.Template.Annotations == nil {
+			desired.Spec.Template.Annotations = map[string]string{}
+		}
+		desired.Spec.Template.Annotations[common.LicenseRestartAnnotation] = id
 	}
 
 	if err := controllerutil.SetControllerReference(cluster, desired, scheme); err != nil {
@@ -113,3 +159,11 @@ func (actor *StatefulSetActor) Act(ctx context.Context, cluster *ravendbv1.Raven
 
 	return changed, nil
 }
+
+func (actor *StatefulSetActor) NewList() client.ObjectList {
+	return &appsv1.StatefulSetList{}
+}
+
+func (actor *StatefulSetActor) Desired(_ *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) []string {
+	return []string{common.Prefix + node.Tag}
+}
@@ -20,13 +20,43 @@ import (
 	"context"
 	"fmt"
 
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// applyResourceSSA applies desired with SSA and reports whether the object was
+// created or actually modified. A no-op apply leaves the object untouched, so
+// comparing the live object before and after is enough to tell the two apart:
+// kinds that track metadata.generation (StatefulSet, Job, Ingress) are compared
+// by generation, so status-only churn from their controllers does not count;
+// the rest (Service, ConfigMap) by resourceVersion. The "before" comes from c's
+// cache and the "after" from the patch response; a cache that lags behind only
+// reports one extra change, which isn't worth a live read on every apply.
 func applyResourceSSA(ctx context.Context, c client.Client, desired client.Object, fieldOwner string) (bool, error) {
+	live, ok := desired.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, fmt.Errorf("apply (SSA) %T: not a client.Object", desired)
+	}
+
+	existed := true
+	if err := c.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		if !kerrors.IsNotFound(err) {
+			return false, fmt.Errorf("get %T %s/%s: %w", desired, desired.GetNamespace(), desired.GetName(), err)
+		}
+		existed = false
+	}
+	beforeRV, beforeGen := live.GetResourceVersion(), live.GetGeneration()
+
 	desired.SetResourceVersion("")
 	if err := c.Patch(ctx, desired, client.Apply, client.FieldOwner(fieldOwner), client.ForceOwnership); err != nil {
 		return false, fmt.Errorf("apply (SSA) %T %s/%s: %w", desired, desired.GetNamespace(), desired.GetName(), err)
 	}
-	return false, nil
+
+	if !existed {
+		return true, nil
+	}
+	if beforeGen > 0 {
+		return desired.GetGeneration() != beforeGen, nil
+	}
+	return desired.GetResourceVersion() != beforeRV, nil
 }
@@ -0,0 +1,118 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package certutil decodes and encodes the PFX and PEM certificates the operator, its
+// controllers and its webhooks read from secrets. It depends on nothing of the operator,
+// so every package can use the same decoder.
+package certutil
+
+import (
+	"crypto/sha1"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pkcs12"
+	gopkcs12 "software.sslmate.com/src/go-pkcs12"
+)
+
+// PFXKeyPair decodes a PFX bundle into the key pair a TLS client presents.
+func PFXKeyPair(pfx []byte, password string) (tls.Certificate, error) {
+	blocks, err := pkcs12.ToPEM(pfx, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("decode pfx: %w", err)
+	}
+
+	var certPEM, keyPEM []byte
+	for _, b := range blocks {
+		if strings.Contains(b.Type, "PRIVATE KEY") {
+			keyPEM = append(keyPEM, pem.EncodeToMemory(b)...)
+		} else {
+			certPEM = append(certPEM, pem.EncodeToMemory(b)...)
+		}
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("decode pfx: %w", err)
+	}
+	return pair, nil
+}
+
+// ParsePFXCertificate returns the leaf certificate of a PFX bundle. Secrets without a
+// password key hold password-less bundles, opened with an empty password.
+func ParsePFXCertificate(pfx []byte, password string) (*x509.Certificate, error) {
+	pair, err := PFXKeyPair(pfx, password)
+	if err != nil {
+		return nil, err
+	}
+	if pair.Leaf != nil {
+		return pair.Leaf, nil
+	}
+	return x509.ParseCertificate(pair.Certificate[0])
+}
+
+// ParsePEMCertificate returns the first certificate of a PEM bundle such as ca.crt.
+func ParsePEMCertificate(data []byte) (*x509.Certificate, error) {
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("no PEM certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// PEMToPFX bundles a PEM key pair (e.g. a kubernetes.io/tls Secret) into the PFX layout the
+// nodes and the bootstrapper load. The chain after the leaf is kept as CA certificates. The
+// legacy SHA1/3DES encryption is used because pkcs12 decoding, ours included, expects it.
+func PEMToPFX(certPEM, keyPEM []byte, password string) ([]byte, error) {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parse key pair: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse certificate: %w", err)
+	}
+	var chain []*x509.Certificate
+	for _, der := range pair.Certificate[1:] {
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("parse chain certificate: %w", err)
+		}
+		chain = append(chain, c)
+	}
+
+	pfx, err := gopkcs12.LegacyDES.Encode(pair.PrivateKey, leaf, chain, password)
+	if err != nil {
+		return nil, fmt.Errorf("encode pfx: %w", err)
+	}
+	return pfx, nil
+}
+
+// Thumbprint is the upper-case hex SHA-1 of the certificate, the form RavenDB
+// identifies certificates by.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha1.Sum(cert.Raw)
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
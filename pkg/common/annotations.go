@@ -0,0 +1,35 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strconv"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// IsReconcilePaused reports whether the cluster carries a truthy
+// ravendb.io/reconcile-paused annotation.
+func IsReconcilePaused(cluster *ravendbv1.RavenDBCluster) bool {
+	v, ok := cluster.GetAnnotations()[ReconcilePausedAnnotation]
+	if !ok {
+		return false
+	}
+	paused, err := strconv.ParseBool(strings.TrimSpace(v))
+	return err == nil && paused
+}
@@ -51,6 +51,11 @@ const (
 	BootstrapperHookVolumeName = "ravendb-bootstrapper-hook"
 	RavenDbNodeServiceAccount  = "ravendb-ops-sa"
 	RavenDbBootstrapperJob     = "ravendb-cluster-init"
+	MetricsPortName            = "metrics"
+	MetricsSuffix              = "-metrics"
+	CertManagerServerCert      = "ravendb-server"
+	CertManagerClientCert      = "ravendb-client"
+	CertManagerTLSSuffix       = "-tls"
 )
 
 // labels
@@ -60,6 +65,7 @@ const (
 	LabelManagedBy    = "app.kubernetes.io/managed-by"
 	LabelNodeTag      = "nodeTag"
 	LabelApp          = "app"
+	LabelComponent    = "app.kubernetes.io/component"
 	TopologyZoneLabel = "topology.kubernetes.io/zone"
 )
 
@@ -78,6 +84,11 @@ const (
 	UpgradePostWaitAnnotation               = "ravendb.io/upgrade-post-wait"
 	UpgradePingIntervalAnnotation           = "ravendb.io/upgrade-ping-interval"
 	UpgradeDBIntervalAnnotation             = "ravendb.io/upgrade-db-interval"
+	ReconcilePausedAnnotation               = "ravendb.io/reconcile-paused"
+	SecretsHashAnnotation                   = "ravendb.io/secrets-hash"
+	CertSourceHashAnnotation                = "ravendb.io/cert-source-hash"
+	CertReplacePendingAnnotation            = "ravendb.io/cert-replace-pending"
+	LicenseRestartAnnotation                = "ravendb.io/license-restart"
 )
 
 // internal ports
@@ -98,6 +109,14 @@ const (
 	InternalTcpUrl   = "tcp://0.0.0.0:38888"
 )
 
+// field indexes
+const (
+	SecretRefsIndex    = ".spec.secretRefs"
+	ConfigMapRefsIndex = ".spec.configMapRefs"
+	InstanceIndex      = ".metadata.labels.instance"
+	ClusterRefIndex    = ".spec.clusterRef"
+)
+
 // other
 const (
 	NumOfReplicas                    = 1
@@ -111,4 +130,13 @@ const (
 	InitClusterHookKey               = "init-cluster.sh"
 	CheckNodesDiscoverabilityHookKey = "check-nodes-discoverability.sh"
 	BootstrapperHookConfigMap        = "ravendb-bootstrapper-hook"
+	ComponentMetrics                 = "metrics"
+	RavenDBMetricsPath               = "/admin/monitoring/v1/prometheus"
+	ComponentCertificates            = "certificates"
+	ComponentClientCertificate       = "client-certificate"
+	ClientCertificateFinalizer       = "ravendb.io/revoke-client-certificate"
+	ComponentDatabase                = "database"
+	DatabaseFinalizer                = "ravendb.io/delete-database"
+	EncryptionKeySecretKey           = "key"
+	BackupFinalizer                  = "ravendb.io/delete-backup-task"
 )
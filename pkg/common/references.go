@@ -0,0 +1,137 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	ravendbv1 "ravendb-operator/api/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ReferencedSecretNames returns every Secret the cluster spec points at that the operator
+// doesn't own: license, client/CA/cluster certificates, per-node certificates, trusted client
+// certificates, secret volumes and, with spec.certManager, the Secrets cert-manager issues into.
+func ReferencedSecretNames(cluster *ravendbv1.RavenDBCluster) []string {
+	names := []string{cluster.Spec.LicenseSecretRef, cluster.Spec.ClientCertSecretRef}
+	if cluster.Spec.CertManager != nil {
+		names = append(names,
+			CertManagerServerCert+CertManagerTLSSuffix,
+			CertManagerClientCert+CertManagerTLSSuffix,
+		)
+	}
+	if cluster.Spec.CACertSecretRef != nil {
+		names = append(names, *cluster.Spec.CACertSecretRef)
+	}
+	if cluster.Spec.ClusterCertSecretRef != nil {
+		names = append(names, *cluster.Spec.ClusterCertSecretRef)
+	}
+	for _, n := range cluster.Spec.Nodes {
+		if n.CertSecretRef != nil {
+			names = append(names, *n.CertSecretRef)
+		}
+	}
+	for _, t := range cluster.Spec.TrustedClientCertificates {
+		names = append(names, t.SecretRef)
+	}
+	if cluster.Spec.StorageSpec.AdditionalVolumes != nil {
+		for _, v := range *cluster.Spec.StorageSpec.AdditionalVolumes {
+			if v.VolumeSource.Secret != nil {
+				names = append(names, v.VolumeSource.Secret.SecretName)
+			}
+		}
+	}
+	return uniqueNonEmpty(names)
+}
+
+// ReferencedConfigMapNames returns the user-provided ConfigMaps mounted as additional volumes.
+func ReferencedConfigMapNames(cluster *ravendbv1.RavenDBCluster) []string {
+	var names []string
+	if cluster.Spec.StorageSpec.AdditionalVolumes != nil {
+		for _, v := range *cluster.Spec.StorageSpec.AdditionalVolumes {
+			if v.VolumeSource.ConfigMap != nil {
+				names = append(names, v.VolumeSource.ConfigMap.Name)
+			}
+		}
+	}
+	return uniqueNonEmpty(names)
+}
+
+// NodeCertSecretName returns the server certificate secret mounted into the node's pod.
+func NodeCertSecretName(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) string {
+	switch cluster.Spec.Mode {
+	case ravendbv1.ModeLetsEncrypt:
+		if node.CertSecretRef != nil {
+			return *node.CertSecretRef
+		}
+	case ravendbv1.ModeNone:
+		if cluster.Spec.ClusterCertSecretRef != nil {
+			return *cluster.Spec.ClusterCertSecretRef
+		}
+	}
+	return ""
+}
+
+// NodeSecretsHash hashes the content of the certificate and license secrets mounted
+// into the node's pod. Missing secrets are skipped so the hash changes once they appear.
+func NodeSecretsHash(ctx context.Context, c client.Reader, cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) (string, error) {
+	h := sha256.New()
+	for _, name := range uniqueNonEmpty([]string{NodeCertSecretName(cluster, node), cluster.Spec.LicenseSecretRef}) {
+		var s corev1.Secret
+		if err := c.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &s); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+
+		keys := make([]string, 0, len(s.Data))
+		for k := range s.Data {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		h.Write([]byte(name))
+		for _, k := range keys {
+			h.Write([]byte(k))
+			h.Write(s.Data[k])
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+func uniqueNonEmpty(in []string) []string {
+	seen := make(map[string]struct{}, len(in))
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s == "" {
+			continue
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		out = append(out, s)
+	}
+	return out
+}
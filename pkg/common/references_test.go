@@ -0,0 +1,91 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common_test
+
+import (
+	"context"
+	"testing"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func secret(name string, data map[string]string) *corev1.Secret {
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ravendb"}, Data: map[string][]byte{}}
+	for k, v := range data {
+		s.Data[k] = []byte(v)
+	}
+	return s
+}
+
+func TestNodeSecretsHash(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+
+	certA, certB := "node-a-cert", "node-b-cert"
+	cluster := &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Mode:             ravendbv1.ModeLetsEncrypt,
+			LicenseSecretRef: "license",
+			Nodes:            []ravendbv1.RavenDBNode{{Tag: "a", CertSecretRef: &certA}, {Tag: "b", CertSecretRef: &certB}},
+		},
+	}
+	nodeA, nodeB := cluster.Spec.Nodes[0], cluster.Spec.Nodes[1]
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		secret("license", map[string]string{"license.json": "{}"}),
+		secret(certA, map[string]string{"a.pfx": "cert-a"}),
+		secret("unrelated", map[string]string{"x": "y"}),
+	).Build()
+	ctx := context.Background()
+	hash := func(node ravendbv1.RavenDBNode) string {
+		t.Helper()
+		h, err := common.NodeSecretsHash(ctx, c, cluster, node)
+		require.NoError(t, err)
+		return h
+	}
+
+	before := hash(nodeA)
+	require.Equal(t, before, hash(nodeA), "the hash is stable")
+
+	unrelated := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ravendb", Name: "unrelated"}, unrelated))
+	unrelated.Data["x"] = []byte("z")
+	require.NoError(t, c.Update(ctx, unrelated))
+	require.Equal(t, before, hash(nodeA), "secrets the node doesn't mount don't count")
+
+	// node b's certificate is missing: it's skipped, and counts once it appears
+	missing := hash(nodeB)
+	require.NotEqual(t, before, missing)
+	require.NoError(t, c.Create(ctx, secret(certB, map[string]string{"b.pfx": "cert-b"})))
+	require.NotEqual(t, missing, hash(nodeB))
+
+	lic := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, client.ObjectKey{Namespace: "ravendb", Name: "license"}, lic))
+	lic.Data["license.json"] = []byte(`{"Id":"new"}`)
+	require.NoError(t, c.Update(ctx, lic))
+	require.NotEqual(t, before, hash(nodeA), "a license change rolls every node")
+}
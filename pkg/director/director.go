@@ -43,6 +43,14 @@ type Director interface {
 		c client.Client,
 		scheme *runtime.Scheme,
 	) (bool, error)
+
+	Prune(
+		ctx context.Context,
+		cluster *ravendbv1.RavenDBCluster,
+		c client.Client,
+		dryRun bool,
+		keepNodes []string,
+	) ([]string, error)
 }
 
 type DefaultDirector struct {
@@ -53,9 +61,11 @@ type DefaultDirector struct {
 func NewDefaultDirector() Director {
 	return &DefaultDirector{
 		perClusterActors: []actor.PerClusterActor{
+			actor.NewCertManagerActor(),
 			actor.NewIngressActor(resource.NewIngressBuilder()),
 			actor.NewBootstrapperActor(resource.NewJobBuilder()),
 			actor.NewHooksActor(),
+			actor.NewMonitoringActor(),
 		},
 		perNodeActors: []actor.PerNodeActor{
 			actor.NewStatefulSetActor(resource.NewStatefulSetBuilder()),
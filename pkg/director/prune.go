@@ -0,0 +1,158 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package director
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/actor"
+	"ravendb-operator/pkg/common"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type pruneTarget struct {
+	list    client.ObjectList
+	desired map[string]struct{}
+}
+
+// Prune deletes objects controlled by the cluster that the current spec no longer
+// produces, e.g. the Ingress after switching to aws-nlb or the Service and
+// StatefulSet of a dropped node. Only kinds whose actors implement one of the
+// Prunable interfaces are considered, so the bootstrap Job, the hook ConfigMaps
+// and PVCs are never touched. Kinds whose CRD isn't installed (the prometheus-operator
+// monitors) are skipped. With dryRun set nothing is deleted.
+// keepNodes are tags dropped from spec.nodes whose objects are kept anyway, since the
+// RavenDB cluster still counts them; they go once the node was removed from it.
+// It returns "<Kind> <namespace>/<name>" for every pruned (or would-be pruned) object.
+func (d *DefaultDirector) Prune(
+	ctx context.Context,
+	cluster *ravendbv1.RavenDBCluster,
+	c client.Client,
+	dryRun bool,
+	keepNodes []string,
+) ([]string, error) {
+	targets := map[string]*pruneTarget{}
+	target := func(list client.ObjectList) *pruneTarget {
+		key := fmt.Sprintf("%T", list)
+		if u, ok := list.(*unstructured.UnstructuredList); ok {
+			key = u.GroupVersionKind().String()
+		}
+		if t, ok := targets[key]; ok {
+			return t
+		}
+		t := &pruneTarget{list: list, desired: map[string]struct{}{}}
+		targets[key] = t
+		return t
+	}
+
+	for _, a := range d.perClusterActors {
+		for _, p := range clusterPrunables(a) {
+			t := target(p.NewList())
+			if !a.ShouldAct(cluster) {
+				continue
+			}
+			for _, name := range p.Desired(cluster) {
+				t.desired[name] = struct{}{}
+			}
+		}
+	}
+
+	for _, a := range d.perNodeActors {
+		p, ok := a.(actor.PerNodePrunable)
+		if !ok {
+			continue
+		}
+		t := target(p.NewList())
+		nodes := slices.Clone(cluster.Spec.Nodes)
+		for _, tag := range keepNodes {
+			// RavenDB reports tags upper-case; object names are lower-case
+			nodes = append(nodes, ravendbv1.RavenDBNode{Tag: strings.ToLower(tag)})
+		}
+		for _, node := range nodes {
+			for _, name := range p.Desired(cluster, node) {
+				t.desired[name] = struct{}{}
+			}
+		}
+	}
+
+	var pruned []string
+	for _, t := range targets {
+		if err := c.List(ctx, t.list,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{common.LabelInstance: cluster.Name, common.LabelManagedBy: common.Manager},
+		); err != nil {
+			if meta.IsNoMatchError(err) {
+				continue // optional kind whose CRD isn't installed: nothing of it to prune
+			}
+			return pruned, fmt.Errorf("list %T: %w", t.list, err)
+		}
+
+		objs, err := meta.ExtractList(t.list)
+		if err != nil {
+			return pruned, fmt.Errorf("extract %T: %w", t.list, err)
+		}
+
+		for _, o := range objs {
+			obj, ok := o.(client.Object)
+			if !ok || !metav1.IsControlledBy(obj, cluster) || obj.GetDeletionTimestamp() != nil {
+				continue
+			}
+			if _, ok := t.desired[obj.GetName()]; ok {
+				continue
+			}
+
+			ref := fmt.Sprintf("%s %s/%s", kindOf(t.list), obj.GetNamespace(), obj.GetName())
+			if !dryRun {
+				if err := c.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !kerrors.IsNotFound(err) {
+					return pruned, fmt.Errorf("delete %s: %w", ref, err)
+				}
+			}
+			pruned = append(pruned, ref)
+		}
+	}
+
+	sort.Strings(pruned)
+	return pruned, nil
+}
+
+func clusterPrunables(a actor.PerClusterActor) []actor.PerClusterPrunable {
+	switch p := a.(type) {
+	case actor.PerClusterMultiPrunable:
+		return p.Prunables()
+	case actor.PerClusterPrunable:
+		return []actor.PerClusterPrunable{p}
+	}
+	return nil
+}
+
+func kindOf(list client.ObjectList) string {
+	if u, ok := list.(*unstructured.UnstructuredList); ok {
+		return strings.TrimSuffix(u.GetKind(), "List")
+	}
+	return strings.TrimSuffix(reflect.TypeOf(list).Elem().Name(), "List")
+}
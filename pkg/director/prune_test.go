@@ -0,0 +1,149 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package director_test
+
+import (
+	"context"
+	"testing"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/director"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const pruneNS = "ravendb"
+
+func pruneCluster() *ravendbv1.RavenDBCluster {
+	return &ravendbv1.RavenDBCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "ravendb.ravendb.io/v1", Kind: "RavenDBCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: pruneNS, UID: types.UID("cluster-uid")},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Nodes: []ravendbv1.RavenDBNode{{Tag: "a"}, {Tag: "b"}},
+		},
+	}
+}
+
+// managed gives obj the labels Prune lists by and, when owned, the cluster as controller.
+func managed[T client.Object](obj T, name string, cluster *ravendbv1.RavenDBCluster, owned bool) T {
+	obj.SetName(name)
+	obj.SetNamespace(pruneNS)
+	obj.SetLabels(map[string]string{common.LabelInstance: cluster.Name, common.LabelManagedBy: common.Manager})
+	if owned {
+		obj.SetOwnerReferences([]metav1.OwnerReference{*metav1.NewControllerRef(cluster, cluster.GroupVersionKind())})
+	}
+	return obj
+}
+
+func pruneClient(t *testing.T, cluster *ravendbv1.RavenDBCluster) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	require.NoError(t, ravendbv1.AddToScheme(scheme))
+
+	objs := []client.Object{
+		managed(&appsv1.StatefulSet{}, "ravendb-a", cluster, true),
+		managed(&appsv1.StatefulSet{}, "ravendb-c", cluster, true),
+		managed(&appsv1.StatefulSet{}, "ravendb-d", cluster, false),
+		managed(&corev1.Service{}, "ravendb-a", cluster, true),
+		managed(&corev1.Service{}, "ravendb-c", cluster, true),
+		managed(&networkingv1.Ingress{}, common.App, cluster, true),
+	}
+
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			// the cert-manager and prometheus-operator CRDs aren't installed
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if u, ok := list.(*unstructured.UnstructuredList); ok {
+					return &meta.NoKindMatchError{GroupKind: u.GroupVersionKind().GroupKind()}
+				}
+				return c.List(ctx, list, opts...)
+			},
+		}).
+		Build()
+}
+
+func exists(t *testing.T, c client.Client, obj client.Object, name string) bool {
+	t.Helper()
+	err := c.Get(context.Background(), client.ObjectKey{Namespace: pruneNS, Name: name}, obj)
+	if kerrors.IsNotFound(err) {
+		return false
+	}
+	require.NoError(t, err)
+	return true
+}
+
+func TestPrune_DeletesOnlyOwnedObjectsTheSpecDropped(t *testing.T) {
+	cluster := pruneCluster()
+	c := pruneClient(t, cluster)
+
+	pruned, err := director.NewDefaultDirector().Prune(context.Background(), cluster, c, false, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"Ingress ravendb/" + common.App,
+		"Service ravendb/ravendb-c",
+		"StatefulSet ravendb/ravendb-c",
+	}, pruned)
+
+	require.True(t, exists(t, c, &appsv1.StatefulSet{}, "ravendb-a"))
+	require.True(t, exists(t, c, &corev1.Service{}, "ravendb-a"))
+	require.True(t, exists(t, c, &appsv1.StatefulSet{}, "ravendb-d"), "objects the cluster doesn't control are never pruned")
+	require.False(t, exists(t, c, &appsv1.StatefulSet{}, "ravendb-c"))
+	require.False(t, exists(t, c, &corev1.Service{}, "ravendb-c"))
+	require.False(t, exists(t, c, &networkingv1.Ingress{}, common.App))
+}
+
+func TestPrune_DryRunDeletesNothing(t *testing.T) {
+	cluster := pruneCluster()
+	c := pruneClient(t, cluster)
+
+	pruned, err := director.NewDefaultDirector().Prune(context.Background(), cluster, c, true, nil)
+	require.NoError(t, err)
+	require.Len(t, pruned, 3)
+
+	require.True(t, exists(t, c, &appsv1.StatefulSet{}, "ravendb-c"))
+	require.True(t, exists(t, c, &corev1.Service{}, "ravendb-c"))
+	require.True(t, exists(t, c, &networkingv1.Ingress{}, common.App))
+}
+
+func TestPrune_KeepsNodesStillInTheRavenDBCluster(t *testing.T) {
+	cluster := pruneCluster()
+	c := pruneClient(t, cluster)
+
+	pruned, err := director.NewDefaultDirector().Prune(context.Background(), cluster, c, false, []string{"C"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Ingress ravendb/" + common.App}, pruned)
+
+	require.True(t, exists(t, c, &appsv1.StatefulSet{}, "ravendb-c"))
+	require.True(t, exists(t, c, &corev1.Service{}, "ravendb-c"))
+}
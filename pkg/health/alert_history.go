@@ -0,0 +1,138 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"sync"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// AlertHistory keeps every active alert of each cluster between reconciles, since
+// status.alerts only lists maxAlertItems of them. The evaluator stages the alerts it
+// computed; Commit makes them the active set once the status was written. It also
+// remembers when the collector last read each cluster's notification centers.
+type AlertHistory struct {
+	mu      sync.Mutex
+	active  map[types.NamespacedName][]ravendbv1.AlertSummary
+	pending map[types.NamespacedName]stagedAlerts
+	polled  map[types.NamespacedName]time.Time
+}
+
+type stagedAlerts struct {
+	prev, cur []ravendbv1.AlertSummary
+}
+
+func NewAlertHistory() *AlertHistory {
+	return &AlertHistory{
+		active:  map[types.NamespacedName][]ravendbv1.AlertSummary{},
+		pending: map[types.NamespacedName]stagedAlerts{},
+		polled:  map[types.NamespacedName]time.Time{},
+	}
+}
+
+// pollDue reports whether the cluster's notification centers are due to be read again, and
+// if so records now as the time they were. Without a history they always are.
+func (h *AlertHistory) pollDue(cluster *ravendbv1.RavenDBCluster, now time.Time) bool {
+	if h == nil {
+		return true
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := clusterKey(cluster)
+	if last, ok := h.polled[key]; ok && now.Sub(last) < alertsPollInterval {
+		return false
+	}
+	h.polled[key] = now
+	return true
+}
+
+// previous returns the active alerts of the cluster. Before anything was committed (e.g.
+// after an operator restart) that's what its status lists.
+func (h *AlertHistory) previous(cluster *ravendbv1.RavenDBCluster) []ravendbv1.AlertSummary {
+	if h != nil {
+		h.mu.Lock()
+		items, ok := h.active[clusterKey(cluster)]
+		h.mu.Unlock()
+		if ok {
+			return items
+		}
+	}
+	if cluster.Status.Alerts == nil {
+		return nil
+	}
+	return cluster.Status.Alerts.Items
+}
+
+func (h *AlertHistory) stage(cluster *ravendbv1.RavenDBCluster, prev, cur []ravendbv1.AlertSummary) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.pending[clusterKey(cluster)] = stagedAlerts{prev: prev, cur: cur}
+}
+
+// Commit makes the alerts staged for the cluster the active ones and returns those that
+// weren't active before. Nothing staged returns nil.
+func (h *AlertHistory) Commit(key types.NamespacedName) []ravendbv1.AlertSummary {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	staged, ok := h.pending[key]
+	delete(h.pending, key)
+	if ok {
+		h.active[key] = staged.cur
+	}
+	h.mu.Unlock()
+
+	seen := make(map[string]struct{}, len(staged.prev))
+	for _, a := range staged.prev {
+		seen[alertKey(a)] = struct{}{}
+	}
+	var raised []ravendbv1.AlertSummary
+	for _, a := range staged.cur {
+		if _, ok := seen[alertKey(a)]; !ok {
+			raised = append(raised, a)
+		}
+	}
+	return raised
+}
+
+// Forget drops a deleted cluster.
+func (h *AlertHistory) Forget(key types.NamespacedName) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.active, key)
+	delete(h.pending, key)
+	delete(h.polled, key)
+}
+
+func clusterKey(cluster *ravendbv1.RavenDBCluster) types.NamespacedName {
+	return types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+}
+
+func alertKey(a ravendbv1.AlertSummary) string {
+	return a.Node + "/" + a.Database + "/" + a.ID
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAlertHistory_PollDue(t *testing.T) {
+	raven := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"}}
+	other := &ravendbv1.RavenDBCluster{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "ravendb"}}
+	now := time.Now()
+
+	h := NewAlertHistory()
+	require.True(t, h.pollDue(raven, now))
+	require.False(t, h.pollDue(raven, now.Add(10*time.Second)), "reconciles in between keep the last read")
+	require.True(t, h.pollDue(other, now), "each cluster has its own interval")
+	require.True(t, h.pollDue(raven, now.Add(alertsPollInterval)))
+
+	h.Forget(clusterKey(raven))
+	require.True(t, h.pollDue(raven, now.Add(alertsPollInterval+time.Second)))
+
+	var none *AlertHistory
+	require.True(t, none.pollDue(raven, now))
+	require.True(t, none.pollDue(raven, now))
+}
@@ -18,6 +18,7 @@ package health
 
 import (
 	"context"
+	"time"
 
 	ravendbv1 "ravendb-operator/api/v1"
 
@@ -36,6 +37,9 @@ type ResourceFacts struct {
 	Ingresses    []IngressFact
 	Jobs         []JobFact
 	Secrets      []SecretFact
+	Certificates []CertificateFact
+	License      *LicenseFact
+	RavenDB      *RavenDBFacts
 }
 
 type StatefulSetFact struct {
@@ -51,9 +55,14 @@ type StatefulSetFact struct {
 type PodFact struct {
 	Name      string
 	Namespace string
+	Tag       string
 	Phase     string
 	Ready     bool
 	Restarts  int32
+	// CrashLooping lists containers waiting in CrashLoopBackOff; OOMKilledAt is the
+	// latest OOM kill among the containers' current and last terminations (zero if none).
+	CrashLooping []string
+	OOMKilledAt  time.Time
 }
 
 type PVCFact struct {
@@ -88,8 +97,95 @@ type JobFact struct {
 	Completed bool
 }
 
+// RavenDBFacts is what the RavenDB cluster itself reports. nil until bootstrap completed.
+type RavenDBFacts struct {
+	Leader string
+	Nodes  []RavenDBNodeFact
+	Error  string
+
+	Databases      []DatabaseFact
+	DatabasesError string
+
+	// AlertsFailedNodes are nodes whose notification centers couldn't be read this time.
+	AlertsPolled      bool
+	Alerts            []AlertFact
+	AlertsFailedNodes []string
+
+	// Storage is only collected while spec.health.minFreeStoragePercent is on.
+	Storage []NodeStorageFact
+
+	// License is the license the first node that answered runs with.
+	License      *LicenseStatusFact
+	LicenseError string
+}
+
+// LicenseStatusFact is a node's /license/status. Zero limits weren't reported.
+type LicenseStatusFact struct {
+	Id             string
+	LicensedTo     string
+	Type           string
+	Expiration     time.Time
+	Expired        bool
+	MaxCores       int
+	MaxMemoryGB    int
+	MaxClusterSize int
+}
+
+type NodeStorageFact struct {
+	Tag         string
+	FreeMB      int64
+	FreePercent int
+	Error       string
+}
+
+type AlertFact struct {
+	ID        string
+	Node      string
+	Database  string
+	Severity  string
+	AlertType string
+	Title     string
+	Message   string
+}
+
+type DatabaseFact struct {
+	Name              string
+	Disabled          bool
+	ReplicationFactor int
+	Members           []string
+	Rehabs            []string
+	NotOk             []string
+	LastError         string
+}
+
+type RavenDBNodeFact struct {
+	Tag       string
+	Role      ravendbv1.RavenDBNodeRole
+	Connected bool
+	Version   string
+	Error     string
+}
+
 type SecretFact struct {
 	Name      string
 	Namespace string
 	Type      string
 }
+
+// LicenseFact is the license.json of licenseSecretRef. Error is set when it couldn't be read.
+type LicenseFact struct {
+	Id    string
+	Name  string
+	Error string
+}
+
+// CertificateFact is a certificate decoded from one key of a referenced secret.
+// Error is set (and the rest empty) when the key couldn't be decoded.
+type CertificateFact struct {
+	Secret   string
+	Key      string
+	Subject  string
+	DNSNames []string
+	NotAfter time.Time
+	Error    string
+}
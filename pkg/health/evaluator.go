@@ -18,12 +18,16 @@ package health
 
 import (
 	"context"
+	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -31,7 +35,9 @@ type Evaluator interface {
 	Evaluate(ctx context.Context, cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time)
 }
 
-type evaluator struct{}
+type evaluator struct {
+	alerts *AlertHistory
+}
 
 type conditionResult struct {
 	status  metav1.ConditionStatus
@@ -44,16 +50,36 @@ func NewEvaluator() Evaluator {
 	return &evaluator{}
 }
 
+// NewEvaluatorWithAlertHistory carries the full set of active alerts over in alerts, and
+// stages the new one there for the caller to commit.
+func NewEvaluatorWithAlertHistory(alerts *AlertHistory) Evaluator {
+	return &evaluator{alerts: alerts}
+}
+
 func (e *evaluator) Evaluate(_ context.Context, cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) {
 
+	e.applyRestartHistory(cluster, res, now)
+
 	e.apply(cluster, ravendbv1.ConditionStorageReady, e.evalStorage(cluster, res), now)
-	e.apply(cluster, ravendbv1.ConditionCertificatesReady, e.evalCertificates(cluster, res), now)
-	e.apply(cluster, ravendbv1.ConditionLicensesValid, e.evalLicense(cluster, res), now)
+	e.apply(cluster, ravendbv1.ConditionCertificatesReady, e.evalCertificates(cluster, res, now), now)
+	e.apply(cluster, ravendbv1.ConditionCertificatesExpiring, e.evalCertificatesExpiring(cluster, res, now), now)
+	e.applyLicense(cluster, res)
+	e.apply(cluster, ravendbv1.ConditionLicensesValid, e.evalLicense(cluster, res, now), now)
+	e.apply(cluster, ravendbv1.ConditionLicenseExpiring, e.evalLicenseExpiring(cluster, now), now)
+	e.apply(cluster, ravendbv1.ConditionLicenseLimitsExceeded, e.evalLicenseLimitsExceeded(cluster), now)
 	e.apply(cluster, ravendbv1.ConditionNodesHealthy, e.evalNodesHealthy(cluster, res), now)
+	e.apply(cluster, ravendbv1.ConditionClusterFormed, e.evalClusterFormed(cluster, res), now)
+	e.apply(cluster, ravendbv1.ConditionDatabasesHealthy, e.evalDatabasesHealthy(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionExternalAccessReady, e.evalExternalAccessReady(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionBootstrapCompleted, e.evalBootstrap(cluster, res), now)
 	e.apply(cluster, ravendbv1.ConditionProgressing, e.evalProgressingCase(cluster, res), now)
-	e.apply(cluster, ravendbv1.ConditionDegraded, e.evalDegradingCase(cluster, res), now)
+	e.apply(cluster, ravendbv1.ConditionDegraded, e.evalDegradingCase(cluster, res, now), now)
+	e.apply(cluster, ravendbv1.ConditionReconcilePaused, e.evalReconcilePaused(cluster), now)
+
+	e.applyRavenDBNodeStatus(cluster, res)
+	e.applyDatabaseSummaries(cluster, res)
+	e.applyAlerts(cluster, res)
+	e.applyCertificates(cluster, res)
 
 	cluster.SetObservedGeneration(cluster.Generation)
 	cluster.ComputeReady(now)
@@ -118,7 +144,7 @@ func (e *evaluator) evalBootstrap(cluster *ravendbv1.RavenDBCluster, res *Resour
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapJobRunning, message: "bootstrap job still running"}
 }
 
-func (e *evaluator) evalCertificates(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+func (e *evaluator) evalCertificates(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) conditionResult {
 
 	if res == nil {
 		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCertSecretMissing, message: "waiting for certificate secrets to be observed"}
@@ -136,9 +162,73 @@ func (e *evaluator) evalCertificates(cluster *ravendbv1.RavenDBCluster, res *Res
 		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCertSecretMissing, message: "missing certificate secrets: " + joinNames(missingSecrets)}
 	}
 
+	if expired := expiringCertificates(res.Certificates, now.Time); len(expired) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCertificateExpired, message: "expired certificates: " + joinNames(expired)}
+	}
+
 	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "all certificate secrets present"}
 }
 
+// CertificatesExpiring=True when a certificate in the referenced secrets expires within
+// spec.health.certificateExpiryWarningDays (or already has).
+func (e *evaluator) evalCertificatesExpiring(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) conditionResult {
+
+	days := cluster.Spec.Health.GetCertificateExpiryWarningDays()
+	if days == 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "certificate expiry warning disabled"}
+	}
+	if res == nil {
+		return conditionResult{skip: true}
+	}
+
+	if expired := expiringCertificates(res.Certificates, now.Time); len(expired) > 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCertificateExpired, message: "expired: " + joinNames(expired)}
+	}
+
+	horizon := now.Add(time.Duration(days) * 24 * time.Hour)
+	if expiring := expiringCertificates(res.Certificates, horizon); len(expiring) > 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCertificateExpiring, message: fmt.Sprintf("expiring within %d days: %s", days, joinNames(expiring))}
+	}
+
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: fmt.Sprintf("no certificate expires within %d days", days)}
+}
+
+// lists "secret/key (expires <date>)" for every decoded certificate whose NotAfter is before t.
+func expiringCertificates(certs []CertificateFact, t time.Time) []string {
+	var out []string
+	for _, c := range certs {
+		if c.Error == "" && c.NotAfter.Before(t) {
+			out = append(out, fmt.Sprintf("%s/%s (expires %s)", c.Secret, c.Key, c.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+	return out
+}
+
+// publishes what was decoded from the referenced secrets.
+func (e *evaluator) applyCertificates(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+
+	if res == nil {
+		return
+	}
+
+	var out []ravendbv1.CertificateStatus
+	for _, c := range res.Certificates {
+		st := ravendbv1.CertificateStatus{SecretName: c.Secret, Key: c.Key, Subject: c.Subject, DNSNames: c.DNSNames, Error: c.Error}
+		if !c.NotAfter.IsZero() {
+			notAfter := metav1.NewTime(c.NotAfter)
+			st.NotAfter = &notAfter
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].SecretName != out[j].SecretName {
+			return out[i].SecretName < out[j].SecretName
+		}
+		return out[i].Key < out[j].Key
+	})
+	cluster.Status.Certificates = out
+}
+
 func (e *evaluator) evalNodesHealthy(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
 
 	if res == nil || len(res.Pods) == 0 {
@@ -173,7 +263,215 @@ func (e *evaluator) evalNodesHealthy(cluster *ravendbv1.RavenDBCluster, res *Res
 	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "all node pods ready"}
 }
 
-func (e *evaluator) evalLicense(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+// ClusterFormed=True when RavenDB has a leader and every spec node is part of its
+// topology and connected. pod readiness alone can't tell a node was kicked out.
+func (e *evaluator) evalClusterFormed(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+
+	if res == nil || res.RavenDB == nil {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapJobRunning, message: "waiting for bootstrap before reading cluster topology"}
+	}
+
+	rdb := res.RavenDB
+	if rdb.Error != "" {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonTopologyUnavailable, message: "cluster topology unavailable: " + rdb.Error}
+	}
+	if rdb.Leader == "" {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonTopologyUnavailable, message: "no leader elected"}
+	}
+
+	notInCluster := make([]string, 0, len(rdb.Nodes))
+	disconnected := make([]string, 0, len(rdb.Nodes))
+
+	for i := 0; i < len(rdb.Nodes); i++ {
+		n := rdb.Nodes[i]
+		if n.Role == ravendbv1.NodeRoleNotInCluster {
+			notInCluster = append(notInCluster, n.Tag)
+			continue
+		}
+		if !n.Connected {
+			disconnected = append(disconnected, n.Tag)
+		}
+	}
+
+	if len(notInCluster) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonNodesNotInCluster, message: "nodes not in cluster topology: " + joinNames(notInCluster)}
+	}
+	if len(disconnected) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonNodesDisconnected, message: "nodes disconnected from leader " + rdb.Leader + ": " + joinNames(disconnected)}
+	}
+
+	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "cluster formed with leader " + rdb.Leader}
+}
+
+// copies role/connectivity/version onto the node statuses the upgrader produced.
+// when the topology couldn't be read the previous values are kept.
+func (e *evaluator) applyRavenDBNodeStatus(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+
+	if res == nil || res.RavenDB == nil || res.RavenDB.Error != "" {
+		return
+	}
+
+	byTag := make(map[string]RavenDBNodeFact, len(res.RavenDB.Nodes))
+	for _, n := range res.RavenDB.Nodes {
+		byTag[strings.ToUpper(n.Tag)] = n
+	}
+
+	for i := range cluster.Status.Nodes {
+		st := &cluster.Status.Nodes[i]
+		fact, ok := byTag[strings.ToUpper(st.Tag)]
+		if !ok {
+			continue
+		}
+		connected := fact.Connected
+		st.Role = fact.Role
+		st.Connected = &connected
+		st.ServerVersion = fact.Version
+	}
+}
+
+// DatabasesHealthy=True when every enabled database group has all its nodes Ok and none in rehab.
+func (e *evaluator) evalDatabasesHealthy(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+
+	if res == nil || res.RavenDB == nil {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonBootstrapJobRunning, message: "waiting for bootstrap before reading databases"}
+	}
+
+	rdb := res.RavenDB
+	if rdb.DatabasesError != "" {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonDatabasesUnavailable, message: "databases unavailable: " + rdb.DatabasesError}
+	}
+	if len(rdb.Databases) == 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "no databases"}
+	}
+
+	degraded := make([]string, 0, len(rdb.Databases))
+	for i := 0; i < len(rdb.Databases); i++ {
+		db := rdb.Databases[i]
+		if db.Disabled {
+			continue
+		}
+		switch {
+		case len(db.Rehabs) > 0:
+			degraded = append(degraded, db.Name+" (rehab: "+strings.Join(db.Rehabs, ",")+")")
+		case len(db.NotOk) > 0:
+			degraded = append(degraded, db.Name+" (not ok: "+strings.Join(db.NotOk, ",")+")")
+		}
+	}
+
+	if len(degraded) > 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonDatabasesDegraded, message: "databases degraded: " + joinNames(degraded)}
+	}
+
+	return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "all databases healthy"}
+}
+
+// publishes the per-database summary; keeps the previous one while /databases can't be read.
+func (e *evaluator) applyDatabaseSummaries(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+
+	if res == nil || res.RavenDB == nil || res.RavenDB.DatabasesError != "" {
+		return
+	}
+
+	summaries := make([]ravendbv1.DatabaseSummary, 0, len(res.RavenDB.Databases))
+	for _, db := range res.RavenDB.Databases {
+		summaries = append(summaries, ravendbv1.DatabaseSummary{
+			Name:              db.Name,
+			ReplicationFactor: db.ReplicationFactor,
+			Members:           db.Members,
+			Rehabs:            db.Rehabs,
+			Disabled:          db.Disabled,
+			LastError:         db.LastError,
+		})
+	}
+	if len(summaries) == 0 {
+		summaries = nil
+	}
+	cluster.Status.Databases = summaries
+}
+
+const maxAlertItems = 50
+
+// publishes the active notification-center alerts at or above spec.alerts.minSeverity.
+// alerts of nodes we couldn't read this round are carried over so they don't flap; with an
+// alert history that includes the ones beyond maxAlertItems.
+func (e *evaluator) applyAlerts(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+
+	if cluster.Spec.Alerts != nil && cluster.Spec.Alerts.Disabled {
+		cluster.Status.Alerts = nil
+		e.alerts.stage(cluster, nil, nil)
+		return
+	}
+	if res == nil || res.RavenDB == nil || !res.RavenDB.AlertsPolled {
+		return
+	}
+
+	minRank := severityRank(string(ravendbv1.AlertSeverityWarning))
+	if cluster.Spec.Alerts != nil && cluster.Spec.Alerts.MinSeverity != "" {
+		minRank = severityRank(string(cluster.Spec.Alerts.MinSeverity))
+	}
+
+	failed := make(map[string]struct{}, len(res.RavenDB.AlertsFailedNodes))
+	for _, n := range res.RavenDB.AlertsFailedNodes {
+		failed[n] = struct{}{}
+	}
+
+	items := make([]ravendbv1.AlertSummary, 0, len(res.RavenDB.Alerts))
+	for _, a := range res.RavenDB.Alerts {
+		if _, skip := failed[a.Node]; skip || severityRank(a.Severity) < minRank {
+			continue
+		}
+		items = append(items, ravendbv1.AlertSummary{
+			ID:        a.ID,
+			Node:      a.Node,
+			Database:  a.Database,
+			Severity:  a.Severity,
+			AlertType: a.AlertType,
+			Title:     a.Title,
+			Message:   a.Message,
+		})
+	}
+	prev := e.alerts.previous(cluster)
+	for _, a := range prev {
+		if _, keep := failed[a.Node]; keep && severityRank(a.Severity) >= minRank {
+			items = append(items, a)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Node != items[j].Node {
+			return items[i].Node < items[j].Node
+		}
+		if items[i].Database != items[j].Database {
+			return items[i].Database < items[j].Database
+		}
+		return items[i].ID < items[j].ID
+	})
+
+	e.alerts.stage(cluster, prev, items)
+
+	status := &ravendbv1.AlertsStatus{Active: len(items)}
+	if len(items) > maxAlertItems {
+		status.Items = items[:maxAlertItems:maxAlertItems]
+	} else if len(items) > 0 {
+		status.Items = items
+	}
+	cluster.Status.Alerts = status
+}
+
+// RavenDB severities: None, Info, Success, Warning, Error.
+func severityRank(s string) int {
+	switch strings.ToLower(s) {
+	case "error":
+		return 3
+	case "warning":
+		return 2
+	case "info", "success":
+		return 1
+	}
+	return 0
+}
+
+func (e *evaluator) evalLicense(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) conditionResult {
 
 	license := cluster.Spec.LicenseSecretRef
 	if license == "" {
@@ -185,14 +483,88 @@ func (e *evaluator) evalLicense(cluster *ravendbv1.RavenDBCluster, res *Resource
 	}
 
 	for i := 0; i < len(res.Secrets); i++ {
-		if res.Secrets[i].Name == license {
-			return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "license secret present"}
+		if res.Secrets[i].Name != license {
+			continue
+		}
+		if res.License != nil && res.License.Error != "" {
+			return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonLicenseInvalid, message: res.License.Error}
+		}
+		if lic := cluster.Status.License; lic != nil && lic.Expiration != nil && !lic.Expiration.After(now.Time) {
+			return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonLicenseExpired, message: "license expired " + lic.Expiration.UTC().Format(time.RFC3339)}
 		}
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonCompleted, message: "license secret present"}
 	}
 
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonLicenseSecretMissing, message: "missing license secret: " + cluster.Namespace + "/" + license}
 }
 
+// publishes license.json's id and licensee, and the type, expiration and limits RavenDB
+// reports. when RavenDB didn't answer this time the last reported values stay.
+func (e *evaluator) applyLicense(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) {
+
+	if cluster.Spec.LicenseSecretRef == "" {
+		cluster.Status.License = nil
+		return
+	}
+	if res == nil || res.License == nil {
+		return
+	}
+
+	st := &ravendbv1.LicenseStatus{}
+	if prev := cluster.Status.License; prev != nil {
+		*st = *prev
+	}
+	st.Id, st.LicensedTo, st.Error = res.License.Id, res.License.Name, res.License.Error
+
+	if rdb := res.RavenDB; rdb != nil && rdb.License != nil {
+		l := rdb.License
+		st.ActiveId = l.Id
+		st.Type = l.Type
+		st.Expiration = nil
+		if !l.Expiration.IsZero() {
+			exp := metav1.NewTime(l.Expiration)
+			st.Expiration = &exp
+		}
+		st.MaxCores, st.MaxMemoryGB, st.MaxClusterSize = int32(l.MaxCores), int32(l.MaxMemoryGB), int32(l.MaxClusterSize)
+	} else if rdb != nil && rdb.LicenseError != "" && st.Error == "" {
+		st.Error = "license status: " + rdb.LicenseError
+	}
+	cluster.Status.License = st
+}
+
+// LicenseExpiring=True when the license expires within spec.health.licenseExpiryWarningDays
+// (or already has).
+func (e *evaluator) evalLicenseExpiring(cluster *ravendbv1.RavenDBCluster, now metav1.Time) conditionResult {
+
+	days := cluster.Spec.Health.GetLicenseExpiryWarningDays()
+	if days == 0 {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "license expiry warning disabled"}
+	}
+	lic := cluster.Status.License
+	if lic == nil || lic.Expiration == nil {
+		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no license expiration reported"}
+	}
+
+	expires := lic.Expiration.UTC().Format(time.RFC3339)
+	if !lic.Expiration.After(now.Time) {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonLicenseExpired, message: "license expired " + expires}
+	}
+	if lic.Expiration.Time.Before(now.Add(time.Duration(days) * 24 * time.Hour)) {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonLicenseExpiring, message: fmt.Sprintf("license expires within %d days (%s)", days, expires)}
+	}
+
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "license expires " + expires}
+}
+
+// LicenseLimitsExceeded=True when the spec runs more nodes than the license covers.
+func (e *evaluator) evalLicenseLimitsExceeded(cluster *ravendbv1.RavenDBCluster) conditionResult {
+
+	if msg := cluster.Status.License.NodesCovered(len(cluster.Spec.Nodes)); msg != "" {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonLicenseLimitsExceeded, message: msg}
+	}
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "license covers the cluster"}
+}
+
 func (e *evaluator) evalExternalAccessReady(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
 
 	// only when external access is configured.
@@ -244,8 +616,11 @@ func (e *evaluator) evalProgressingCase(cluster *ravendbv1.RavenDBCluster, res *
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no active rollouts"}
 }
 
-// Degraded=True when bootstrap job failed or pods have high restart counts.
-func (e *evaluator) evalDegradingCase(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts) conditionResult {
+// Degraded=True when the bootstrap job failed, or any of the spec.health checks trips:
+// containers crash-looping or OOM-killed within the restart window, a node restarting
+// restartThreshold times within it, RavenDB reporting too little free storage on a node,
+// or a PVC provisioned below its request. the first of those found gives the reason.
+func (e *evaluator) evalDegradingCase(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) conditionResult {
 
 	if c, ok := cluster.GetCondition(ravendbv1.ConditionBootstrapCompleted); ok &&
 		c.Status == metav1.ConditionFalse && c.Reason == string(ravendbv1.ReasonBootstrapFailed) {
@@ -256,23 +631,143 @@ func (e *evaluator) evalDegradingCase(cluster *ravendbv1.RavenDBCluster, res *Re
 		return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no degradation detected"}
 	}
 
-	const restartThreshold int32 = 5
-	offendersPods := make([]string, 0, len(res.Pods))
+	h := cluster.Spec.Health
+	window := h.GetRestartWindow()
 
-	for i := 0; i < len(res.Pods); i++ {
-		pod := res.Pods[i]
-		if pod.Restarts >= restartThreshold {
-			offendersPods = append(offendersPods, pod.Namespace+"/"+pod.Name)
+	var (
+		reason   ravendbv1.ClusterConditionReason
+		findings []string
+	)
+	found := func(r ravendbv1.ClusterConditionReason, msg string) {
+		if reason == "" {
+			reason = r
 		}
+		findings = append(findings, msg)
 	}
 
-	if len(offendersPods) > 0 {
-		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonPodsNotReady, message: "high restart count: " + joinNames(offendersPods)}
+	if h == nil || !h.IgnoreCrashLoopBackOff {
+		for _, pod := range res.Pods {
+			if len(pod.CrashLooping) > 0 {
+				found(ravendbv1.ReasonCrashLoopBackOff, fmt.Sprintf("%s/%s in CrashLoopBackOff (%s)", pod.Namespace, pod.Name, strings.Join(pod.CrashLooping, ", ")))
+			}
+		}
+	}
+
+	if h == nil || !h.IgnoreOOMKilled {
+		for _, pod := range res.Pods {
+			if !pod.OOMKilledAt.IsZero() && now.Sub(pod.OOMKilledAt) <= window {
+				found(ravendbv1.ReasonOOMKilled, fmt.Sprintf("%s/%s OOMKilled at %s", pod.Namespace, pod.Name, pod.OOMKilledAt.UTC().Format(time.RFC3339)))
+			}
+		}
+	}
+
+	if threshold := h.GetRestartThreshold(); threshold > 0 {
+		for _, n := range cluster.Status.Nodes {
+			if restarts := int32(len(n.RecentRestarts)); restarts >= threshold {
+				found(ravendbv1.ReasonRestartThreshold, fmt.Sprintf("node %s restarted %d times within %s", n.Tag, restarts, window))
+			}
+		}
+	}
+
+	if minFree := h.GetMinFreeStoragePercent(); minFree > 0 && res.RavenDB != nil {
+		for _, st := range res.RavenDB.Storage {
+			if st.Error == "" && st.FreePercent < int(minFree) {
+				found(ravendbv1.ReasonStorageLow, fmt.Sprintf("node %s has %d%% (%d MB) storage free, below %d%%", st.Tag, st.FreePercent, st.FreeMB, minFree))
+			}
+		}
+	}
+
+	for _, pvc := range res.PVCs {
+		if below, msg := pvcBelowRequest(pvc); below {
+			found(ravendbv1.ReasonPVCCapacity, msg)
+		}
+	}
+
+	if len(findings) > 0 {
+		return conditionResult{status: metav1.ConditionTrue, reason: reason, message: strings.Join(findings, "; ")}
 	}
 
 	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "no degradation detected"}
 }
 
+// a bound PVC whose capacity is below its request was shrunk by the provisioner or is
+// stuck resizing; RavenDB will run out of space earlier than the spec says.
+func pvcBelowRequest(pvc PVCFact) (bool, string) {
+	if !pvc.Bound || pvc.RequestedSize == "" || pvc.ActualSize == "" {
+		return false, ""
+	}
+	requested, err1 := resource.ParseQuantity(pvc.RequestedSize)
+	actual, err2 := resource.ParseQuantity(pvc.ActualSize)
+	if err1 != nil || err2 != nil || actual.Cmp(requested) >= 0 {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%s/%s capacity %s below requested %s", pvc.Namespace, pvc.Name, pvc.ActualSize, pvc.RequestedSize)
+}
+
+// caps the restart history kept on a node status, whatever the window.
+const maxRestartHistory = 50
+
+// keeps the restart history on the node statuses: restarts that appeared since the last
+// observation are stamped with now, and stamps older than the restart window fall off.
+func (e *evaluator) applyRestartHistory(cluster *ravendbv1.RavenDBCluster, res *ResourceFacts, now metav1.Time) {
+
+	if res == nil {
+		return
+	}
+
+	byTag := make(map[string]PodFact, len(res.Pods))
+	for _, p := range res.Pods {
+		if p.Tag != "" {
+			byTag[strings.ToUpper(p.Tag)] = p
+		}
+	}
+
+	cutoff := now.Add(-cluster.Spec.Health.GetRestartWindow())
+
+	for i := range cluster.Status.Nodes {
+		st := &cluster.Status.Nodes[i]
+
+		history := make([]metav1.Time, 0, len(st.RecentRestarts))
+		for _, t := range st.RecentRestarts {
+			if t.Time.After(cutoff) {
+				history = append(history, t)
+			}
+		}
+
+		if pod, ok := byTag[strings.ToUpper(st.Tag)]; ok {
+			if st.Restarts != nil {
+				added := pod.Restarts - *st.Restarts
+				if added < 0 {
+					added = pod.Restarts // the pod was replaced and its count started over
+				}
+				for j := int32(0); j < added; j++ {
+					history = append(history, now)
+				}
+			}
+			restarts := pod.Restarts
+			st.Restarts = &restarts
+		}
+
+		if len(history) > maxRestartHistory {
+			history = history[len(history)-maxRestartHistory:]
+		}
+		if len(history) == 0 {
+			history = nil
+		}
+		st.RecentRestarts = history
+	}
+}
+
+// ReconcilePaused=True while the reconcile-paused annotation is set. Actors and the
+// upgrader are skipped, so everything else above reflects the cluster as it is.
+func (e *evaluator) evalReconcilePaused(cluster *ravendbv1.RavenDBCluster) conditionResult {
+	if common.IsReconcilePaused(cluster) {
+		return conditionResult{status: metav1.ConditionTrue, reason: ravendbv1.ReasonReconcilePaused, message: "reconciliation paused by " + common.ReconcilePausedAnnotation + " annotation"}
+	}
+
+	return conditionResult{status: metav1.ConditionFalse, reason: ravendbv1.ReasonCompleted, message: "reconciliation active"}
+}
+
 func getExpectedSecretNames(cluster *ravendbv1.RavenDBCluster) []string {
 	secretsList := []string{}
 
@@ -0,0 +1,376 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/health"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func evaluate(t *testing.T, cluster *ravendbv1.RavenDBCluster, res *health.ResourceFacts) metav1.Condition {
+	t.Helper()
+	health.NewEvaluator().Evaluate(context.Background(), cluster, res, metav1.Now())
+	cond, ok := cluster.GetCondition(ravendbv1.ConditionClusterFormed)
+	require.True(t, ok)
+	return *cond
+}
+
+func TestClusterFormed(t *testing.T) {
+	nodes := func(roles ...ravendbv1.RavenDBNodeRole) []health.RavenDBNodeFact {
+		out := []health.RavenDBNodeFact{}
+		for i, r := range roles {
+			out = append(out, health.RavenDBNodeFact{Tag: string(rune('A' + i)), Role: r, Connected: true, Version: "6.2.3"})
+		}
+		return out
+	}
+
+	cases := []struct {
+		name       string
+		rdb        *health.RavenDBFacts
+		wantStatus metav1.ConditionStatus
+		wantReason ravendbv1.ClusterConditionReason
+	}{
+		{"not polled yet", nil, metav1.ConditionFalse, ravendbv1.ReasonBootstrapJobRunning},
+		{"unreachable", &health.RavenDBFacts{Error: "connection refused"}, metav1.ConditionFalse, ravendbv1.ReasonTopologyUnavailable},
+		{"no leader", &health.RavenDBFacts{Nodes: nodes(ravendbv1.NodeRoleMember)}, metav1.ConditionFalse, ravendbv1.ReasonTopologyUnavailable},
+		{"node kicked out", &health.RavenDBFacts{Leader: "A", Nodes: nodes(ravendbv1.NodeRoleLeader, ravendbv1.NodeRoleNotInCluster)}, metav1.ConditionFalse, ravendbv1.ReasonNodesNotInCluster},
+		{"formed", &health.RavenDBFacts{Leader: "A", Nodes: nodes(ravendbv1.NodeRoleLeader, ravendbv1.NodeRoleMember, ravendbv1.NodeRoleWatcher)}, metav1.ConditionTrue, ravendbv1.ReasonCompleted},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := benchCluster()
+			cond := evaluate(t, cluster, &health.ResourceFacts{RavenDB: tc.rdb})
+			require.Equal(t, tc.wantStatus, cond.Status)
+			require.Equal(t, string(tc.wantReason), cond.Reason)
+		})
+	}
+
+	t.Run("disconnected", func(t *testing.T) {
+		rdb := &health.RavenDBFacts{Leader: "A", Nodes: nodes(ravendbv1.NodeRoleLeader, ravendbv1.NodeRoleMember)}
+		rdb.Nodes[1].Connected = false
+		cond := evaluate(t, benchCluster(), &health.ResourceFacts{RavenDB: rdb})
+		require.Equal(t, string(ravendbv1.ReasonNodesDisconnected), cond.Reason)
+		require.Contains(t, cond.Message, "B")
+	})
+}
+
+func TestRavenDBNodeStatus(t *testing.T) {
+	cluster := benchCluster()
+	cluster.Status.Nodes = []ravendbv1.RavenDBNodeStatus{{Tag: "a", Status: ravendbv1.NodeStatusCreated}, {Tag: "b", Status: ravendbv1.NodeStatusCreated}}
+
+	evaluate(t, cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{Leader: "A", Nodes: []health.RavenDBNodeFact{
+		{Tag: "a", Role: ravendbv1.NodeRoleLeader, Connected: true, Version: "6.2.3"},
+		{Tag: "b", Role: ravendbv1.NodeRolePromotable, Connected: false, Version: "6.2.3"},
+	}}})
+
+	require.Equal(t, ravendbv1.NodeRoleLeader, cluster.Status.Nodes[0].Role)
+	require.True(t, *cluster.Status.Nodes[0].Connected)
+	require.Equal(t, "6.2.3", cluster.Status.Nodes[0].ServerVersion)
+	require.Equal(t, ravendbv1.NodeRolePromotable, cluster.Status.Nodes[1].Role)
+	require.False(t, *cluster.Status.Nodes[1].Connected)
+
+	// an unreadable topology keeps what we knew
+	evaluate(t, cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{Error: "timeout"}})
+	require.Equal(t, ravendbv1.NodeRoleLeader, cluster.Status.Nodes[0].Role)
+}
+
+func TestDatabasesHealthy(t *testing.T) {
+	cases := []struct {
+		name       string
+		rdb        *health.RavenDBFacts
+		wantStatus metav1.ConditionStatus
+		wantReason ravendbv1.ClusterConditionReason
+	}{
+		{"not polled yet", nil, metav1.ConditionFalse, ravendbv1.ReasonBootstrapJobRunning},
+		{"unreachable", &health.RavenDBFacts{DatabasesError: "HTTP 503"}, metav1.ConditionFalse, ravendbv1.ReasonDatabasesUnavailable},
+		{"no databases", &health.RavenDBFacts{}, metav1.ConditionTrue, ravendbv1.ReasonCompleted},
+		{"rehab", &health.RavenDBFacts{Databases: []health.DatabaseFact{{Name: "orders", Members: []string{"A"}, Rehabs: []string{"B"}}}}, metav1.ConditionFalse, ravendbv1.ReasonDatabasesDegraded},
+		{"not ok", &health.RavenDBFacts{Databases: []health.DatabaseFact{{Name: "orders", Members: []string{"A", "B"}, NotOk: []string{"B"}}}}, metav1.ConditionFalse, ravendbv1.ReasonDatabasesDegraded},
+		{"disabled is ignored", &health.RavenDBFacts{Databases: []health.DatabaseFact{{Name: "old", Disabled: true, NotOk: []string{"A"}}}}, metav1.ConditionTrue, ravendbv1.ReasonCompleted},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := benchCluster()
+			health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: tc.rdb}, metav1.Now())
+			cond, ok := cluster.GetCondition(ravendbv1.ConditionDatabasesHealthy)
+			require.True(t, ok)
+			require.Equal(t, tc.wantStatus, cond.Status)
+			require.Equal(t, string(tc.wantReason), cond.Reason)
+		})
+	}
+
+	t.Run("summary", func(t *testing.T) {
+		cluster := benchCluster()
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{Databases: []health.DatabaseFact{
+			{Name: "orders", ReplicationFactor: 2, Members: []string{"A"}, Rehabs: []string{"B"}, NotOk: []string{"B"}, LastError: "node=B error=timeout"},
+		}}}, metav1.Now())
+
+		require.Equal(t, []ravendbv1.DatabaseSummary{{
+			Name: "orders", ReplicationFactor: 2, Members: []string{"A"}, Rehabs: []string{"B"}, LastError: "node=B error=timeout",
+		}}, cluster.Status.Databases)
+	})
+}
+
+func TestAlerts(t *testing.T) {
+	alerts := []health.AlertFact{
+		{ID: "AlertRaised/OutOfDisk", Node: "A", Severity: "Error", Title: "low disk"},
+		{ID: "AlertRaised/IndexErrors", Node: "B", Database: "orders", Severity: "Warning", Title: "index errors"},
+		{ID: "AlertRaised/Info", Node: "A", Severity: "Info", Title: "fyi"},
+	}
+
+	t.Run("defaults to warning", func(t *testing.T) {
+		cluster := benchCluster()
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{AlertsPolled: true, Alerts: alerts}}, metav1.Now())
+		require.Equal(t, 2, cluster.Status.Alerts.Active)
+		require.Equal(t, "A", cluster.Status.Alerts.Items[0].Node)
+	})
+
+	t.Run("min severity", func(t *testing.T) {
+		cluster := benchCluster()
+		cluster.Spec.Alerts = &ravendbv1.AlertsSpec{MinSeverity: ravendbv1.AlertSeverityError}
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{AlertsPolled: true, Alerts: alerts}}, metav1.Now())
+		require.Equal(t, 1, cluster.Status.Alerts.Active)
+	})
+
+	t.Run("unreadable node keeps its alerts", func(t *testing.T) {
+		cluster := benchCluster()
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{AlertsPolled: true, Alerts: alerts}}, metav1.Now())
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: &health.RavenDBFacts{AlertsPolled: true, Alerts: alerts[:1], AlertsFailedNodes: []string{"B"}}}, metav1.Now())
+		require.Equal(t, 2, cluster.Status.Alerts.Active)
+	})
+
+	t.Run("history dedupes beyond the listed items", func(t *testing.T) {
+		many := make([]health.AlertFact, 60)
+		for i := range many {
+			many[i] = health.AlertFact{ID: fmt.Sprintf("AlertRaised/%02d", i), Node: "A", Severity: "Warning"}
+		}
+		cluster := benchCluster()
+		key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+		history := health.NewAlertHistory()
+		round := func(facts *health.RavenDBFacts) []ravendbv1.AlertSummary {
+			health.NewEvaluatorWithAlertHistory(history).Evaluate(context.Background(), cluster, &health.ResourceFacts{RavenDB: facts}, metav1.Now())
+			return history.Commit(key)
+		}
+
+		require.Len(t, round(&health.RavenDBFacts{AlertsPolled: true, Alerts: many}), 60)
+		require.Len(t, cluster.Status.Alerts.Items, 50)
+
+		// one of the listed alerts clears: the 51st moves up but isn't new
+		require.Empty(t, round(&health.RavenDBFacts{AlertsPolled: true, Alerts: many[1:]}))
+		require.Equal(t, 59, cluster.Status.Alerts.Active)
+
+		// an unreadable node keeps the alerts status doesn't list too
+		require.Empty(t, round(&health.RavenDBFacts{AlertsPolled: true, AlertsFailedNodes: []string{"A"}}))
+		require.Equal(t, 59, cluster.Status.Alerts.Active)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		cluster := benchCluster()
+		cluster.Status.Alerts = &ravendbv1.AlertsStatus{Active: 1}
+		cluster.Spec.Alerts = &ravendbv1.AlertsSpec{Disabled: true}
+		health.NewEvaluator().Evaluate(context.Background(), cluster, &health.ResourceFacts{}, metav1.Now())
+		require.Nil(t, cluster.Status.Alerts)
+	})
+}
+
+func TestDegraded(t *testing.T) {
+	degraded := func(cluster *ravendbv1.RavenDBCluster, res *health.ResourceFacts, now time.Time) metav1.Condition {
+		t.Helper()
+		health.NewEvaluator().Evaluate(context.Background(), cluster, res, metav1.NewTime(now))
+		cond, ok := cluster.GetCondition(ravendbv1.ConditionDegraded)
+		require.True(t, ok)
+		return *cond
+	}
+	pod := func(restarts int32) health.PodFact {
+		return health.PodFact{Name: "ravendb-a-0", Namespace: benchNS, Tag: "a", Phase: "Running", Ready: true, Restarts: restarts}
+	}
+	now := time.Now()
+
+	t.Run("restarts only count within the window", func(t *testing.T) {
+		cluster := benchCluster()
+		cluster.Status.Nodes = []ravendbv1.RavenDBNodeStatus{{Tag: "a", Status: ravendbv1.NodeStatusCreated}}
+
+		// the first observation is a baseline: a month-old count doesn't degrade
+		cond := degraded(cluster, &health.ResourceFacts{Pods: []health.PodFact{pod(40)}}, now)
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+
+		cond = degraded(cluster, &health.ResourceFacts{Pods: []health.PodFact{pod(45)}}, now.Add(time.Minute))
+		require.Equal(t, metav1.ConditionTrue, cond.Status)
+		require.Equal(t, string(ravendbv1.ReasonRestartThreshold), cond.Reason)
+		require.Len(t, cluster.Status.Nodes[0].RecentRestarts, 5)
+
+		cond = degraded(cluster, &health.ResourceFacts{Pods: []health.PodFact{pod(45)}}, now.Add(2*time.Hour))
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+		require.Empty(t, cluster.Status.Nodes[0].RecentRestarts)
+		require.Equal(t, int32(45), *cluster.Status.Nodes[0].Restarts)
+	})
+
+	t.Run("container states", func(t *testing.T) {
+		crashing := pod(0)
+		crashing.CrashLooping = []string{"ravendb"}
+		cond := degraded(benchCluster(), &health.ResourceFacts{Pods: []health.PodFact{crashing}}, now)
+		require.Equal(t, string(ravendbv1.ReasonCrashLoopBackOff), cond.Reason)
+
+		oom := pod(0)
+		oom.OOMKilledAt = now.Add(-10 * time.Minute)
+		cond = degraded(benchCluster(), &health.ResourceFacts{Pods: []health.PodFact{oom}}, now)
+		require.Equal(t, string(ravendbv1.ReasonOOMKilled), cond.Reason)
+
+		oom.OOMKilledAt = now.Add(-48 * time.Hour)
+		cond = degraded(benchCluster(), &health.ResourceFacts{Pods: []health.PodFact{oom}}, now)
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+
+		cluster := benchCluster()
+		cluster.Spec.Health = &ravendbv1.HealthSpec{IgnoreCrashLoopBackOff: true}
+		cond = degraded(cluster, &health.ResourceFacts{Pods: []health.PodFact{crashing}}, now)
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+	})
+
+	t.Run("storage", func(t *testing.T) {
+		pvc := health.PVCFact{Name: "ravendb-data-ravendb-a-0", Namespace: benchNS, Bound: true, RequestedSize: "10Gi", ActualSize: "5Gi"}
+		cond := degraded(benchCluster(), &health.ResourceFacts{PVCs: []health.PVCFact{pvc}}, now)
+		require.Equal(t, string(ravendbv1.ReasonPVCCapacity), cond.Reason)
+
+		rdb := &health.RavenDBFacts{Storage: []health.NodeStorageFact{{Tag: "a", FreeMB: 300, FreePercent: 3}}}
+		cond = degraded(benchCluster(), &health.ResourceFacts{RavenDB: rdb}, now)
+		require.Equal(t, string(ravendbv1.ReasonStorageLow), cond.Reason)
+
+		off := int32(0)
+		cluster := benchCluster()
+		cluster.Spec.Health = &ravendbv1.HealthSpec{MinFreeStoragePercent: &off}
+		cond = degraded(cluster, &health.ResourceFacts{RavenDB: rdb}, now)
+		require.Equal(t, metav1.ConditionFalse, cond.Status)
+	})
+}
+
+func TestCertificatesExpiring(t *testing.T) {
+	now := time.Now()
+	expiring := func(cluster *ravendbv1.RavenDBCluster, notAfter time.Time) (metav1.Condition, metav1.Condition) {
+		t.Helper()
+		res := &health.ResourceFacts{
+			Secrets:      []health.SecretFact{{Name: "ravendb-client-cert", Namespace: benchNS}, {Name: "ravendb-cluster-cert", Namespace: benchNS}, {Name: "ravendb-ca-cert", Namespace: benchNS}},
+			Certificates: []health.CertificateFact{{Secret: "ravendb-client-cert", Key: "client.pfx", Subject: "CN=admin", NotAfter: notAfter}},
+		}
+		health.NewEvaluator().Evaluate(context.Background(), cluster, res, metav1.NewTime(now))
+		exp, ok := cluster.GetCondition(ravendbv1.ConditionCertificatesExpiring)
+		require.True(t, ok)
+		ready, ok := cluster.GetCondition(ravendbv1.ConditionCertificatesReady)
+		require.True(t, ok)
+		return *exp, *ready
+	}
+
+	exp, _ := expiring(benchCluster(), now.Add(200*24*time.Hour))
+	require.Equal(t, metav1.ConditionFalse, exp.Status)
+
+	cluster := benchCluster()
+	exp, _ = expiring(cluster, now.Add(10*24*time.Hour))
+	require.Equal(t, metav1.ConditionTrue, exp.Status)
+	require.Equal(t, string(ravendbv1.ReasonCertificateExpiring), exp.Reason)
+	require.Contains(t, exp.Message, "ravendb-client-cert/client.pfx")
+	require.Len(t, cluster.Status.Certificates, 1)
+	require.Equal(t, "CN=admin", cluster.Status.Certificates[0].Subject)
+
+	exp, ready := expiring(benchCluster(), now.Add(-time.Hour))
+	require.Equal(t, string(ravendbv1.ReasonCertificateExpired), exp.Reason)
+	require.Equal(t, string(ravendbv1.ReasonCertificateExpired), ready.Reason)
+
+	off := int32(0)
+	cluster = benchCluster()
+	cluster.Spec.Health = &ravendbv1.HealthSpec{CertificateExpiryWarningDays: &off}
+	exp, _ = expiring(cluster, now.Add(time.Hour))
+	require.Equal(t, metav1.ConditionFalse, exp.Status)
+}
+
+func TestLicense(t *testing.T) {
+	now := time.Now()
+	evaluate := func(cluster *ravendbv1.RavenDBCluster, lic *health.LicenseFact, status *health.LicenseStatusFact) {
+		t.Helper()
+		res := &health.ResourceFacts{
+			Secrets: []health.SecretFact{{Name: "ravendb-license", Namespace: benchNS}},
+			License: lic,
+			RavenDB: &health.RavenDBFacts{License: status},
+		}
+		health.NewEvaluator().Evaluate(context.Background(), cluster, res, metav1.NewTime(now))
+	}
+	condition := func(cluster *ravendbv1.RavenDBCluster, ct ravendbv1.ClusterConditionType) metav1.Condition {
+		t.Helper()
+		c, ok := cluster.GetCondition(ct)
+		require.True(t, ok)
+		return *c
+	}
+
+	t.Run("publishes license.json and what RavenDB reports", func(t *testing.T) {
+		cluster := benchCluster()
+		evaluate(cluster, &health.LicenseFact{Id: "new", Name: "Acme"},
+			&health.LicenseStatusFact{Id: "old", Type: "Enterprise", Expiration: now.Add(200 * 24 * time.Hour), MaxCores: 12, MaxMemoryGB: 48, MaxClusterSize: 5})
+
+		lic := cluster.Status.License
+		require.Equal(t, "new", lic.Id)
+		require.Equal(t, "old", lic.ActiveId)
+		require.Equal(t, "Acme", lic.LicensedTo)
+		require.Equal(t, int32(5), lic.MaxClusterSize)
+		require.Equal(t, metav1.ConditionTrue, condition(cluster, ravendbv1.ConditionLicensesValid).Status)
+		require.Equal(t, metav1.ConditionFalse, condition(cluster, ravendbv1.ConditionLicenseExpiring).Status)
+		require.Equal(t, metav1.ConditionFalse, condition(cluster, ravendbv1.ConditionLicenseLimitsExceeded).Status)
+
+		// RavenDB not answering keeps the last reported limits
+		evaluate(cluster, &health.LicenseFact{Id: "new", Name: "Acme"}, nil)
+		require.Equal(t, int32(5), cluster.Status.License.MaxClusterSize)
+	})
+
+	t.Run("expiring and expired", func(t *testing.T) {
+		cluster := benchCluster()
+		evaluate(cluster, &health.LicenseFact{Id: "l"}, &health.LicenseStatusFact{Id: "l", Expiration: now.Add(10 * 24 * time.Hour)})
+		require.Equal(t, string(ravendbv1.ReasonLicenseExpiring), condition(cluster, ravendbv1.ConditionLicenseExpiring).Reason)
+
+		evaluate(cluster, &health.LicenseFact{Id: "l"}, &health.LicenseStatusFact{Id: "l", Expiration: now.Add(-time.Hour), Expired: true})
+		require.Equal(t, string(ravendbv1.ReasonLicenseExpired), condition(cluster, ravendbv1.ConditionLicenseExpiring).Reason)
+		valid := condition(cluster, ravendbv1.ConditionLicensesValid)
+		require.Equal(t, metav1.ConditionFalse, valid.Status)
+		require.Equal(t, string(ravendbv1.ReasonLicenseExpired), valid.Reason)
+	})
+
+	t.Run("more nodes than the license covers", func(t *testing.T) {
+		cluster := benchCluster()
+		evaluate(cluster, &health.LicenseFact{Id: "l"}, &health.LicenseStatusFact{Id: "l", MaxCores: 3, MaxClusterSize: 2})
+		exceeded := condition(cluster, ravendbv1.ConditionLicenseLimitsExceeded)
+		require.Equal(t, metav1.ConditionTrue, exceeded.Status)
+		require.Contains(t, exceeded.Message, "cluster size of 2")
+
+		cluster = benchCluster()
+		evaluate(cluster, &health.LicenseFact{Id: "l"}, &health.LicenseStatusFact{Id: "l", MaxCores: 2, MaxClusterSize: 5})
+		require.Equal(t, metav1.ConditionFalse, condition(cluster, ravendbv1.ConditionLicenseLimitsExceeded).Status, "cores aren't a per-node limit")
+	})
+
+	t.Run("unreadable license.json", func(t *testing.T) {
+		cluster := benchCluster()
+		evaluate(cluster, &health.LicenseFact{Error: "invalid license: unexpected end of JSON input"}, nil)
+		valid := condition(cluster, ravendbv1.ConditionLicensesValid)
+		require.Equal(t, string(ravendbv1.ReasonLicenseInvalid), valid.Reason)
+	})
+}
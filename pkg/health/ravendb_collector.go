@@ -0,0 +1,259 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/upgrade"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// bounds the RavenDB calls of a single reconcile so an unreachable node can't stall it.
+// notification centers get their own budget since each one is a socket we read until quiet.
+const (
+	ravendbCollectTimeout = 10 * time.Second
+	alertsCollectTimeout  = 20 * time.Second
+)
+
+// notification centers read at once; each read waits for the center to go quiet.
+const alertsConcurrency = 8
+
+// how often a cluster's notification centers are read. reconciles in between, e.g. on a pod
+// event, keep the alerts of the last read, so a slow node doesn't hold up every reconcile.
+const alertsPollInterval = time.Minute
+
+// asks RavenDB (over the operator's mTLS client) how it sees the cluster. nodes are only
+// expected to answer once bootstrap completed, so before that we don't call at all.
+// alerts is only read when alerts.pollDue says so.
+func collectRavenDB(ctx context.Context, cli client.Client, cluster *ravendbv1.RavenDBCluster, alerts *AlertHistory) *RavenDBFacts {
+	if c, ok := cluster.GetCondition(ravendbv1.ConditionBootstrapCompleted); !ok || c.Status != metav1.ConditionTrue {
+		return nil
+	}
+
+	rctx, cancel := context.WithTimeout(ctx, ravendbCollectTimeout)
+	defer cancel()
+
+	httpc, err := upgrade.BuildHTTPSClientFromCluster(rctx, cli, cluster)
+	if err != nil {
+		return &RavenDBFacts{Error: err.Error(), DatabasesError: err.Error()}
+	}
+	hcc := upgrade.NewChecks(httpc, cluster)
+
+	tags := make([]string, 0, len(cluster.Spec.Nodes))
+	for _, n := range cluster.Spec.Nodes {
+		tags = append(tags, n.Tag)
+	}
+
+	facts := &RavenDBFacts{}
+	collectDatabases(rctx, hcc, facts)
+	collectTopology(rctx, hcc, tags, facts)
+	collectLicenseStatus(rctx, hcc, tags, facts)
+
+	if cluster.Spec.Health.GetMinFreeStoragePercent() > 0 {
+		collectStorage(rctx, hcc, tags, facts)
+	}
+
+	if (cluster.Spec.Alerts == nil || !cluster.Spec.Alerts.Disabled) && alerts.pollDue(cluster, time.Now()) {
+		actx, cancel := context.WithTimeout(ctx, alertsCollectTimeout)
+		defer cancel()
+		collectAlerts(actx, hcc, tags, facts)
+	}
+
+	return facts
+}
+
+func collectTopology(ctx context.Context, hcc *upgrade.HealthCheckContext, tags []string, facts *RavenDBFacts) {
+	topo, err := hcc.ClusterTopology(ctx, tags)
+	if err != nil {
+		facts.Error = err.Error()
+		return
+	}
+
+	facts.Leader = topo.Leader
+	facts.Nodes = make([]RavenDBNodeFact, 0, len(tags))
+	for _, tag := range tags {
+		t := strings.ToUpper(tag)
+		facts.Nodes = append(facts.Nodes, RavenDBNodeFact{
+			Tag:       tag,
+			Role:      nodeRole(topo, t),
+			Connected: topo.Connected[t],
+			Version:   topo.Versions[t],
+			Error:     topo.Errors[t],
+		})
+	}
+}
+
+// snapshots the server notification center of every node plus, on each node, the
+// notification centers of the databases it is a member of (index errors etc. live there).
+// the centers are read in parallel. a node we couldn't fully read, including one cut off by
+// the deadline, is reported so its previously known alerts are kept.
+func collectAlerts(ctx context.Context, hcc *upgrade.HealthCheckContext, tags []string, facts *RavenDBFacts) {
+	facts.AlertsPolled = true
+
+	type center struct {
+		node     int
+		database string
+	}
+	var centers []center
+	for i, tag := range tags {
+		t := strings.ToUpper(tag)
+		centers = append(centers, center{node: i})
+		for _, db := range facts.Databases {
+			if contains(db.Members, t) {
+				centers = append(centers, center{node: i, database: db.Name})
+			}
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		alerts = make([][]AlertFact, len(tags))
+		failed = make([]bool, len(tags))
+		slots  = make(chan struct{}, alertsConcurrency)
+	)
+	for _, c := range centers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+
+			read, err := hcc.NotificationAlerts(ctx, tags[c.node], c.database)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[c.node] = true
+				return
+			}
+			for _, a := range read {
+				alerts[c.node] = append(alerts[c.node], AlertFact{
+					ID:        a.ID,
+					Node:      a.Node,
+					Database:  a.Database,
+					Severity:  a.Severity,
+					AlertType: a.AlertType,
+					Title:     a.Title,
+					Message:   a.Message,
+				})
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, tag := range tags {
+		if failed[i] {
+			facts.AlertsFailedNodes = append(facts.AlertsFailedNodes, strings.ToUpper(tag))
+			continue
+		}
+		facts.Alerts = append(facts.Alerts, alerts[i]...)
+	}
+}
+
+func collectStorage(ctx context.Context, hcc *upgrade.HealthCheckContext, tags []string, facts *RavenDBFacts) {
+	facts.Storage = make([]NodeStorageFact, 0, len(tags))
+	for _, tag := range tags {
+		st, err := hcc.NodeStorage(ctx, tag)
+		if err != nil {
+			facts.Storage = append(facts.Storage, NodeStorageFact{Tag: tag, Error: err.Error()})
+			continue
+		}
+		facts.Storage = append(facts.Storage, NodeStorageFact{Tag: tag, FreeMB: st.FreeMB, FreePercent: st.FreePercent})
+	}
+}
+
+// the license is cluster-wide, so the first node that answers is enough.
+func collectLicenseStatus(ctx context.Context, hcc *upgrade.HealthCheckContext, tags []string, facts *RavenDBFacts) {
+	var errs []string
+	for _, tag := range tags {
+		st, err := hcc.LicenseStatus(ctx, tag)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		fact := &LicenseStatusFact{
+			Id:             st.Id,
+			LicensedTo:     st.LicensedTo,
+			Type:           st.Type,
+			Expired:        st.Expired,
+			MaxCores:       st.MaxCores,
+			MaxMemoryGB:    st.MaxMemory,
+			MaxClusterSize: st.MaxClusterSize,
+		}
+		if st.Expiration != nil {
+			fact.Expiration = *st.Expiration
+		}
+		facts.License = fact
+		return
+	}
+	facts.LicenseError = strings.Join(errs, "; ")
+}
+
+func collectDatabases(ctx context.Context, hcc *upgrade.HealthCheckContext, facts *RavenDBFacts) {
+	dbs, err := hcc.Databases(ctx)
+	if err != nil {
+		facts.DatabasesError = err.Error()
+		return
+	}
+
+	facts.Databases = make([]DatabaseFact, 0, len(dbs))
+	for _, db := range dbs {
+		facts.Databases = append(facts.Databases, DatabaseFact{
+			Name:              db.Name,
+			Disabled:          db.Disabled,
+			ReplicationFactor: db.ReplicationFactor,
+			Members:           db.Members,
+			Rehabs:            db.Rehabs,
+			NotOk:             db.NotOk,
+			LastError:         db.LastError,
+		})
+	}
+	sort.Slice(facts.Databases, func(i, j int) bool { return facts.Databases[i].Name < facts.Databases[j].Name })
+}
+
+func nodeRole(topo *upgrade.ClusterTopology, tag string) ravendbv1.RavenDBNodeRole {
+	if topo.Leader == tag {
+		return ravendbv1.NodeRoleLeader
+	}
+	switch {
+	case contains(topo.Members, tag):
+		return ravendbv1.NodeRoleMember
+	case contains(topo.Promotables, tag):
+		return ravendbv1.NodeRolePromotable
+	case contains(topo.Watchers, tag):
+		return ravendbv1.NodeRoleWatcher
+	}
+	return ravendbv1.NodeRoleNotInCluster
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -18,25 +18,45 @@ package health
 
 import (
 	"context"
+	"crypto/x509"
+	"sort"
+	"strings"
+	"time"
 
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/upgrade"
 
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // its purpose to translates K8s objs into ResourceFacts that the evaluator consumes.
+//
+// lookups are scoped to the cluster: owned objects are listed through a cache index on the
+// instance label our builders set (owner refs are still checked), while PVCs and Secrets are
+// read by name. so the cost per reconcile doesn't grow with unrelated objects in the namespace.
 
 func NewResourceCollector() Collector {
 	return &resourceCollector{}
 }
 
-type resourceCollector struct{}
+// NewResourceCollectorWithAlertHistory reads the notification centers of a cluster at most
+// once per alertsPollInterval, recording when in alerts.
+func NewResourceCollectorWithAlertHistory(alerts *AlertHistory) Collector {
+	return &resourceCollector{alerts: alerts}
+}
+
+type resourceCollector struct {
+	alerts *AlertHistory
+}
 
 func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, cluster *ravendbv1.RavenDBCluster) (*ResourceFacts, error) {
 
@@ -64,13 +84,13 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 	}
 	facts.Jobs = jobFacts
 
-	podFacts, ownedPodUIDs, claimedPVCNames, err := collectPodsAndPVCRefs(ctx, cli, ns, ownedSSUIDs)
+	podFacts, claimedPVCNames, err := collectPodsAndPVCRefs(ctx, cli, ns, cluster, ownedSSUIDs)
 	if err != nil {
 		return facts, err
 	}
 	facts.Pods = podFacts
 
-	pvcFacts, err := collectPVCs(ctx, cli, ns, ownedSSUIDs, ownedPodUIDs, claimedPVCNames)
+	pvcFacts, err := collectPVCs(ctx, cli, ns, claimedPVCNames)
 	if err != nil {
 		return facts, err
 	}
@@ -88,11 +108,20 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 	}
 	facts.Ingresses = ingFacts
 
-	secFacts, err := collectSecrets(ctx, cli, ns)
+	secFacts, certFacts, err := collectSecrets(ctx, cli, ns, cluster)
 	if err != nil {
 		return facts, err
 	}
 	facts.Secrets = secFacts
+	facts.Certificates = certFacts
+
+	licFact, err := collectLicense(ctx, cli, ns, cluster)
+	if err != nil {
+		return facts, err
+	}
+	facts.License = licFact
+
+	facts.RavenDB = collectRavenDB(ctx, cli, cluster, t.alerts)
 
 	return facts, nil
 }
@@ -100,7 +129,7 @@ func (t *resourceCollector) Collect(ctx context.Context, cli client.Client, clus
 func collectStatefulSets(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]StatefulSetFact, map[string]struct{}, error) {
 
 	var list appsv1.StatefulSetList
-	if err := cli.List(ctx, &list, client.InNamespace(ns)); err != nil {
+	if err := cli.List(ctx, &list, client.InNamespace(ns), ownedByInstance(cluster)); err != nil {
 		return nil, nil, err
 	}
 
@@ -141,7 +170,7 @@ func collectStatefulSets(ctx context.Context, cli client.Client, ns string, clus
 func collectJobs(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]JobFact, error) {
 
 	var list batchv1.JobList
-	if err := cli.List(ctx, &list, client.InNamespace(ns)); err != nil {
+	if err := cli.List(ctx, &list, client.InNamespace(ns), ownedByInstance(cluster)); err != nil {
 		return nil, err
 	}
 
@@ -167,15 +196,14 @@ func collectJobs(ctx context.Context, cli client.Client, ns string, cluster *rav
 	return facts, nil
 }
 
-func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, ownedSSUIDs map[string]struct{}) ([]PodFact, map[string]struct{}, map[string]struct{}, error) {
+func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster, ownedSSUIDs map[string]struct{}) ([]PodFact, map[string]struct{}, error) {
 
 	var list corev1.PodList
-	if err := cli.List(ctx, &list, client.InNamespace(ns)); err != nil {
-		return nil, nil, nil, err
+	if err := cli.List(ctx, &list, client.InNamespace(ns), ownedByInstance(cluster)); err != nil {
+		return nil, nil, err
 	}
 
 	podFacts := make([]PodFact, 0, len(list.Items))
-	ownedPodUIDs := make(map[string]struct{}, len(list.Items))
 	claimedPVCNames := make(map[string]struct{})
 
 	for i := 0; i < len(list.Items); i++ {
@@ -184,13 +212,15 @@ func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, ow
 			continue
 		}
 
-		ownedPodUIDs[string(p.UID)] = struct{}{}
 		podFacts = append(podFacts, PodFact{
-			Name:      p.Name,
-			Namespace: p.Namespace,
-			Phase:     string(p.Status.Phase),
-			Ready:     isPodReady(p),
-			Restarts:  getPodsContainersTotalRestarts(p),
+			Name:         p.Name,
+			Namespace:    p.Namespace,
+			Tag:          p.Labels[common.LabelNodeTag],
+			Phase:        string(p.Status.Phase),
+			Ready:        isPodReady(p),
+			Restarts:     getPodsContainersTotalRestarts(p),
+			CrashLooping: getCrashLoopingContainers(p),
+			OOMKilledAt:  getLastOOMKill(p),
 		})
 
 		for _, vol := range p.Spec.Volumes {
@@ -203,34 +233,23 @@ func collectPodsAndPVCRefs(ctx context.Context, cli client.Client, ns string, ow
 		}
 	}
 
-	return podFacts, ownedPodUIDs, claimedPVCNames, nil
+	return podFacts, claimedPVCNames, nil
 }
 
-func collectPVCs(
-	ctx context.Context,
-	cli client.Client,
-	ns string,
-	ownedSSUIDs map[string]struct{},
-	ownedPodUIDs map[string]struct{},
-	claimedPVCNames map[string]struct{},
-) ([]PVCFact, error) {
-
-	var pvcList corev1.PersistentVolumeClaimList
-	if err := cli.List(ctx, &pvcList, client.InNamespace(ns)); err != nil {
-		return nil, err
-	}
+func collectPVCs(ctx context.Context, cli client.Client, ns string, claimedPVCNames map[string]struct{}) ([]PVCFact, error) {
 
-	facts := make([]PVCFact, 0, len(pvcList.Items))
+	facts := make([]PVCFact, 0, len(claimedPVCNames))
 
-	// a pvc belongs to us if: it’s owned by one of our STSs/Pods or one of our Pods referenced it by claim name (so also any additional vols)
-	for _, pvc := range pvcList.Items {
+	// a pvc belongs to us if one of our Pods referenced it by claim name (so also any additional vols).
+	// claim templates carry no cluster labels, so we read them by name instead of listing.
+	for _, name := range sortedNames(claimedPVCNames) {
 
-		ownedBySS := isOwnedByAny(pvc.OwnerReferences, ownedSSUIDs)
-		ownedByPod := isOwnedByAny(pvc.OwnerReferences, ownedPodUIDs)
-		_, referencedByPod := claimedPVCNames[pvc.Name]
-
-		if !(ownedBySS || ownedByPod || referencedByPod) {
-			continue
+		var pvc corev1.PersistentVolumeClaim
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, &pvc); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
 		}
 
 		requested := ""
@@ -258,7 +277,7 @@ func collectPVCs(
 func collectServices(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]ServiceFact, error) {
 
 	var svcList corev1.ServiceList
-	if err := cli.List(ctx, &svcList, client.InNamespace(ns)); err != nil {
+	if err := cli.List(ctx, &svcList, client.InNamespace(ns), ownedByInstance(cluster)); err != nil {
 		return nil, err
 	}
 
@@ -287,7 +306,7 @@ func collectServices(ctx context.Context, cli client.Client, ns string, cluster
 func collectIngresses(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]IngressFact, error) {
 
 	var ingList networkingv1.IngressList
-	if err := cli.List(ctx, &ingList, client.InNamespace(ns)); err != nil {
+	if err := cli.List(ctx, &ingList, client.InNamespace(ns), ownedByInstance(cluster)); err != nil {
 		return nil, err
 	}
 
@@ -308,23 +327,141 @@ func collectIngresses(ctx context.Context, cli client.Client, ns string, cluster
 	return facts, nil
 }
 
-func collectSecrets(ctx context.Context, cli client.Client, ns string) ([]SecretFact, error) {
+// only the secrets the spec references are read, each by name.
+// the keys certificates live under in the referenced secrets
+const (
+	serverPFXKey   = "server.pfx"
+	clientPFXKey   = "client.pfx"
+	pfxPasswordKey = "password"
+	caCRTKey       = "ca.crt"
+)
 
-	var secretList corev1.SecretList
-	if err := cli.List(ctx, &secretList, client.InNamespace(ns)); err != nil {
-		return nil, err
-	}
+// gets the referenced secrets by name. the manager doesn't cache Secrets, so these are
+// read from the API server and nothing else is listed.
+func collectSecrets(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) ([]SecretFact, []CertificateFact, error) {
 
-	facts := make([]SecretFact, 0, len(secretList.Items))
+	names := common.ReferencedSecretNames(cluster)
+	facts := make([]SecretFact, 0, len(names))
+	var certs []CertificateFact
 
-	for _, s := range secretList.Items {
+	for _, name := range names {
+		var s corev1.Secret
+		if err := cli.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, &s); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return nil, nil, err
+		}
 		facts = append(facts, SecretFact{
 			Name:      s.Name,
 			Namespace: s.Namespace,
 			Type:      string(s.Type),
 		})
+		certs = append(certs, collectCertificates(&s)...)
 	}
-	return facts, nil
+	return facts, certs, nil
+}
+
+// reads license.json out of licenseSecretRef. nil when there's no such secret.
+func collectLicense(ctx context.Context, cli client.Client, ns string, cluster *ravendbv1.RavenDBCluster) (*LicenseFact, error) {
+	if cluster.Spec.LicenseSecretRef == "" {
+		return nil, nil
+	}
+
+	var s corev1.Secret
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: ns, Name: cluster.Spec.LicenseSecretRef}, &s); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(s.Data))
+	for k := range s.Data {
+		if strings.HasSuffix(k, ".json") {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return &LicenseFact{Error: "secret " + s.Name + " has no .json key"}, nil
+	}
+	sort.Strings(keys)
+
+	lic, err := upgrade.ParseLicense(s.Data[keys[0]])
+	if err != nil {
+		return &LicenseFact{Error: err.Error()}, nil
+	}
+	return &LicenseFact{Id: lic.Id, Name: lic.Name}, nil
+}
+
+// decodes whichever of server.pfx, client.pfx and ca.crt the secret holds.
+func collectCertificates(s *corev1.Secret) []CertificateFact {
+	var facts []CertificateFact
+	for _, key := range []string{serverPFXKey, clientPFXKey, caCRTKey} {
+		data, ok := s.Data[key]
+		if !ok || len(data) == 0 {
+			continue
+		}
+
+		var (
+			cert *x509.Certificate
+			err  error
+		)
+		if key == caCRTKey {
+			cert, err = certutil.ParsePEMCertificate(data)
+		} else {
+			cert, err = certutil.ParsePFXCertificate(data, string(s.Data[pfxPasswordKey]))
+		}
+
+		fact := CertificateFact{Secret: s.Name, Key: key}
+		if err != nil {
+			fact.Error = err.Error()
+		} else {
+			fact.Subject = cert.Subject.String()
+			fact.DNSNames = cert.DNSNames
+			fact.NotAfter = cert.NotAfter
+		}
+		facts = append(facts, fact)
+	}
+	return facts
+}
+
+// RegisterIndexes indexes the kinds the collector lists by the cluster that manages them.
+// must run before the manager's cache starts.
+func RegisterIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, obj := range []client.Object{
+		&appsv1.StatefulSet{},
+		&batchv1.Job{},
+		&corev1.Pod{},
+		&corev1.Service{},
+		&networkingv1.Ingress{},
+	} {
+		if err := indexer.IndexField(ctx, obj, common.InstanceIndex, indexInstance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func indexInstance(obj client.Object) []string {
+	labels := obj.GetLabels()
+	if labels[common.LabelManagedBy] != common.Manager || labels[common.LabelInstance] == "" {
+		return nil
+	}
+	return []string{labels[common.LabelInstance]}
+}
+
+func ownedByInstance(cluster *ravendbv1.RavenDBCluster) client.MatchingFields {
+	return client.MatchingFields{common.InstanceIndex: cluster.Name}
+}
+
+func sortedNames(set map[string]struct{}) []string {
+	out := make([]string, 0, len(set))
+	for name := range set {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
 }
 
 func isOwnedByCluster(owners []metav1.OwnerReference, cluster *ravendbv1.RavenDBCluster) bool {
@@ -364,3 +501,25 @@ func getPodsContainersTotalRestarts(p *corev1.Pod) int32 {
 	}
 	return sum
 }
+
+func getCrashLoopingContainers(p *corev1.Pod) []string {
+	var names []string
+	for _, cs := range p.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason == "CrashLoopBackOff" {
+			names = append(names, cs.Name)
+		}
+	}
+	return names
+}
+
+func getLastOOMKill(p *corev1.Pod) time.Time {
+	var last time.Time
+	for _, cs := range p.Status.ContainerStatuses {
+		for _, t := range []*corev1.ContainerStateTerminated{cs.State.Terminated, cs.LastTerminationState.Terminated} {
+			if t != nil && t.Reason == "OOMKilled" && t.FinishedAt.After(last) {
+				last = t.FinishedAt.Time
+			}
+		}
+	}
+	return last
+}
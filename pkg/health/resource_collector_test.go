@@ -0,0 +1,215 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/health"
+
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const benchNS = "ravendb"
+
+func benchCluster() *ravendbv1.RavenDBCluster {
+	return &ravendbv1.RavenDBCluster{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "ravendb.ravendb.io/v1", Kind: "RavenDBCluster"},
+		ObjectMeta: metav1.ObjectMeta{Name: "bench", Namespace: benchNS, UID: types.UID("cluster-uid")},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Mode:                 ravendbv1.ModeNone,
+			LicenseSecretRef:     "ravendb-license",
+			ClientCertSecretRef:  "ravendb-client-cert",
+			ClusterCertSecretRef: ptr("ravendb-cluster-cert"),
+			CACertSecretRef:      ptr("ravendb-ca-cert"),
+			Nodes:                []ravendbv1.RavenDBNode{{Tag: "a"}, {Tag: "b"}, {Tag: "c"}},
+		},
+	}
+}
+
+func ptr(s string) *string { return &s }
+
+// seeds the objects one cluster owns plus `noise` unrelated objects of every kind.
+func benchObjects(cluster *ravendbv1.RavenDBCluster, noise int) []client.Object {
+	labels := map[string]string{
+		common.LabelInstance:  cluster.Name,
+		common.LabelManagedBy: common.Manager,
+	}
+	owner := []metav1.OwnerReference{{APIVersion: "ravendb.ravendb.io/v1", Kind: "RavenDBCluster", Name: cluster.Name, UID: cluster.UID}}
+
+	var objs []client.Object
+	for _, n := range cluster.Spec.Nodes {
+		sts := common.Prefix + n.Tag
+		stsUID := types.UID("sts-" + n.Tag)
+		pvc := common.DataVolumeName + "-" + sts + "-0"
+
+		objs = append(objs,
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: sts, Namespace: benchNS, UID: stsUID, Labels: labels, OwnerReferences: owner}},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: sts + "-0", Namespace: benchNS, Labels: labels,
+					OwnerReferences: []metav1.OwnerReference{{APIVersion: "apps/v1", Kind: "StatefulSet", Name: sts, UID: stsUID}}},
+				Spec: corev1.PodSpec{Volumes: []corev1.Volume{{Name: common.DataVolumeName,
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: pvc}}}}},
+			},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: pvc, Namespace: benchNS}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: sts, Namespace: benchNS, Labels: labels, OwnerReferences: owner}},
+		)
+	}
+	for _, s := range common.ReferencedSecretNames(cluster) {
+		objs = append(objs, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: s, Namespace: benchNS}})
+	}
+
+	for i := 0; i < noise; i++ {
+		name := fmt.Sprintf("other-%d", i)
+		objs = append(objs,
+			&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: benchNS}},
+			&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: benchNS}},
+			&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: benchNS}},
+			&corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: benchNS}},
+			&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: benchNS}, Data: map[string][]byte{"key": make([]byte, 1024)}},
+		)
+	}
+	return objs
+}
+
+func benchClient(tb testing.TB, noise int) (client.Client, *ravendbv1.RavenDBCluster) {
+	tb.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(tb, clientgoscheme.AddToScheme(scheme))
+	require.NoError(tb, ravendbv1.AddToScheme(scheme))
+
+	cluster := benchCluster()
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(benchObjects(cluster, noise)...)
+	require.NoError(tb, health.RegisterIndexes(context.Background(), builderIndexer{builder}))
+	return builder.Build(), cluster
+}
+
+// lets RegisterIndexes populate the fake client the same way it does the manager's cache.
+type builderIndexer struct{ b *fake.ClientBuilder }
+
+func (i builderIndexer) IndexField(_ context.Context, obj client.Object, field string, fn client.IndexerFunc) error {
+	i.b.WithIndex(obj, field, fn)
+	return nil
+}
+
+func TestResourceCollector_IgnoresUnrelatedObjects(t *testing.T) {
+	cli, cluster := benchClient(t, 10)
+
+	facts, err := health.NewResourceCollector().Collect(context.Background(), cli, cluster)
+	require.NoError(t, err)
+	require.Len(t, facts.StatefulSets, 3)
+	require.Len(t, facts.Pods, 3)
+	require.Len(t, facts.PVCs, 3)
+	require.Len(t, facts.Services, 3)
+	require.Len(t, facts.Secrets, 4)
+}
+
+func TestResourceCollector_DecodesCertificates(t *testing.T) {
+	cli, cluster := benchClient(t, 0)
+	ctx := context.Background()
+
+	notAfter := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+	ca := &corev1.Secret{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: benchNS, Name: *cluster.Spec.CACertSecretRef}, ca))
+	ca.Data = map[string][]byte{"ca.crt": selfSignedPEM(t, "RavenDB CA", notAfter)}
+	require.NoError(t, cli.Update(ctx, ca))
+
+	clientCert := &corev1.Secret{}
+	require.NoError(t, cli.Get(ctx, client.ObjectKey{Namespace: benchNS, Name: cluster.Spec.ClientCertSecretRef}, clientCert))
+	clientCert.Data = map[string][]byte{"client.pfx": []byte("not a pfx")}
+	require.NoError(t, cli.Update(ctx, clientCert))
+
+	facts, err := health.NewResourceCollector().Collect(ctx, cli, cluster)
+	require.NoError(t, err)
+	require.Len(t, facts.Certificates, 2)
+
+	byKey := map[string]health.CertificateFact{}
+	for _, c := range facts.Certificates {
+		byKey[c.Key] = c
+	}
+	require.Equal(t, "CN=RavenDB CA", byKey["ca.crt"].Subject)
+	require.True(t, byKey["ca.crt"].NotAfter.Equal(notAfter))
+	require.NotEmpty(t, byKey["client.pfx"].Error)
+}
+
+func selfSignedPEM(t *testing.T, cn string, notAfter time.Time) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// the namespace-wide variant replays what the collector used to do (list every kind in the
+// namespace, secrets included) so the scoped numbers have something to be compared with.
+func BenchmarkResourceCollector_Collect(b *testing.B) {
+	for _, noise := range []int{0, 100, 1000} {
+		cli, cluster := benchClient(b, noise)
+		collector := health.NewResourceCollector()
+
+		b.Run(fmt.Sprintf("scoped/noise=%d", noise), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := collector.Collect(context.Background(), cli, cluster); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+
+		b.Run(fmt.Sprintf("namespace-wide/noise=%d", noise), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				for _, list := range []client.ObjectList{
+					&appsv1.StatefulSetList{}, &corev1.PodList{}, &corev1.PersistentVolumeClaimList{},
+					&corev1.ServiceList{}, &corev1.SecretList{},
+				} {
+					if err := cli.List(context.Background(), list, client.InNamespace(benchNS)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics holds the operator's custom Prometheus collectors. They are registered
+// on the controller-runtime registry, so they're served by the manager's metrics server.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const namespace = "ravendb_operator"
+
+// reconcile outcomes
+const (
+	OutcomeSuccess = "success"
+	OutcomeRequeue = "requeue"
+	OutcomeError   = "error"
+)
+
+// node upgrade states, on top of the RavenDBNodeStatus phases (Created/Failed)
+const NodeStateUpgrading = "Upgrading"
+
+var (
+	conditionStatuses = []string{"True", "False", "Unknown"}
+	nodeStates        = []string{"Created", "Failed", NodeStateUpgrading}
+)
+
+var (
+	gateResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gate_results_total",
+		Help:      "Upgrade gate checks by kind, phase and result (pass, block, timeout). Every blocked attempt counts.",
+	}, []string{"kind", "phase", "result"})
+
+	gateDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "gate_duration_seconds",
+		Help:      "Time from a gate's start until it passed, timed out or failed.",
+		Buckets:   []float64{1, 5, 15, 30, 60, 120, 300, 600, 900},
+	}, []string{"kind", "phase", "result"})
+
+	reconcileOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_total",
+		Help:      "Reconciles per cluster by outcome (success, requeue, error).",
+	}, []string{"namespace", "cluster", "outcome"})
+
+	clusterCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "cluster_condition",
+		Help:      "1 for the current status of every cluster condition, 0 for the other statuses.",
+	}, []string{"namespace", "cluster", "condition", "status"})
+
+	nodeUpgradeState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_upgrade_state",
+		Help:      "1 for the current upgrade state of every node (Created, Failed, Upgrading), 0 for the others.",
+	}, []string{"namespace", "cluster", "node", "state"})
+
+	bootstrapDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "bootstrap_duration_seconds",
+		Help:      "Time from cluster creation until BootstrapCompleted turned True.",
+	}, []string{"namespace", "cluster"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		gateResults,
+		gateDuration,
+		reconcileOutcomes,
+		clusterCondition,
+		nodeUpgradeState,
+		bootstrapDuration,
+	)
+}
+
+// ObserveGate counts a gate result; terminal results also record how long the gate ran.
+func ObserveGate(kind, phase, result string, elapsed time.Duration, terminal bool) {
+	gateResults.WithLabelValues(kind, phase, result).Inc()
+	if terminal {
+		gateDuration.WithLabelValues(kind, phase, result).Observe(elapsed.Seconds())
+	}
+}
+
+func ObserveReconcile(ns, cluster, outcome string) {
+	reconcileOutcomes.WithLabelValues(ns, cluster, outcome).Inc()
+}
+
+func SetCondition(ns, cluster, condition, status string) {
+	for _, s := range conditionStatuses {
+		v := 0.0
+		if s == status {
+			v = 1
+		}
+		clusterCondition.WithLabelValues(ns, cluster, condition, s).Set(v)
+	}
+}
+
+func SetNodeUpgradeState(ns, cluster, node, state string) {
+	for _, s := range nodeStates {
+		v := 0.0
+		if s == state {
+			v = 1
+		}
+		nodeUpgradeState.WithLabelValues(ns, cluster, node, s).Set(v)
+	}
+}
+
+func SetBootstrapDuration(ns, cluster string, d time.Duration) {
+	bootstrapDuration.WithLabelValues(ns, cluster).Set(d.Seconds())
+}
+
+// ForgetNode drops the series of a node once it's no longer part of the cluster.
+func ForgetNode(ns, cluster, node string) {
+	nodeUpgradeState.DeletePartialMatch(prometheus.Labels{"namespace": ns, "cluster": cluster, "node": node})
+}
+
+// ForgetCluster drops every per-cluster series once the cluster is gone.
+func ForgetCluster(ns, cluster string) {
+	labels := prometheus.Labels{"namespace": ns, "cluster": cluster}
+	reconcileOutcomes.DeletePartialMatch(labels)
+	clusterCondition.DeletePartialMatch(labels)
+	nodeUpgradeState.DeletePartialMatch(labels)
+	bootstrapDuration.DeletePartialMatch(labels)
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics_ConditionAndNodeStateAreOneHot(t *testing.T) {
+	SetCondition("ns", "c1", "Ready", "False")
+	SetCondition("ns", "c1", "Ready", "True")
+	require.Equal(t, 1.0, testutil.ToFloat64(clusterCondition.WithLabelValues("ns", "c1", "Ready", "True")))
+	require.Equal(t, 0.0, testutil.ToFloat64(clusterCondition.WithLabelValues("ns", "c1", "Ready", "False")))
+
+	SetNodeUpgradeState("ns", "c1", "A", NodeStateUpgrading)
+	SetNodeUpgradeState("ns", "c1", "A", "Created")
+	require.Equal(t, 1.0, testutil.ToFloat64(nodeUpgradeState.WithLabelValues("ns", "c1", "A", "Created")))
+	require.Equal(t, 0.0, testutil.ToFloat64(nodeUpgradeState.WithLabelValues("ns", "c1", "A", NodeStateUpgrading)))
+}
+
+func TestMetrics_GateDurationOnlyOnTerminalResults(t *testing.T) {
+	ObserveGate("NodeAlive", "Pre", "block", time.Second, false)
+	ObserveGate("NodeAlive", "Pre", "pass", 3*time.Second, true)
+
+	require.Equal(t, 1.0, testutil.ToFloat64(gateResults.WithLabelValues("NodeAlive", "Pre", "block")))
+	require.Equal(t, 1.0, testutil.ToFloat64(gateResults.WithLabelValues("NodeAlive", "Pre", "pass")))
+	require.Equal(t, 1, testutil.CollectAndCount(gateDuration))
+}
+
+func TestMetrics_ForgetClusterDropsItsSeries(t *testing.T) {
+	ObserveReconcile("ns", "gone", OutcomeSuccess)
+	SetBootstrapDuration("ns", "gone", time.Minute)
+	SetBootstrapDuration("ns", "kept", time.Minute)
+
+	ForgetCluster("ns", "gone")
+
+	require.Equal(t, 0, testutil.CollectAndCount(reconcileOutcomes))
+	require.Equal(t, 1, testutil.CollectAndCount(bootstrapDuration))
+}
+
+func TestMetrics_ForgetNodeDropsOnlyItsSeries(t *testing.T) {
+	SetNodeUpgradeState("ns", "shrunk", "A", "Created")
+	SetNodeUpgradeState("ns", "shrunk", "B", "Created")
+	before := testutil.CollectAndCount(nodeUpgradeState)
+
+	ForgetNode("ns", "shrunk", "B")
+
+	require.Equal(t, before-len(nodeStates), testutil.CollectAndCount(nodeUpgradeState))
+	require.Equal(t, 1.0, testutil.ToFloat64(nodeUpgradeState.WithLabelValues("ns", "shrunk", "A", "Created")))
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// cert-manager's Certificate is built unstructured for the same reason as the monitors.
+var CertificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+const (
+	serverPFXKey = "server.pfx"
+	clientPFXKey = "client.pfx"
+)
+
+// CertManagerTLSSecretName is the kubernetes.io/tls Secret cert-manager issues a Certificate into.
+func CertManagerTLSSecretName(certificate string) string {
+	return certificate + common.CertManagerTLSSuffix
+}
+
+// ServerCertificateDNSNames lists every host the server certificate must cover: the public
+// HTTPS and TCP hosts of all nodes, plus the in-cluster service names the nodes reach each
+// other's TCP endpoint on (RAVEN_PublicServerUrl_Tcp_Cluster).
+func ServerCertificateDNSNames(cluster *ravendbv1.RavenDBCluster) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+	add := func(host string) {
+		host = strings.ToLower(host)
+		if !seen[host] {
+			seen[host] = true
+			names = append(names, host)
+		}
+	}
+
+	for _, node := range cluster.Spec.Nodes {
+		for _, raw := range []string{node.PublicServerUrl, node.PublicServerUrlTcp} {
+			u, err := url.Parse(raw)
+			if err != nil || u.Hostname() == "" {
+				return nil, fmt.Errorf("node %s: cannot derive a host from %q", node.Tag, raw)
+			}
+			add(u.Hostname())
+		}
+	}
+	for _, node := range cluster.Spec.Nodes {
+		add(common.Prefix + node.Tag + common.ClusterFQDNSuffix)
+	}
+
+	return names, nil
+}
+
+// BuildServerCertificate requests the certificate every node serves (server.pfx). RavenDB
+// also uses it as the client certificate between nodes, hence the client auth usage.
+func BuildServerCertificate(cluster *ravendbv1.RavenDBCluster) (*unstructured.Unstructured, error) {
+	dnsNames, err := ServerCertificateDNSNames(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := buildCertificateSpec(cluster, common.CertManagerServerCert)
+	spec["commonName"] = dnsNames[0]
+	spec["dnsNames"] = toInterfaceSlice(dnsNames)
+	spec["usages"] = []interface{}{"digital signature", "key encipherment", "server auth", "client auth"}
+
+	return buildCertificate(cluster, common.CertManagerServerCert, spec), nil
+}
+
+// BuildClientCertificate requests the operator's admin client certificate (client.pfx). Its
+// private key is kept across renewals: RavenDB trusts a renewed client certificate with the
+// same issuer and public key as a registered one, so the operator doesn't lose access.
+func BuildClientCertificate(cluster *ravendbv1.RavenDBCluster) *unstructured.Unstructured {
+	spec := buildCertificateSpec(cluster, common.CertManagerClientCert)
+	spec["commonName"] = cluster.Name + "." + common.Manager
+	spec["usages"] = []interface{}{"digital signature", "key encipherment", "client auth"}
+	spec["privateKey"].(map[string]interface{})["rotationPolicy"] = "Never"
+
+	return buildCertificate(cluster, common.CertManagerClientCert, spec)
+}
+
+func buildCertificateSpec(cluster *ravendbv1.RavenDBCluster, name string) map[string]interface{} {
+	cm := cluster.Spec.CertManager
+
+	kind := cm.IssuerRef.Kind
+	if kind == "" {
+		kind = ravendbv1.CertManagerIssuer
+	}
+	issuerRef := map[string]interface{}{"name": cm.IssuerRef.Name, "kind": string(kind)}
+	if cm.IssuerRef.Group != "" {
+		issuerRef["group"] = cm.IssuerRef.Group
+	}
+
+	spec := map[string]interface{}{
+		"secretName": CertManagerTLSSecretName(name),
+		"issuerRef":  issuerRef,
+		// RavenDB's certificate handling expects RSA keys
+		"privateKey": map[string]interface{}{"algorithm": "RSA", "size": int64(2048)},
+	}
+	if cm.Duration != nil {
+		spec["duration"] = cm.Duration.Duration.String()
+	}
+	if cm.RenewBefore != nil {
+		spec["renewBefore"] = cm.RenewBefore.Duration.String()
+	}
+	return spec
+}
+
+func buildCertificate(cluster *ravendbv1.RavenDBCluster, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": cluster.Namespace,
+			"labels": map[string]interface{}{
+				common.LabelAppName:   common.App,
+				common.LabelManagedBy: common.Manager,
+				common.LabelInstance:  cluster.Name,
+				common.LabelComponent: common.ComponentCertificates,
+			},
+		},
+		"spec": spec,
+	}}
+	obj.SetGroupVersionKind(CertificateGVK)
+	return obj
+}
+
+// BuildServerCertSecret builds clusterCertSecretRef with the server.pfx get-server-cert.sh
+// serves. sourceHash identifies the issued certificate it was converted from.
+func BuildServerCertSecret(cluster *ravendbv1.RavenDBCluster, pfx []byte, sourceHash string) *corev1.Secret {
+	return buildCertSecret(cluster, *cluster.Spec.ClusterCertSecretRef, serverPFXKey, pfx, sourceHash)
+}
+
+// BuildClientCertSecret builds clientCertSecretRef with the password-less client.pfx.
+func BuildClientCertSecret(cluster *ravendbv1.RavenDBCluster, pfx []byte, sourceHash string) *corev1.Secret {
+	return buildCertSecret(cluster, cluster.Spec.ClientCertSecretRef, clientPFXKey, pfx, sourceHash)
+}
+
+// BuildCACertSecret builds caCertSecretRef with the issuer's CA as ca.crt.
+func BuildCACertSecret(cluster *ravendbv1.RavenDBCluster, caPEM []byte, sourceHash string) *corev1.Secret {
+	return buildCertSecret(cluster, *cluster.Spec.CACertSecretRef, caCRTKey, caPEM, sourceHash)
+}
+
+func buildCertSecret(cluster *ravendbv1.RavenDBCluster, name, key string, data []byte, sourceHash string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				common.LabelAppName:   common.App,
+				common.LabelManagedBy: common.Manager,
+				common.LabelInstance:  cluster.Name,
+				common.LabelComponent: common.ComponentCertificates,
+			},
+			Annotations: map[string]string{
+				common.CertSourceHashAnnotation: sourceHash,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{key: data},
+	}
+}
+
+func toInterfaceSlice(ss []string) []interface{} {
+	out := make([]interface{}, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
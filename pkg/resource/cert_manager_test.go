@@ -0,0 +1,90 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func certManagedCluster() *ravendbv1.RavenDBCluster {
+	clusterCert, ca := "ravendb-cluster-cert", "ravendb-ca-cert"
+	return &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Mode:                 ravendbv1.ModeNone,
+			ClusterCertSecretRef: &clusterCert,
+			ClientCertSecretRef:  "ravendb-client-cert",
+			CACertSecretRef:      &ca,
+			CertManager: &ravendbv1.CertManagerSpec{
+				IssuerRef: ravendbv1.CertManagerIssuerRef{Name: "ravendb-ca", Kind: ravendbv1.CertManagerClusterIssuer},
+				Duration:  &metav1.Duration{Duration: 2160 * time.Hour},
+			},
+			Nodes: []ravendbv1.RavenDBNode{
+				{Tag: "A", PublicServerUrl: "https://a.example.run", PublicServerUrlTcp: "tcp://a-tcp.example.run:443"},
+				{Tag: "B", PublicServerUrl: "https://b.example.run", PublicServerUrlTcp: "tcp://b-tcp.example.run:443"},
+			},
+		},
+	}
+}
+
+func TestBuildServerCertificate_CoversEveryNode(t *testing.T) {
+	cluster := certManagedCluster()
+
+	cert, err := resource.BuildServerCertificate(cluster)
+	require.NoError(t, err)
+	require.Equal(t, resource.CertificateGVK, cert.GroupVersionKind())
+	require.Equal(t, common.CertManagerServerCert, cert.GetName())
+
+	dnsNames, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "dnsNames")
+	require.Equal(t, []string{
+		"a.example.run", "a-tcp.example.run", "b.example.run", "b-tcp.example.run",
+		"ravendb-a.ravendb.svc.cluster.local", "ravendb-b.ravendb.svc.cluster.local",
+	}, dnsNames)
+
+	usages, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "usages")
+	require.Contains(t, usages, "client auth")
+
+	secretName, _, _ := unstructured.NestedString(cert.Object, "spec", "secretName")
+	require.Equal(t, "ravendb-server-tls", secretName)
+	kind, _, _ := unstructured.NestedString(cert.Object, "spec", "issuerRef", "kind")
+	require.Equal(t, "ClusterIssuer", kind)
+	duration, _, _ := unstructured.NestedString(cert.Object, "spec", "duration")
+	require.Equal(t, "2160h0m0s", duration)
+}
+
+func TestBuildClientCertificate_KeepsPrivateKey(t *testing.T) {
+	cert := resource.BuildClientCertificate(certManagedCluster())
+
+	policy, _, _ := unstructured.NestedString(cert.Object, "spec", "privateKey", "rotationPolicy")
+	require.Equal(t, "Never", policy)
+	usages, _, _ := unstructured.NestedStringSlice(cert.Object, "spec", "usages")
+	require.NotContains(t, usages, "server auth")
+
+	secret := resource.BuildClientCertSecret(certManagedCluster(), []byte("pfx"), "hash")
+	require.Equal(t, "ravendb-client-cert", secret.Name)
+	require.Equal(t, []byte("pfx"), secret.Data["client.pfx"])
+	require.Equal(t, "hash", secret.Annotations[common.CertSourceHashAnnotation])
+}
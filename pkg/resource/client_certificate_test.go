@@ -0,0 +1,45 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildClientCertificateSecret_DefaultsToResourceName(t *testing.T) {
+	cc := &ravendbv1.RavenDBClientCertificate{
+		ObjectMeta: metav1.ObjectMeta{Name: "reporting", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBClientCertificateSpec{ClusterRef: "c1"},
+	}
+
+	secret := resource.BuildClientCertificateSecret(cc, []byte("pfx"))
+	require.Equal(t, "reporting", secret.Name)
+	require.Equal(t, "ravendb", secret.Namespace)
+	require.Equal(t, []byte("pfx"), secret.Data["client.pfx"])
+	require.Equal(t, "c1", secret.Labels[common.LabelInstance])
+	require.Equal(t, common.ComponentClientCertificate, secret.Labels[common.LabelComponent])
+
+	cc.Spec.SecretName = "reporting-pfx"
+	require.Equal(t, "reporting-pfx", resource.BuildClientCertificateSecret(cc, nil).Name)
+}
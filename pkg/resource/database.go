@@ -0,0 +1,48 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// BuildEncryptionKeySecret builds the Secret holding the encryption key of a RavenDBDatabase.
+// It has no owner, so the key outlives the resource like a retained database does.
+func BuildEncryptionKeySecret(db *ravendbv1.RavenDBDatabase, key string) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Secret",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      db.GetEncryptionKeySecretRef(),
+			Namespace: db.Namespace,
+			Labels: map[string]string{
+				common.LabelAppName:   common.App,
+				common.LabelManagedBy: common.Manager,
+				common.LabelInstance:  db.Spec.ClusterRef,
+				common.LabelComponent: common.ComponentDatabase,
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{common.EncryptionKeySecretKey: []byte(key)},
+	}
+}
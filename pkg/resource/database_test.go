@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBuildEncryptionKeySecret_IsNotOwned(t *testing.T) {
+	db := &ravendbv1.RavenDBDatabase{
+		ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBDatabaseSpec{ClusterRef: "c1", Encrypted: true, Topology: []string{"A", "B"}},
+	}
+
+	secret := resource.BuildEncryptionKeySecret(db, "a2V5")
+	require.Equal(t, "orders-encryption-key", secret.Name)
+	require.Equal(t, "ravendb", secret.Namespace)
+	require.Empty(t, secret.OwnerReferences)
+	require.Equal(t, []byte("a2V5"), secret.Data["key"])
+	require.Equal(t, "c1", secret.Labels[common.LabelInstance])
+	require.Equal(t, common.ComponentDatabase, secret.Labels[common.LabelComponent])
+
+	db.Spec.EncryptionKeySecretRef = "orders-key"
+	require.Equal(t, "orders-key", resource.BuildEncryptionKeySecret(db, "a2V5").Name)
+}
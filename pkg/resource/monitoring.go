@@ -0,0 +1,184 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource
+
+import (
+	"fmt"
+	"net/url"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// the prometheus-operator kinds are built unstructured: the operator doesn't depend on
+// their Go types, and they only exist when the prometheus-operator CRDs are installed.
+var (
+	ServiceMonitorGVK = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "ServiceMonitor"}
+	PodMonitorGVK     = schema.GroupVersionKind{Group: "monitoring.coreos.com", Version: "v1", Kind: "PodMonitor"}
+)
+
+const caCRTKey = "ca.crt"
+
+func MonitorKindOf(cluster *ravendbv1.RavenDBCluster) ravendbv1.MonitorKind {
+	if m := cluster.Spec.Monitoring; m != nil && m.Kind == ravendbv1.MonitorKindPodMonitor {
+		return ravendbv1.MonitorKindPodMonitor
+	}
+	return ravendbv1.MonitorKindServiceMonitor
+}
+
+func MetricsServiceName(node ravendbv1.RavenDBNode) string {
+	return common.Prefix + node.Tag + common.MetricsSuffix
+}
+
+// MetricsClientCertSecretName is the TLS Secret the monitors present to RavenDB.
+func MetricsClientCertSecretName(cluster *ravendbv1.RavenDBCluster) string {
+	if m := cluster.Spec.Monitoring; m != nil {
+		return m.ClientCertSecretRef
+	}
+	return ""
+}
+
+// BuildMetricsService builds the per-node Service a ServiceMonitor scrapes. It selects
+// the same pod as the node Service but exposes the HTTPS port under the metrics name,
+// and stays ClusterIP whatever the external access type is.
+func BuildMetricsService(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) *corev1.Service {
+	labels := buildServiceLabels(cluster, node)
+	labels[common.LabelComponent] = common.ComponentMetrics
+
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "Service",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      MetricsServiceName(node),
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector: buildServiceSelector(node),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       common.MetricsPortName,
+					Port:       common.InternalHttpsPort,
+					TargetPort: intstr.FromString(common.HttpsPortName),
+					Protocol:   corev1.ProtocolTCP,
+				},
+			},
+		},
+	}
+}
+
+// BuildMonitor builds the ServiceMonitor or PodMonitor that scrapes one node. Monitors
+// are per node because each node's certificate is verified against that node's host.
+func BuildMonitor(cluster *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode) (*unstructured.Unstructured, error) {
+	spec := cluster.Spec.Monitoring
+	if spec == nil {
+		return nil, fmt.Errorf("monitoring is not configured")
+	}
+
+	u, err := url.Parse(node.PublicServerUrl)
+	if err != nil || u.Hostname() == "" {
+		return nil, fmt.Errorf("node %s: cannot derive TLS server name from publicServerUrl %q", node.Tag, node.PublicServerUrl)
+	}
+
+	labels := map[string]interface{}{}
+	for k, v := range spec.Labels {
+		labels[k] = v
+	}
+	labels[common.LabelAppName] = common.App
+	labels[common.LabelManagedBy] = common.Manager
+	labels[common.LabelInstance] = cluster.Name
+	labels[common.LabelNodeTag] = node.Tag
+	labels[common.LabelComponent] = common.ComponentMetrics
+
+	endpoint := map[string]interface{}{
+		"scheme":    "https",
+		"path":      common.RavenDBMetricsPath,
+		"tlsConfig": buildMonitorTLSConfig(cluster, u.Hostname()),
+	}
+	if spec.Interval != "" {
+		endpoint["interval"] = spec.Interval
+	}
+	if spec.ScrapeTimeout != "" {
+		endpoint["scrapeTimeout"] = spec.ScrapeTimeout
+	}
+
+	selector := map[string]interface{}{
+		common.LabelInstance: cluster.Name,
+		common.LabelNodeTag:  node.Tag,
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"name":      common.Prefix + node.Tag,
+			"namespace": cluster.Namespace,
+			"labels":    labels,
+		},
+	}}
+
+	switch MonitorKindOf(cluster) {
+	case ravendbv1.MonitorKindPodMonitor:
+		obj.SetGroupVersionKind(PodMonitorGVK)
+		endpoint["port"] = common.HttpsPortName
+		obj.Object["spec"] = map[string]interface{}{
+			"selector":            map[string]interface{}{"matchLabels": selector},
+			"podMetricsEndpoints": []interface{}{endpoint},
+		}
+	default:
+		obj.SetGroupVersionKind(ServiceMonitorGVK)
+		endpoint["port"] = common.MetricsPortName
+		selector[common.LabelComponent] = common.ComponentMetrics
+		obj.Object["spec"] = map[string]interface{}{
+			"selector":  map[string]interface{}{"matchLabels": selector},
+			"endpoints": []interface{}{endpoint},
+		}
+	}
+
+	return obj, nil
+}
+
+func buildMonitorTLSConfig(cluster *ravendbv1.RavenDBCluster, serverName string) map[string]interface{} {
+	certSecret := MetricsClientCertSecretName(cluster)
+
+	tlsConfig := map[string]interface{}{
+		"serverName": serverName,
+		"cert": map[string]interface{}{
+			"secret": map[string]interface{}{"name": certSecret, "key": corev1.TLSCertKey},
+		},
+		"keySecret": map[string]interface{}{"name": certSecret, "key": corev1.TLSPrivateKeyKey},
+	}
+
+	if cluster.Spec.Monitoring.InsecureSkipVerify {
+		tlsConfig["insecureSkipVerify"] = true
+	}
+
+	// self-signed clusters need their CA; Let's Encrypt certificates verify against the system roots
+	if cluster.Spec.Mode == ravendbv1.ModeNone && cluster.Spec.CACertSecretRef != nil {
+		tlsConfig["ca"] = map[string]interface{}{
+			"secret": map[string]interface{}{"name": *cluster.Spec.CACertSecretRef, "key": caCRTKey},
+		}
+	}
+
+	return tlsConfig
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resource_test
+
+import (
+	"testing"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/resource"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func monitoredCluster(mode ravendbv1.ClusterMode, m *ravendbv1.MonitoringSpec) *ravendbv1.RavenDBCluster {
+	ca := "ravendb-ca-cert"
+	return &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "c1", Namespace: "ravendb"},
+		Spec: ravendbv1.RavenDBClusterSpec{
+			Mode:                mode,
+			ClientCertSecretRef: "ravendb-client-cert",
+			CACertSecretRef:     &ca,
+			Monitoring:          m,
+			Nodes: []ravendbv1.RavenDBNode{
+				{Tag: "a", PublicServerUrl: "https://a.example.run:443"},
+			},
+		},
+	}
+}
+
+func TestBuildMonitor_ServiceMonitorScrapesMetricsService(t *testing.T) {
+	cluster := monitoredCluster(ravendbv1.ModeNone, &ravendbv1.MonitoringSpec{Interval: "30s", Labels: map[string]string{"release": "prom"}, ClientCertSecretRef: "prom-client-tls"})
+
+	mon, err := resource.BuildMonitor(cluster, cluster.Spec.Nodes[0])
+	require.NoError(t, err)
+	require.Equal(t, resource.ServiceMonitorGVK, mon.GroupVersionKind())
+	require.Equal(t, "prom", mon.GetLabels()["release"])
+
+	sel, _, _ := unstructured.NestedStringMap(mon.Object, "spec", "selector", "matchLabels")
+	require.Equal(t, common.ComponentMetrics, sel[common.LabelComponent])
+
+	eps, _, _ := unstructured.NestedSlice(mon.Object, "spec", "endpoints")
+	require.Len(t, eps, 1)
+	ep := eps[0].(map[string]interface{})
+	require.Equal(t, common.MetricsPortName, ep["port"])
+	require.Equal(t, "30s", ep["interval"])
+
+	serverName, _, _ := unstructured.NestedString(ep, "tlsConfig", "serverName")
+	require.Equal(t, "a.example.run", serverName)
+	caName, _, _ := unstructured.NestedString(ep, "tlsConfig", "ca", "secret", "name")
+	require.Equal(t, "ravendb-ca-cert", caName)
+	certName, _, _ := unstructured.NestedString(ep, "tlsConfig", "cert", "secret", "name")
+	require.Equal(t, "prom-client-tls", certName)
+
+	svc := resource.BuildMetricsService(cluster, cluster.Spec.Nodes[0])
+	require.Equal(t, common.ComponentMetrics, svc.Labels[common.LabelComponent])
+	require.Equal(t, common.MetricsPortName, svc.Spec.Ports[0].Name)
+}
+
+func TestBuildMonitor_PodMonitorWithOwnCertAndPublicCA(t *testing.T) {
+	own := "prom-client-tls"
+	cluster := monitoredCluster(ravendbv1.ModeLetsEncrypt, &ravendbv1.MonitoringSpec{Kind: ravendbv1.MonitorKindPodMonitor, ClientCertSecretRef: own})
+
+	mon, err := resource.BuildMonitor(cluster, cluster.Spec.Nodes[0])
+	require.NoError(t, err)
+	require.Equal(t, resource.PodMonitorGVK, mon.GroupVersionKind())
+
+	eps, _, _ := unstructured.NestedSlice(mon.Object, "spec", "podMetricsEndpoints")
+	require.Len(t, eps, 1)
+	ep := eps[0].(map[string]interface{})
+	require.Equal(t, common.HttpsPortName, ep["port"])
+
+	_, hasCA, _ := unstructured.NestedMap(ep, "tlsConfig", "ca")
+	require.False(t, hasCA, "Let's Encrypt certificates verify against the system roots")
+	keyName, _, _ := unstructured.NestedString(ep, "tlsConfig", "keySecret", "name")
+	require.Equal(t, own, keyName)
+}
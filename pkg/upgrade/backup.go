@@ -0,0 +1,276 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// BackupSettings are the destinations of a backup, in RavenDB's shape. Unset ones are disabled.
+type BackupSettings struct {
+	LocalSettings *LocalSettings `json:",omitempty"`
+	S3Settings    *S3Settings    `json:",omitempty"`
+	AzureSettings *AzureSettings `json:",omitempty"`
+}
+
+type LocalSettings struct {
+	Disabled   bool
+	FolderPath string
+}
+
+type S3Settings struct {
+	Disabled         bool
+	BucketName       string
+	RemoteFolderName string `json:",omitempty"`
+	AwsRegionName    string `json:",omitempty"`
+	AwsAccessKey     string
+	AwsSecretKey     string
+	AwsSessionToken  string `json:",omitempty"`
+	CustomServerUrl  string `json:",omitempty"`
+	ForcePathStyle   bool
+}
+
+type AzureSettings struct {
+	Disabled         bool
+	StorageContainer string
+	RemoteFolderName string `json:",omitempty"`
+	AccountName      string
+	AccountKey       string `json:",omitempty"`
+	SasToken         string `json:",omitempty"`
+}
+
+type RetentionPolicy struct {
+	Disabled               bool
+	MinimumBackupAgeToKeep string `json:",omitempty"`
+}
+
+// PeriodicBackupConfiguration is an ongoing backup task. TaskId 0 creates a new one.
+type PeriodicBackupConfiguration struct {
+	TaskId                     int64
+	Name                       string
+	Disabled                   bool
+	BackupType                 string
+	FullBackupFrequency        string
+	IncrementalBackupFrequency string `json:",omitempty"`
+	RetentionPolicy            RetentionPolicy
+	BackupSettings
+}
+
+// PeriodicBackupStatus is how the last runs of a backup task went.
+type PeriodicBackupStatus struct {
+	LastFullBackup        *time.Time
+	LastIncrementalBackup *time.Time
+	LastError             string
+	LastErrorTime         *time.Time
+}
+
+type periodicBackupStatusResponse struct {
+	Status *struct {
+		LastFullBackup        string
+		LastIncrementalBackup string
+		Error                 *struct {
+			Exception string
+			At        string
+		}
+	}
+}
+
+type ongoingTask struct {
+	TaskId   int64
+	TaskName string
+	TaskType string
+}
+
+type ongoingTasksResponse struct {
+	OngoingTasksList []ongoingTask
+	OngoingTasks     []ongoingTask
+}
+
+// PutPeriodicBackup creates or updates a backup task of the database and returns its id.
+func (hcc *HealthCheckContext) PutPeriodicBackup(ctx context.Context, database string, conf PeriodicBackupConfiguration) (int64, error) {
+	body, err := json.Marshal(conf)
+	if err != nil {
+		return 0, err
+	}
+	endpoint, err := hcc.adminURL("/databases/" + url.PathEscape(database) + "/admin/periodic-backup")
+	if err != nil {
+		return 0, err
+	}
+
+	code, resp, err := hcc.httpPOST(ctx, endpoint, body)
+	if err != nil {
+		return 0, fmt.Errorf("put backup task %q of database %q: %w", conf.Name, database, err)
+	}
+	if code < 200 || code >= 300 {
+		return 0, fmt.Errorf("put backup task %q of database %q: HTTP %d (%s)", conf.Name, database, code, summarizeError(resp))
+	}
+
+	var out struct{ TaskId int64 }
+	if err := json.Unmarshal([]byte(resp), &out); err != nil || out.TaskId == 0 {
+		return 0, fmt.Errorf("put backup task %q of database %q: invalid response", conf.Name, database)
+	}
+	return out.TaskId, nil
+}
+
+// BackupTasks lists the backup tasks of the database, by id.
+func (hcc *HealthCheckContext) BackupTasks(ctx context.Context, database string) (map[int64]string, error) {
+	endpoint, err := hcc.adminURL("/databases/" + url.PathEscape(database) + "/tasks")
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("list tasks of database %q: %w", database, err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("list tasks of database %q: HTTP %d (%s)", database, code, summarizeError(body))
+	}
+
+	var resp ongoingTasksResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("list tasks of database %q: invalid /tasks response", database)
+	}
+	out := map[int64]string{}
+	for _, t := range append(resp.OngoingTasksList, resp.OngoingTasks...) {
+		if t.TaskType == "Backup" {
+			out[t.TaskId] = t.TaskName
+		}
+	}
+	return out, nil
+}
+
+// DeleteBackupTask removes a backup task; the backups it took stay. An unknown task or database
+// isn't an error.
+func (hcc *HealthCheckContext) DeleteBackupTask(ctx context.Context, database string, taskId int64) error {
+	q := url.Values{}
+	q.Set("id", strconv.FormatInt(taskId, 10))
+	q.Set("type", "Backup")
+	endpoint, err := hcc.adminURL("/databases/" + url.PathEscape(database) + "/admin/tasks?" + q.Encode())
+	if err != nil {
+		return err
+	}
+
+	code, body, err := hcc.httpSend(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("delete backup task %d of database %q: %w", taskId, database, err)
+	}
+	if code == http.StatusNotFound || code == http.StatusServiceUnavailable {
+		return nil
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("delete backup task %d of database %q: HTTP %d (%s)", taskId, database, code, summarizeError(body))
+	}
+	return nil
+}
+
+// PeriodicBackupStatus reads how the last runs of a backup task went. A task that never ran
+// gives an empty status.
+func (hcc *HealthCheckContext) PeriodicBackupStatus(ctx context.Context, database string, taskId int64) (*PeriodicBackupStatus, error) {
+	endpoint, err := hcc.adminURL("/databases/" + url.PathEscape(database) + "/periodic-backup/status?taskId=" + strconv.FormatInt(taskId, 10))
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("backup status of task %d: %w", taskId, err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("backup status of task %d: HTTP %d (%s)", taskId, code, summarizeError(body))
+	}
+
+	var resp periodicBackupStatusResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("backup status of task %d: invalid response", taskId)
+	}
+	out := &PeriodicBackupStatus{}
+	if resp.Status == nil {
+		return out, nil
+	}
+	out.LastFullBackup = optionalRavenDateTime(resp.Status.LastFullBackup)
+	out.LastIncrementalBackup = optionalRavenDateTime(resp.Status.LastIncrementalBackup)
+	if e := resp.Status.Error; e != nil && e.Exception != "" {
+		out.LastError = summarizeError(e.Exception)
+		out.LastErrorTime = optionalRavenDateTime(e.At)
+	}
+	return out, nil
+}
+
+func optionalRavenDateTime(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := parseRavenDateTime(s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// TimeSpan formats a duration the way .NET reads a TimeSpan: [d.]hh:mm:ss.
+func TimeSpan(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	days := int64(d / (24 * time.Hour))
+	d -= time.Duration(days) * 24 * time.Hour
+	hms := fmt.Sprintf("%02d:%02d:%02d", int64(d/time.Hour), int64(d/time.Minute)%60, int64(d/time.Second)%60)
+	if days > 0 {
+		return fmt.Sprintf("%d.%s", days, hms)
+	}
+	return hms
+}
+
+type backupConfiguration struct {
+	BackupType string
+	BackupSettings
+}
+
+// StartBackup takes a one-time backup of the database, outside of any task, and returns the
+// operation and the node running it.
+func (hcc *HealthCheckContext) StartBackup(ctx context.Context, database, backupType string, settings BackupSettings) (int64, string, error) {
+	body, err := json.Marshal(backupConfiguration{BackupType: backupType, BackupSettings: settings})
+	if err != nil {
+		return 0, "", err
+	}
+	endpoint, err := hcc.adminURL("/databases/" + url.PathEscape(database) + "/admin/backup")
+	if err != nil {
+		return 0, "", err
+	}
+
+	code, resp, err := hcc.httpPOST(ctx, endpoint, body)
+	if err != nil {
+		return 0, "", fmt.Errorf("backup database %q: %w", database, err)
+	}
+	if code < 200 || code >= 300 {
+		return 0, "", fmt.Errorf("backup database %q: HTTP %d (%s)", database, code, summarizeError(resp))
+	}
+
+	var out struct {
+		OperationId     int64
+		ResponsibleNode string
+	}
+	if err := json.Unmarshal([]byte(resp), &out); err != nil || out.OperationId == 0 {
+		return 0, "", fmt.Errorf("backup database %q: invalid response", database)
+	}
+	return out.OperationId, out.ResponsibleNode, nil
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// RegisteredCertificate is a client certificate RavenDB trusts.
+type RegisteredCertificate struct {
+	Name              string
+	Thumbprint        string
+	SecurityClearance ravendbv1.SecurityClearance
+	Permissions       map[string]ravendbv1.DatabaseAccess
+}
+
+type certificatesResponse struct {
+	Results []RegisteredCertificate
+}
+
+type certificateDefinition struct {
+	Name              string
+	Certificate       string `json:",omitempty"`
+	Thumbprint        string `json:",omitempty"`
+	SecurityClearance ravendbv1.SecurityClearance
+	Permissions       map[string]ravendbv1.DatabaseAccess
+}
+
+// Certificates lists the client certificates registered in the cluster, through
+// /admin/certificates (secondary ones included).
+func (hcc *HealthCheckContext) Certificates(ctx context.Context) ([]RegisteredCertificate, error) {
+	endpoint, err := hcc.certificatesURL("/admin/certificates?secondary=true")
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("list certificates: %w", err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("list certificates: HTTP %d (%s)", code, summarizeError(body))
+	}
+
+	var resp certificatesResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("list certificates: invalid /admin/certificates response")
+	}
+	return resp.Results, nil
+}
+
+// RegisterClientCertificate trusts the public certificate with the given clearance and,
+// for ValidUser, per-database permissions.
+func (hcc *HealthCheckContext) RegisterClientCertificate(ctx context.Context, name string, cert *x509.Certificate, clearance ravendbv1.SecurityClearance, permissions map[string]ravendbv1.DatabaseAccess) error {
+	return hcc.sendCertificate(ctx, http.MethodPut, "/admin/certificates", certificateDefinition{
+		Name:              name,
+		Certificate:       base64.StdEncoding.EncodeToString(cert.Raw),
+		SecurityClearance: clearance,
+		Permissions:       nonNilPermissions(permissions),
+	})
+}
+
+// GenerateClientCertificate has RavenDB create and register a client certificate and returns
+// its PFX, which has no password. RavenDB hands it out inside a zip archive.
+func (hcc *HealthCheckContext) GenerateClientCertificate(ctx context.Context, name string, clearance ravendbv1.SecurityClearance, permissions map[string]ravendbv1.DatabaseAccess) ([]byte, error) {
+	endpoint, err := hcc.certificatesURL("/admin/certificates")
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(certificateDefinition{
+		Name:              name,
+		SecurityClearance: clearance,
+		Permissions:       nonNilPermissions(permissions),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	code, resp, err := hcc.httpSend(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("generate certificate %q: %w", name, err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("generate certificate %q: HTTP %d (%s)", name, code, summarizeError(resp))
+	}
+	pfx, err := pfxFromZip([]byte(resp))
+	if err != nil {
+		return nil, fmt.Errorf("generate certificate %q: %w", name, err)
+	}
+	return pfx, nil
+}
+
+func pfxFromZip(data []byte) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("read certificate archive: %w", err)
+	}
+	for _, f := range zr.File {
+		if !strings.HasSuffix(strings.ToLower(f.Name), ".pfx") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("certificate archive has no .pfx")
+}
+
+// EditClientCertificate changes the name, clearance and permissions of a registered certificate.
+func (hcc *HealthCheckContext) EditClientCertificate(ctx context.Context, thumbprint, name string, clearance ravendbv1.SecurityClearance, permissions map[string]ravendbv1.DatabaseAccess) error {
+	return hcc.sendCertificate(ctx, http.MethodPost, "/admin/certificates/edit", certificateDefinition{
+		Name:              name,
+		Thumbprint:        thumbprint,
+		SecurityClearance: clearance,
+		Permissions:       nonNilPermissions(permissions),
+	})
+}
+
+// DeleteCertificate revokes a client certificate. An unknown thumbprint isn't an error.
+func (hcc *HealthCheckContext) DeleteCertificate(ctx context.Context, thumbprint string) error {
+	endpoint, err := hcc.certificatesURL("/admin/certificates?thumbprint=" + url.QueryEscape(thumbprint))
+	if err != nil {
+		return err
+	}
+
+	code, body, err := hcc.httpSend(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("delete certificate %s: %w", thumbprint, err)
+	}
+	if code == http.StatusNotFound {
+		return nil
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("delete certificate %s: HTTP %d (%s)", thumbprint, code, summarizeError(body))
+	}
+	return nil
+}
+
+func (hcc *HealthCheckContext) sendCertificate(ctx context.Context, method, path string, def certificateDefinition) error {
+	endpoint, err := hcc.certificatesURL(path)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(def)
+	if err != nil {
+		return err
+	}
+
+	code, resp, err := hcc.httpSend(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("certificate %q: %w", def.Name, err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("certificate %q: HTTP %d (%s)", def.Name, code, summarizeError(resp))
+	}
+	return nil
+}
+
+func (hcc *HealthCheckContext) certificatesURL(path string) (string, error) {
+	base, err := hcc.clusterURL()
+	if err != nil {
+		return "", err
+	}
+	return join(base, path)
+}
+
+// RavenDB rejects a null Permissions object.
+func nonNilPermissions(p map[string]ravendbv1.DatabaseAccess) map[string]ravendbv1.DatabaseAccess {
+	if p == nil {
+		return map[string]ravendbv1.DatabaseAccess{}
+	}
+	return p
+}
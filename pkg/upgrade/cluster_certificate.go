@@ -0,0 +1,63 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+type replaceClusterCertRequest struct {
+	Name        string
+	Certificate string
+	Password    string
+}
+
+// ReplaceClusterCertificate hands a new server certificate to the cluster through the
+// leader's /admin/certificates/replace-cluster-cert. RavenDB distributes it, every node
+// confirms it and the nodes switch over (running the certificate change hook) once all
+// of them did.
+func (hcc *HealthCheckContext) ReplaceClusterCertificate(ctx context.Context, name string, pfx []byte, password string) error {
+	base, err := hcc.clusterURL()
+	if err != nil {
+		return err
+	}
+	endpoint, err := join(base, "/admin/certificates/replace-cluster-cert?replaceImmediately=false")
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(replaceClusterCertRequest{
+		Name:        name,
+		Certificate: base64.StdEncoding.EncodeToString(pfx),
+		Password:    password,
+	})
+	if err != nil {
+		return err
+	}
+
+	code, resp, err := hcc.httpPOST(ctx, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("replace cluster certificate: %w", err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("replace cluster certificate: HTTP %d (%s)", code, summarizeError(resp))
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type topologyResponse struct {
+	Topology struct {
+		Members     map[string]string
+		Promotables map[string]string
+		Watchers    map[string]string
+	}
+	Leader  string
+	NodeTag string
+	Status  map[string]struct {
+		Connected    bool
+		ErrorDetails string
+	}
+	NodeLicenseDetails map[string]struct {
+		BuildInfo struct {
+			FullVersion string
+		}
+	}
+}
+
+// ClusterTopology is the RavenDB cluster as seen by its leader (or, when the leader
+// can't be reached, by the first node that answered).
+type ClusterTopology struct {
+	Leader      string
+	Members     []string
+	Promotables []string
+	Watchers    []string
+	// Connected/Errors hold the responder's view of its peers, keyed by upper-case tag.
+	Connected map[string]bool
+	Errors    map[string]string
+	Versions  map[string]string
+}
+
+// ClusterTopology asks the nodes for /cluster/topology in spec order and, if the first
+// answer didn't come from the leader, asks the leader again since only it reports the
+// connectivity of every peer.
+func (hcc *HealthCheckContext) ClusterTopology(ctx context.Context, tags []string) (*ClusterTopology, error) {
+	var (
+		tr      *topologyResponse
+		lastErr error
+	)
+	for _, tag := range tags {
+		tr, lastErr = hcc.fetchTopology(ctx, tag)
+		if lastErr == nil {
+			break
+		}
+	}
+	if tr == nil {
+		return nil, lastErr
+	}
+
+	if tr.Leader != "" && !strings.EqualFold(tr.Leader, tr.NodeTag) {
+		if fromLeader, err := hcc.fetchTopology(ctx, tr.Leader); err == nil {
+			tr = fromLeader
+		}
+	}
+
+	return toClusterTopology(tr), nil
+}
+
+func (hcc *HealthCheckContext) fetchTopology(ctx context.Context, tag string) (*topologyResponse, error) {
+	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
+	if nodeURL == "" {
+		return nil, fmt.Errorf("no URL for tag %q", tag)
+	}
+
+	endpoint, err := join(nodeURL, "/cluster/topology")
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("node %s: HTTP %d (%s)", normalizeTag(tag), code, truncate(body, 200))
+	}
+
+	var tr topologyResponse
+	if err := json.Unmarshal([]byte(body), &tr); err != nil {
+		return nil, fmt.Errorf("node %s: invalid /cluster/topology response", normalizeTag(tag))
+	}
+	return &tr, nil
+}
+
+func toClusterTopology(tr *topologyResponse) *ClusterTopology {
+	out := &ClusterTopology{
+		Leader:    normalizeTag(tr.Leader),
+		Connected: map[string]bool{},
+		Errors:    map[string]string{},
+		Versions:  map[string]string{},
+	}
+
+	for tag := range tr.Topology.Members {
+		out.Members = append(out.Members, normalizeTag(tag))
+	}
+	for tag := range tr.Topology.Promotables {
+		out.Promotables = append(out.Promotables, normalizeTag(tag))
+	}
+	for tag := range tr.Topology.Watchers {
+		out.Watchers = append(out.Watchers, normalizeTag(tag))
+	}
+
+	// the responder doesn't report on itself; it answered, so it's connected
+	if tr.NodeTag != "" {
+		out.Connected[normalizeTag(tr.NodeTag)] = true
+	}
+	for tag, st := range tr.Status {
+		out.Connected[normalizeTag(tag)] = st.Connected
+		if e := summarizeError(st.ErrorDetails); e != "" {
+			out.Errors[normalizeTag(tag)] = e
+		}
+	}
+
+	for tag, d := range tr.NodeLicenseDetails {
+		out.Versions[normalizeTag(tag)] = d.BuildInfo.FullVersion
+	}
+
+	return out
+}
+
+// Nodes is every node of the cluster, whatever its role.
+func (t *ClusterTopology) Nodes() []string {
+	return append(append(append([]string{}, t.Members...), t.Promotables...), t.Watchers...)
+}
+
+// RemoveClusterNode takes a node out of the cluster. The leader handles it; other nodes
+// redirect there.
+func (hcc *HealthCheckContext) RemoveClusterNode(ctx context.Context, tag string) error {
+	endpoint, err := hcc.adminURL("/admin/cluster/node?nodeTag=" + url.QueryEscape(normalizeTag(tag)))
+	if err != nil {
+		return err
+	}
+
+	code, body, err := hcc.httpSend(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("remove node %s from the cluster: %w", normalizeTag(tag), err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("remove node %s from the cluster: HTTP %d (%s)", normalizeTag(tag), code, summarizeError(body))
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DatabaseRecord is the part of a database record the operator manages.
+type DatabaseRecord struct {
+	DatabaseName string
+	Disabled     bool
+	Encrypted    bool
+	Topology     DatabaseTopology
+	Settings     map[string]string
+	// DatabaseState is RestoreInProgress while a restore is creating the database.
+	DatabaseState string `json:",omitempty"`
+}
+
+// RestoreInProgress is true while a restore operation is creating the database.
+func (r *DatabaseRecord) RestoreInProgress() bool {
+	return r.DatabaseState == "RestoreInProgress"
+}
+
+type DatabaseTopology struct {
+	Members           []string
+	Promotables       []string
+	Rehabs            []string
+	ReplicationFactor int
+}
+
+// Nodes is every node of the database group, whatever its state.
+func (t DatabaseTopology) Nodes() []string {
+	return append(append(append([]string{}, t.Members...), t.Promotables...), t.Rehabs...)
+}
+
+type deleteDatabasesRequest struct {
+	DatabaseNames []string
+	HardDelete    bool
+	FromNodes     []string `json:",omitempty"`
+}
+
+type toggleDatabasesRequest struct {
+	DatabaseNames []string
+}
+
+// DatabaseRecord reads the record of a database through /admin/databases, nil when there's no
+// such database.
+func (hcc *HealthCheckContext) DatabaseRecord(ctx context.Context, name string) (*DatabaseRecord, error) {
+	endpoint, err := hcc.adminURL("/admin/databases?name=" + url.QueryEscape(name))
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("get database %q: %w", name, err)
+	}
+	if code == http.StatusNotFound {
+		return nil, nil
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("get database %q: HTTP %d (%s)", name, code, summarizeError(body))
+	}
+
+	var record DatabaseRecord
+	if err := json.Unmarshal([]byte(body), &record); err != nil {
+		return nil, fmt.Errorf("get database %q: invalid /admin/databases response", name)
+	}
+	if record.DatabaseName == "" {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+// CreateDatabase creates a database on the given nodes, or on replicationFactor nodes RavenDB
+// picks when there are none. An encrypted database needs its key on those nodes first
+// (DistributeSecret).
+func (hcc *HealthCheckContext) CreateDatabase(ctx context.Context, name string, replicationFactor int, nodes []string, encrypted bool, settings map[string]string) error {
+	record := DatabaseRecord{
+		DatabaseName: name,
+		Encrypted:    encrypted,
+		Settings:     nonNilSettings(settings),
+	}
+	if len(nodes) > 0 {
+		record.Topology = DatabaseTopology{Members: nodes, ReplicationFactor: len(nodes)}
+		replicationFactor = len(nodes)
+	}
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("name", name)
+	q.Set("replicationFactor", strconv.Itoa(replicationFactor))
+	return hcc.sendDatabaseAdmin(ctx, http.MethodPut, "/admin/databases?"+q.Encode(), body, "create database %q", name)
+}
+
+// AddDatabaseNode adds a node to the database group. With an empty tag RavenDB picks one.
+func (hcc *HealthCheckContext) AddDatabaseNode(ctx context.Context, name, tag string) error {
+	q := url.Values{}
+	q.Set("name", name)
+	if tag != "" {
+		q.Set("node", normalizeTag(tag))
+	}
+	return hcc.sendDatabaseAdmin(ctx, http.MethodPut, "/admin/databases/node?"+q.Encode(), nil, "add node %s to database %q", tag, name)
+}
+
+// RemoveDatabaseNode removes a node from the database group and deletes the data it held.
+func (hcc *HealthCheckContext) RemoveDatabaseNode(ctx context.Context, name, tag string) error {
+	body, err := json.Marshal(deleteDatabasesRequest{
+		DatabaseNames: []string{name},
+		HardDelete:    true,
+		FromNodes:     []string{normalizeTag(tag)},
+	})
+	if err != nil {
+		return err
+	}
+	return hcc.sendDatabaseAdmin(ctx, http.MethodDelete, "/admin/databases", body, "remove node %s from database %q", tag, name)
+}
+
+// DeleteDatabase deletes a database from every node, along with its data. An unknown database
+// isn't an error.
+func (hcc *HealthCheckContext) DeleteDatabase(ctx context.Context, name string) error {
+	body, err := json.Marshal(deleteDatabasesRequest{DatabaseNames: []string{name}, HardDelete: true})
+	if err != nil {
+		return err
+	}
+	endpoint, err := hcc.adminURL("/admin/databases")
+	if err != nil {
+		return err
+	}
+
+	code, resp, err := hcc.httpSend(ctx, http.MethodDelete, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("delete database %q: %w", name, err)
+	}
+	if code == http.StatusNotFound || (code >= 400 && strings.Contains(resp, "DatabaseDoesNotExistException")) {
+		return nil
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("delete database %q: HTTP %d (%s)", name, code, summarizeError(resp))
+	}
+	return nil
+}
+
+// PutDatabaseSettings replaces the settings of the database record. They take effect once the
+// database is reloaded (ReloadDatabase).
+func (hcc *HealthCheckContext) PutDatabaseSettings(ctx context.Context, name string, settings map[string]string) error {
+	body, err := json.Marshal(nonNilSettings(settings))
+	if err != nil {
+		return err
+	}
+	path := "/databases/" + url.PathEscape(name) + "/admin/configuration/settings"
+	return hcc.sendDatabaseAdmin(ctx, http.MethodPut, path, body, "put settings of database %q", name)
+}
+
+// ReloadDatabase disables and enables the database again, on every node.
+func (hcc *HealthCheckContext) ReloadDatabase(ctx context.Context, name string) error {
+	body, err := json.Marshal(toggleDatabasesRequest{DatabaseNames: []string{name}})
+	if err != nil {
+		return err
+	}
+	if err := hcc.sendDatabaseAdmin(ctx, http.MethodPost, "/admin/databases/disable", body, "disable database %q", name); err != nil {
+		return err
+	}
+	return hcc.sendDatabaseAdmin(ctx, http.MethodPost, "/admin/databases/enable", body, "enable database %q", name)
+}
+
+// GenerateSecret has RavenDB generate a base64 encryption key.
+func (hcc *HealthCheckContext) GenerateSecret(ctx context.Context) (string, error) {
+	endpoint, err := hcc.adminURL("/admin/secrets/generate")
+	if err != nil {
+		return "", err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return "", fmt.Errorf("generate encryption key: %w", err)
+	}
+	if code < 200 || code >= 300 {
+		return "", fmt.Errorf("generate encryption key: HTTP %d (%s)", code, summarizeError(body))
+	}
+	return strings.TrimSpace(body), nil
+}
+
+// DistributeSecret puts the encryption key of a database on the given nodes.
+func (hcc *HealthCheckContext) DistributeSecret(ctx context.Context, name, key string, tags []string) error {
+	q := url.Values{}
+	q.Set("name", name)
+	for _, tag := range tags {
+		q.Add("node", normalizeTag(tag))
+	}
+	endpoint, err := hcc.adminURL("/admin/secrets/distribute?" + q.Encode())
+	if err != nil {
+		return err
+	}
+
+	code, body, err := hcc.httpPOST(ctx, endpoint, []byte(key))
+	if err != nil {
+		return fmt.Errorf("distribute encryption key of database %q: %w", name, err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("distribute encryption key of database %q: HTTP %d (%s)", name, code, summarizeError(body))
+	}
+	return nil
+}
+
+func (hcc *HealthCheckContext) sendDatabaseAdmin(ctx context.Context, method, path string, body []byte, format string, args ...any) error {
+	endpoint, err := hcc.adminURL(path)
+	if err != nil {
+		return err
+	}
+
+	code, resp, err := hcc.httpSend(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fmt.Sprintf(format, args...), err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("%s: HTTP %d (%s)", fmt.Sprintf(format, args...), code, summarizeError(resp))
+	}
+	return nil
+}
+
+func (hcc *HealthCheckContext) adminURL(path string) (string, error) {
+	base, err := hcc.clusterURL()
+	if err != nil {
+		return "", err
+	}
+	return join(base, path)
+}
+
+// RavenDB rejects a null Settings object.
+func nonNilSettings(s map[string]string) map[string]string {
+	if s == nil {
+		return map[string]string{}
+	}
+	return s
+}
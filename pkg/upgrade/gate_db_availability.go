@@ -3,6 +3,7 @@ package upgrade
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
@@ -35,28 +36,91 @@ var ignoredErrSnippets = []string{
 	"serviceunavailable", "node in rehabilitation",
 }
 
-func (hcc *HealthCheckContext) DatabasesOnline(ctx context.Context, excludedTag string) (bool, string, error) {
+// DatabaseState is a compact view of one database group as /databases reports it.
+type DatabaseState struct {
+	Name              string
+	Disabled          bool
+	ReplicationFactor int
+	Members           []string
+	Promotables       []string
+	Rehabs            []string
+	// NotOk lists the nodes whose LastStatus isn't "Ok"; LastError is the first error among them.
+	NotOk     []string
+	LastError string
+}
+
+// fetches and parses /databases. failures reaching the server or reading its answer are
+// returned as a reason (not an error) so gates can keep polling.
+func (hcc *HealthCheckContext) fetchDatabases(ctx context.Context) (*databasesResponse, string, error) {
 	baseURL, err := hcc.clusterURL()
 	if err != nil {
-		return false, "", err
+		return nil, "", err
 	}
 
 	endpoint, err := join(baseURL, "/databases")
 	if err != nil {
-		return false, "", err
+		return nil, "", err
 	}
 
 	code, body, err := hcc.httpGET(ctx, endpoint)
 	if err != nil {
-		return false, err.Error(), nil
+		return nil, err.Error(), nil
 	}
 	if code < 200 || code >= 300 {
-		return false, fmt.Sprintf("HTTP %d (%s)", code, truncate(body, 200)), nil
+		return nil, fmt.Sprintf("HTTP %d (%s)", code, truncate(body, 200)), nil
 	}
 
 	var dr databasesResponse
 	if json.Unmarshal([]byte(body), &dr) != nil {
-		return false, "invalid /databases response", nil
+		return nil, "invalid /databases response", nil
+	}
+	return &dr, "", nil
+}
+
+// Databases summarizes every database group on the cluster.
+func (hcc *HealthCheckContext) Databases(ctx context.Context) ([]DatabaseState, error) {
+	dr, reason, err := hcc.fetchDatabases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if dr == nil {
+		return nil, errors.New(reason)
+	}
+
+	out := make([]DatabaseState, 0, len(dr.Databases))
+	for _, db := range dr.Databases {
+		st := DatabaseState{
+			Name:              db.Name,
+			Disabled:          db.Disabled,
+			ReplicationFactor: db.ReplicationFactor,
+			Members:           pluckTags(db.NodesTopology.Members),
+			Promotables:       pluckTags(db.NodesTopology.Promotables),
+			Rehabs:            pluckTags(db.NodesTopology.Rehabs),
+		}
+
+		all := append(append(append([]string{}, st.Members...), st.Promotables...), st.Rehabs...)
+		for _, tag := range all {
+			status := db.NodesTopology.Status[tag]
+			if strings.EqualFold(strings.TrimSpace(status.LastStatus), "ok") {
+				continue
+			}
+			st.NotOk = append(st.NotOk, tag)
+			if st.LastError == "" && status.LastError != "" {
+				st.LastError = fmt.Sprintf("node=%s error=%s", tag, summarizeError(status.LastError))
+			}
+		}
+		out = append(out, st)
+	}
+	return out, nil
+}
+
+func (hcc *HealthCheckContext) DatabasesOnline(ctx context.Context, excludedTag string) (bool, string, error) {
+	dr, reason, err := hcc.fetchDatabases(ctx)
+	if err != nil {
+		return false, "", err
+	}
+	if dr == nil {
+		return false, reason, nil
 	}
 	if len(dr.Databases) == 0 {
 		return true, "no databases", nil
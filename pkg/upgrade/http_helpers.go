@@ -17,6 +17,7 @@ limitations under the License.
 package upgrade
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -53,6 +54,36 @@ func (hcc *HealthCheckContext) httpGET(ctx context.Context, rawURL string) (int,
 	return resp.StatusCode, string(body), nil
 }
 
+func (hcc *HealthCheckContext) httpPOST(ctx context.Context, rawURL string, body []byte) (int, string, error) {
+	return hcc.httpSend(ctx, http.MethodPost, rawURL, body)
+}
+
+// httpSend sends a JSON body (none when nil) with the given method.
+func (hcc *HealthCheckContext) httpSend(ctx context.Context, method, rawURL string, body []byte) (int, string, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, reader)
+	if err != nil {
+		return 0, "", err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := hcc.http.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, rerr := io.ReadAll(resp.Body)
+	if rerr != nil {
+		return resp.StatusCode, "", rerr
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
 func (hcc *HealthCheckContext) clusterURL() (string, error) {
 	if hcc.baseURL == "" {
 		return "", errors.New("baseURL is empty")
@@ -20,17 +20,15 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
-	"encoding/pem"
 	"fmt"
 	"net/http"
 	"strings"
 
-	"golang.org/x/crypto/pkcs12"
-
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/certutil"
 )
 
 const (
@@ -39,7 +37,15 @@ const (
 	caCRTKey     = "ca.crt"
 )
 
+// BuildHTTPSClientFromCluster authenticates with the client certificate registered in
+// RavenDB (see ActiveClientCertSecretName), which is clientCertSecretRef outside a rotation.
 func BuildHTTPSClientFromCluster(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*http.Client, error) {
+	return BuildHTTPSClientWithClientCert(ctx, kc, c, c.ActiveClientCertSecretName())
+}
+
+// BuildHTTPSClientWithClientCert builds the cluster's HTTPS client around the client.pfx of
+// the given secret.
+func BuildHTTPSClientWithClientCert(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, secretName string) (*http.Client, error) {
 
 	needCA, err := needsCA(c)
 	if err != nil {
@@ -47,8 +53,8 @@ func BuildHTTPSClientFromCluster(ctx context.Context, kc client.Client, c *raven
 	}
 
 	var clientSecret corev1.Secret
-	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: c.Spec.ClientCertSecretRef}, &clientSecret); err != nil {
-		return nil, fmt.Errorf("get client cert secret %q: %w", c.Spec.ClientCertSecretRef, err)
+	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: secretName}, &clientSecret); err != nil {
+		return nil, fmt.Errorf("get client cert secret %q: %w", secretName, err)
 	}
 
 	pair, err := loadClientPair(clientSecret)
@@ -84,21 +90,17 @@ func needsCA(c *ravendbv1.RavenDBCluster) (bool, error) {
 	}
 }
 
-func pfxToTLSCert(pfx []byte, password string) (tls.Certificate, error) {
-
-	blocks, err := pkcs12.ToPEM(pfx, password)
-	if err != nil {
-		return tls.Certificate{}, fmt.Errorf("decode client.pfx: %w", err)
+// ClientCertificate returns the certificate in the client.pfx of the given secret.
+func ClientCertificate(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, secretName string) (*x509.Certificate, error) {
+	var secret corev1.Secret
+	if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: secretName}, &secret); err != nil {
+		return nil, fmt.Errorf("get client cert secret %q: %w", secretName, err)
 	}
-	var certPEM, keyPEM []byte
-	for _, b := range blocks {
-		if strings.Contains(b.Type, "PRIVATE KEY") {
-			keyPEM = append(keyPEM, pem.EncodeToMemory(b)...)
-		} else {
-			certPEM = append(certPEM, pem.EncodeToMemory(b)...)
-		}
+	pair, err := loadClientPair(secret)
+	if err != nil {
+		return nil, err
 	}
-	return tls.X509KeyPair(certPEM, keyPEM)
+	return x509.ParseCertificate(pair.Certificate[0])
 }
 
 func loadClientPair(secret corev1.Secret) (tls.Certificate, error) {
@@ -107,7 +109,7 @@ func loadClientPair(secret corev1.Secret) (tls.Certificate, error) {
 		return tls.Certificate{}, fmt.Errorf("client secret %q missing %q", secret.GetName(), clientPFXKey)
 	}
 	pass := string(secret.Data[clientPwdKey]) // allow empty password
-	return pfxToTLSCert(pfx, pass)
+	return certutil.PFXKeyPair(pfx, pass)
 }
 
 func loadCAPool(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster) (*x509.CertPool, error) {
@@ -0,0 +1,140 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// License is the license.json RavenDB is handed. Its attributes (type, expiration, limits)
+// are signed and packed into Keys, so only RavenDB reads those; see LicenseStatus.
+type License struct {
+	Id   string
+	Name string
+	Keys []string
+}
+
+// ParseLicense reads license.json.
+func ParseLicense(data []byte) (*License, error) {
+	var l License
+	if err := json.Unmarshal(data, &l); err != nil {
+		return nil, fmt.Errorf("invalid license: %w", err)
+	}
+	return &l, nil
+}
+
+// LicenseStatus is what a node reports about the license it runs with. MaxMemory is in GB;
+// Expiration is nil for a license that doesn't expire.
+type LicenseStatus struct {
+	Id             string
+	LicensedTo     string
+	Type           string
+	Expiration     *time.Time
+	Expired        bool
+	MaxCores       int
+	MaxMemory      int
+	MaxClusterSize int
+}
+
+// LicenseStatus reads one node's /license/status.
+func (hcc *HealthCheckContext) LicenseStatus(ctx context.Context, tag string) (*LicenseStatus, error) {
+	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
+	if nodeURL == "" {
+		return nil, fmt.Errorf("no URL for tag %q", tag)
+	}
+
+	endpoint, err := join(nodeURL, "/license/status")
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("node %s: HTTP %d (%s)", normalizeTag(tag), code, truncate(body, 200))
+	}
+
+	var resp licenseStatusResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("node %s: invalid /license/status response", normalizeTag(tag))
+	}
+	st := &LicenseStatus{
+		Id:             resp.Id,
+		LicensedTo:     resp.LicensedTo,
+		Type:           resp.Type,
+		Expired:        resp.Expired,
+		MaxCores:       resp.MaxCores,
+		MaxMemory:      resp.MaxMemory,
+		MaxClusterSize: resp.MaxClusterSize,
+	}
+	if resp.Expiration != nil && *resp.Expiration != "" {
+		exp, err := parseRavenDateTime(*resp.Expiration)
+		if err != nil {
+			return nil, fmt.Errorf("node %s: license expiration: %w", normalizeTag(tag), err)
+		}
+		st.Expiration = &exp
+	}
+	return st, nil
+}
+
+type licenseStatusResponse struct {
+	Id             string
+	LicensedTo     string
+	Type           string
+	Expiration     *string
+	Expired        bool
+	MaxCores       int
+	MaxMemory      int
+	MaxClusterSize int
+}
+
+// RavenDB writes .NET DateTimes, usually without a zone ("2025-06-30T00:00:00.0000000"),
+// which are UTC.
+func parseRavenDateTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	return time.ParseInLocation("2006-01-02T15:04:05.9999999", s, time.UTC)
+}
+
+// ActivateLicense hands license.json to RavenDB's /admin/license/activate, which applies it
+// to the whole cluster.
+func (hcc *HealthCheckContext) ActivateLicense(ctx context.Context, license []byte) error {
+	base, err := hcc.clusterURL()
+	if err != nil {
+		return err
+	}
+	endpoint, err := join(base, "/admin/license/activate")
+	if err != nil {
+		return err
+	}
+
+	code, body, err := hcc.httpPOST(ctx, endpoint, license)
+	if err != nil {
+		return fmt.Errorf("activate license: %w", err)
+	}
+	if code < 200 || code >= 300 {
+		return fmt.Errorf("activate license: HTTP %d (%s)", code, summarizeError(body))
+	}
+	return nil
+}
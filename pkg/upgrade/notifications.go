@@ -0,0 +1,132 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// the notification center pushes every active notification right after the socket opens,
+// then only new ones. we read until it goes quiet for this long and hang up.
+const notificationIdleWindow = 750 * time.Millisecond
+
+type notificationMessage struct {
+	Id        string
+	Type      string
+	Title     string
+	Message   string
+	Severity  string
+	AlertType string
+	Database  string
+}
+
+// Alert is an active AlertRaised notification from a node's notification center.
+type Alert struct {
+	ID        string
+	Node      string
+	Database  string
+	Severity  string
+	AlertType string
+	Title     string
+	Message   string
+}
+
+// NotificationAlerts snapshots the active alerts of one node's notification center: the
+// server-wide one when database is empty, else that database's. When ctx ends before the
+// center went quiet the snapshot is incomplete: the alerts read so far come back with an error.
+func (hcc *HealthCheckContext) NotificationAlerts(ctx context.Context, tag, database string) ([]Alert, error) {
+	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
+	if nodeURL == "" {
+		return nil, fmt.Errorf("no URL for tag %q", tag)
+	}
+
+	path := "/server/notification-center/watch"
+	if database != "" {
+		path = "/databases/" + url.PathEscape(database) + "/notification-center/watch"
+	}
+	endpoint, err := join(nodeURL, path)
+	if err != nil {
+		return nil, err
+	}
+	endpoint = strings.Replace(endpoint, "https://", "wss://", 1)
+
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	if tr, ok := hcc.http.Transport.(*http.Transport); ok {
+		dialer.TLSClientConfig = tr.TLSClientConfig
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("node %s: notification center HTTP %d", normalizeTag(tag), resp.StatusCode)
+		}
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	defer conn.Close()
+
+	var alerts []Alert
+	for {
+		deadline := time.Now().Add(notificationIdleWindow)
+		cut := false
+		if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+			deadline, cut = d, true
+		}
+		_ = conn.SetReadDeadline(deadline)
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			var nerr net.Error
+			if errors.As(err, &nerr) && nerr.Timeout() {
+				if cut {
+					return alerts, fmt.Errorf("node %s: read notifications: %w", normalizeTag(tag), context.DeadlineExceeded)
+				}
+				break // quiet: we have the snapshot
+			}
+			return alerts, fmt.Errorf("node %s: read notifications: %w", normalizeTag(tag), err)
+		}
+
+		var msg notificationMessage
+		if json.Unmarshal(data, &msg) != nil || msg.Type != "AlertRaised" {
+			continue
+		}
+		if msg.Database == "" {
+			msg.Database = database
+		}
+		alerts = append(alerts, Alert{
+			ID:        msg.Id,
+			Node:      normalizeTag(tag),
+			Database:  msg.Database,
+			Severity:  msg.Severity,
+			AlertType: msg.AlertType,
+			Title:     msg.Title,
+			Message:   summarizeError(msg.Message),
+		})
+	}
+
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return alerts, nil
+}
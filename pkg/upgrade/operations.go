@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// states of a long-running operation
+const (
+	OperationInProgress = "InProgress"
+	OperationCompleted  = "Completed"
+	OperationFaulted    = "Faulted"
+	OperationCanceled   = "Canceled"
+)
+
+// OperationState is where a long-running operation (restore, backup) is at.
+type OperationState struct {
+	Status string
+	// Message is the last progress message, or the error of a faulted operation.
+	Message string
+}
+
+type operationMessages struct {
+	Messages []string
+	Message  string
+	Error    string
+}
+
+type operationStateResponse struct {
+	Status   string
+	Progress *operationMessages
+	Result   *operationMessages
+}
+
+// Done is true once the operation completed, faulted or was canceled.
+func (s *OperationState) Done() bool {
+	return s.Status == OperationCompleted || s.Status == OperationFaulted || s.Status == OperationCanceled
+}
+
+// OperationState reads the state of an operation on the node running it. Server operations have
+// no database. It's nil when the node doesn't know the operation, e.g. after a restart.
+func (hcc *HealthCheckContext) OperationState(ctx context.Context, tag, database string, id int64) (*OperationState, error) {
+	base := strings.TrimSpace(hcc.urlForTag(tag))
+	if base == "" {
+		var err error
+		if base, err = hcc.clusterURL(); err != nil {
+			return nil, err
+		}
+	}
+	path := "/operations/state?id=" + strconv.FormatInt(id, 10)
+	if database != "" {
+		path = "/databases/" + url.PathEscape(database) + path
+	}
+	endpoint, err := join(base, path)
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("operation %d: %w", id, err)
+	}
+	if code == http.StatusNotFound || (code == http.StatusOK && strings.TrimSpace(body) == "null") {
+		return nil, nil
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("operation %d: HTTP %d (%s)", id, code, summarizeError(body))
+	}
+
+	var resp operationStateResponse
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		return nil, fmt.Errorf("operation %d: invalid state response", id)
+	}
+	return &OperationState{Status: resp.Status, Message: lastOperationMessage(resp)}, nil
+}
+
+func lastOperationMessage(resp operationStateResponse) string {
+	for _, m := range []*operationMessages{resp.Result, resp.Progress} {
+		if m == nil {
+			continue
+		}
+		if resp.Status == OperationFaulted && m.Error != "" {
+			return summarizeError(m.Error)
+		}
+		if m.Message != "" {
+			return summarizeError(m.Message)
+		}
+		if n := len(m.Messages); n > 0 {
+			return summarizeError(m.Messages[n-1])
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// RestoreOptions are the parts of a restore that don't depend on where the backup is.
+type RestoreOptions struct {
+	DatabaseName          string
+	LastFileNameToRestore string
+	EncryptionKey         string
+	DisableOngoingTasks   bool
+	SkipIndexes           bool
+}
+
+type backupEncryptionSettings struct {
+	Key            string
+	EncryptionMode string
+}
+
+type restoreConfiguration struct {
+	DatabaseName             string
+	LastFileNameToRestore    string                    `json:",omitempty"`
+	EncryptionKey            string                    `json:",omitempty"`
+	BackupEncryptionSettings *backupEncryptionSettings `json:",omitempty"`
+	DisableOngoingTasks      bool
+	SkipIndexes              bool
+	Type                     string
+	BackupLocation           string `json:",omitempty"`
+	Settings                 any    `json:",omitempty"`
+}
+
+// StartRestore starts restoring a database from the backup in source, and returns the operation
+// and the node running it.
+func (hcc *HealthCheckContext) StartRestore(ctx context.Context, opts RestoreOptions, source BackupSettings) (int64, string, error) {
+	conf := restoreConfiguration{
+		DatabaseName:          opts.DatabaseName,
+		LastFileNameToRestore: opts.LastFileNameToRestore,
+		DisableOngoingTasks:   opts.DisableOngoingTasks,
+		SkipIndexes:           opts.SkipIndexes,
+	}
+	if opts.EncryptionKey != "" {
+		conf.EncryptionKey = opts.EncryptionKey
+		conf.BackupEncryptionSettings = &backupEncryptionSettings{Key: opts.EncryptionKey, EncryptionMode: "UseProvidedKey"}
+	}
+	switch {
+	case source.S3Settings != nil:
+		conf.Type, conf.Settings = "S3", source.S3Settings
+	case source.AzureSettings != nil:
+		conf.Type, conf.Settings = "Azure", source.AzureSettings
+	case source.LocalSettings != nil:
+		conf.Type, conf.BackupLocation = "Local", source.LocalSettings.FolderPath
+	default:
+		return 0, "", fmt.Errorf("restore database %q: no backup source", opts.DatabaseName)
+	}
+
+	body, err := json.Marshal(conf)
+	if err != nil {
+		return 0, "", err
+	}
+	endpoint, err := hcc.adminURL("/admin/restore/database")
+	if err != nil {
+		return 0, "", err
+	}
+
+	code, resp, err := hcc.httpPOST(ctx, endpoint, body)
+	if err != nil {
+		return 0, "", fmt.Errorf("restore database %q: %w", opts.DatabaseName, err)
+	}
+	if code < 200 || code >= 300 {
+		return 0, "", fmt.Errorf("restore database %q: HTTP %d (%s)", opts.DatabaseName, code, summarizeError(resp))
+	}
+
+	var out struct {
+		OperationId      int64
+		OperationNodeTag string
+	}
+	if err := json.Unmarshal([]byte(resp), &out); err != nil || out.OperationId == 0 {
+		return 0, "", fmt.Errorf("restore database %q: invalid response", opts.DatabaseName)
+	}
+	return out.OperationId, out.OperationNodeTag, nil
+}
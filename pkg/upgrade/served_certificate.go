@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const servedCertificateTimeout = 10 * time.Second
+
+// ServedCertificate returns the leaf certificate a node presents on its public URL. Only
+// the handshake runs, to read the certificate, so it isn't verified: during a rotation the
+// node may already serve a certificate our CA pool doesn't know yet.
+func (hcc *HealthCheckContext) ServedCertificate(ctx context.Context, tag string) (*x509.Certificate, error) {
+	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
+	if nodeURL == "" {
+		return nil, fmt.Errorf("no URL for tag %q", tag)
+	}
+	u, err := url.Parse(nodeURL)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	addr := u.Host
+	if u.Port() == "" {
+		addr = net.JoinHostPort(u.Hostname(), "443")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, servedCertificateTimeout)
+	defer cancel()
+
+	dialer := &tls.Dialer{Config: &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: true,
+	}}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	defer conn.Close()
+
+	peers := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(peers) == 0 {
+		return nil, fmt.Errorf("node %s: no certificate presented", normalizeTag(tag))
+	}
+	return peers[0], nil
+}
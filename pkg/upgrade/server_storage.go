@@ -0,0 +1,64 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type serverMetricsResponse struct {
+	Disk struct {
+		TotalFreeSpaceInMb              int64
+		RemainingStorageSpacePercentage int
+	}
+}
+
+// NodeStorage is the free space a node reports for the volume holding its data directory.
+type NodeStorage struct {
+	FreeMB      int64
+	FreePercent int
+}
+
+// NodeStorage reads the disk section of one node's /admin/monitoring/v1/server.
+func (hcc *HealthCheckContext) NodeStorage(ctx context.Context, tag string) (*NodeStorage, error) {
+	nodeURL := strings.TrimSpace(hcc.urlForTag(tag))
+	if nodeURL == "" {
+		return nil, fmt.Errorf("no URL for tag %q", tag)
+	}
+
+	endpoint, err := join(nodeURL, "/admin/monitoring/v1/server")
+	if err != nil {
+		return nil, err
+	}
+
+	code, body, err := hcc.httpGET(ctx, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("node %s: %w", normalizeTag(tag), err)
+	}
+	if code < 200 || code >= 300 {
+		return nil, fmt.Errorf("node %s: HTTP %d (%s)", normalizeTag(tag), code, truncate(body, 200))
+	}
+
+	var mr serverMetricsResponse
+	if err := json.Unmarshal([]byte(body), &mr); err != nil {
+		return nil, fmt.Errorf("node %s: invalid /admin/monitoring/v1/server response", normalizeTag(tag))
+	}
+	return &NodeStorage{FreeMB: mr.Disk.TotalFreeSpaceInMb, FreePercent: mr.Disk.RemainingStorageSpacePercentage}, nil
+}
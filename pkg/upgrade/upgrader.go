@@ -30,6 +30,7 @@ import (
 
 	ravendbv1 "ravendb-operator/api/v1"
 	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/metrics"
 )
 
 type Upgrader interface {
@@ -98,41 +99,33 @@ func buildGatesDefault(ctx context.Context, kc client.Client, c *ravendbv1.Raven
 //     a) if upgrading, run pre-checks and mark it as upgrading with an annotation.
 //     b) call applyNode(node) to mutate its image.
 //     c) if upgraded, run post-checks. On failure, mark node status as Failed.
-//  4. Return statuses for all nodes.
+//  4. Return statuses for all nodes, also when the tick failed.
 func (u *upgrader) Run(
 	ctx context.Context,
 	cluster *ravendbv1.RavenDBCluster,
 	kc client.Client,
 	applyNode ApplyNodeFn,
 ) ([]ravendbv1.RavenDBNodeStatus, error) {
+	prev := buildPrevStatusMap(cluster.Status)
 
 	// 1) build gates (HTTP client to cluster for checks)
 	gates, err := u.buildGates(ctx, kc, cluster)
 	if err != nil {
-		return nil, err
+		return allNodeStatuses(cluster, nil, prev), err
 	}
 
 	desiredImg := desiredNodeImage(cluster)
-	prev := buildPrevStatusMap(cluster.Status)
 
 	// 2) decide which node to work on in this tick
 	selectedTag, err := u.pickSelectedTag(ctx, kc, cluster, desiredImg)
 	if err != nil {
 		// on error, fall back to returning current statuses
-		out := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-		for _, n := range cluster.Spec.Nodes {
-			out = append(out, statusOrCreated(prev, n.Tag))
-		}
-		return out, err
+		return allNodeStatuses(cluster, nil, prev), err
 	}
 
 	// if nothing to do, just return existing statuses
 	if selectedTag == "" {
-		out := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-		for _, n := range cluster.Spec.Nodes {
-			out = append(out, statusOrCreated(prev, n.Tag))
-		}
-		return out, nil
+		return allNodeStatuses(cluster, nil, prev), nil
 	}
 
 	// 3) iterate all nodes - only mutate the chosen one, keep the rest unchanged
@@ -150,7 +143,7 @@ func (u *upgrader) Run(
 		if getErr != nil {
 			// mark upgrade as failed
 			statuses = append(statuses, ravendbv1.RavenDBNodeStatus{Tag: node.Tag, Status: ravendbv1.NodeStatusFailed})
-			return statuses, getErr
+			return allNodeStatuses(cluster, statuses, prev), getErr
 		}
 
 		currentImg := ""
@@ -158,20 +151,22 @@ func (u *upgrader) Run(
 			currentImg = currentStsImage(sts)
 		}
 		marked, _ := u.hasUpgradeAnnotation(ctx, kc, cluster, node.Tag)
-		upgrading := isUpgrading(stsExists, desiredImg, currentImg, marked)
+		upgrading := isUpgrading(stsExists, desiredImg, currentImg, marked) ||
+			(stsExists && (secretsDrifted(ctx, kc, cluster, node, sts) || licenseRestartPending(cluster, sts)))
 
 		// BEFORE: if upgrading and not already marked, run gates + set annotations
 		if upgrading && !marked {
 			if err := u.preNode(ctx, cluster, gates, node.Tag); err != nil {
 				statuses = append(statuses, failedStatus(node.Tag, err.Error(), desiredImg))
-				return statuses, fmt.Errorf("pre-node gates failed for %s: %w", node.Tag, err)
+				return allNodeStatuses(cluster, statuses, prev), fmt.Errorf("pre-node gates failed for %s: %w", node.Tag, err)
 			}
 
 			// mark upgrade intent with target image
+			metrics.SetNodeUpgradeState(cluster.Namespace, cluster.Name, node.Tag, metrics.NodeStateUpgrading)
 			if err := u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, desiredImg); err != nil {
 				statuses = append(statuses, failedStatus(node.Tag, "set upgrade annotation: "+err.Error(), desiredImg))
 				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
-				return statuses, err
+				return allNodeStatuses(cluster, statuses, prev), err
 			}
 		}
 
@@ -181,7 +176,7 @@ func (u *upgrader) Run(
 			if upgrading {
 				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
 			}
-			return statuses, fmt.Errorf("apply node %s failed: %w", node.Tag, err)
+			return allNodeStatuses(cluster, statuses, prev), fmt.Errorf("apply node %s failed: %w", node.Tag, err)
 		}
 
 		// AFTER: only for real upgrades (not first creation)
@@ -194,7 +189,7 @@ func (u *upgrader) Run(
 					desiredImg,
 				))
 				_ = u.setUpgradeAnnotation(ctx, kc, cluster, node.Tag, "")
-				return statuses, fmt.Errorf("post-node gates failed for %s: %w", node.Tag, err)
+				return allNodeStatuses(cluster, statuses, prev), fmt.Errorf("post-node gates failed for %s: %w", node.Tag, err)
 			}
 
 			// success so cleanup annotation
@@ -207,15 +202,37 @@ func (u *upgrader) Run(
 	}
 
 	// 4) keep order like Spec.Nodes
+	return allNodeStatuses(cluster, statuses, prev), nil
+}
+
+// allNodeStatuses orders statuses like Spec.Nodes. Nodes the tick didn't get to, e.g. because
+// it failed on an earlier one, keep their last status.
+func allNodeStatuses(c *ravendbv1.RavenDBCluster, statuses []ravendbv1.RavenDBNodeStatus, prev map[string]ravendbv1.RavenDBNodeStatus) []ravendbv1.RavenDBNodeStatus {
 	byUpper := make(map[string]ravendbv1.RavenDBNodeStatus, len(statuses))
 	for _, s := range statuses {
 		byUpper[normalizeTag(s.Tag)] = s
 	}
-	ordered := make([]ravendbv1.RavenDBNodeStatus, 0, len(cluster.Spec.Nodes))
-	for _, n := range cluster.Spec.Nodes {
-		ordered = append(ordered, byUpper[normalizeTag(n.Tag)])
+	ordered := make([]ravendbv1.RavenDBNodeStatus, 0, len(c.Spec.Nodes))
+	for _, n := range c.Spec.Nodes {
+		s, ok := byUpper[normalizeTag(n.Tag)]
+		if !ok {
+			s = statusOrCreated(prev, n.Tag)
+		}
+		ordered = append(ordered, keepObserved(s, prev))
+	}
+	return ordered
+}
+
+// the node worked on gets a fresh status, whether it succeeded or failed; what the health
+// evaluator observed about it (topology, restart history) isn't the upgrader's to reset.
+func keepObserved(s ravendbv1.RavenDBNodeStatus, prev map[string]ravendbv1.RavenDBNodeStatus) ravendbv1.RavenDBNodeStatus {
+	p, ok := prev[normalizeTag(s.Tag)]
+	if !ok {
+		return s
 	}
-	return ordered, nil
+	s.Role, s.Connected, s.ServerVersion = p.Role, p.Connected, p.ServerVersion
+	s.Restarts, s.RecentRestarts = p.Restarts, p.RecentRestarts
+	return s
 }
 
 // looks for a node which StatefulSet has the "upgrade image" annotation.
@@ -338,9 +355,50 @@ func (u *upgrader) pickSelectedTag(ctx context.Context, kc client.Client, c *rav
 		}
 	}
 
+	// and finally the first one whose mounted secrets changed
+	for _, n := range c.Spec.Nodes {
+		name := statefulSetName(n.Tag)
+		var sts appsv1.StatefulSet
+		if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, &sts); err == nil {
+			if secretsDrifted(ctx, kc, c, n, &sts) {
+				return normalizeTag(n.Tag), nil
+			}
+		}
+	}
+
+	// and the first one not restarted yet for a license the API didn't take
+	for _, n := range c.Spec.Nodes {
+		name := statefulSetName(n.Tag)
+		var sts appsv1.StatefulSet
+		if err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: name}, &sts); err == nil {
+			if licenseRestartPending(c, &sts) {
+				return normalizeTag(n.Tag), nil
+			}
+		}
+	}
+
 	return "", nil
 }
 
+// reports whether the node still has to restart to load the license of status.licenseUpdate.
+func licenseRestartPending(c *ravendbv1.RavenDBCluster, sts *appsv1.StatefulSet) bool {
+	id := c.LicenseRestartId()
+	return id != "" && sts.Spec.Template.Annotations[common.LicenseRestartAnnotation] != id
+}
+
+// reports whether the node's pod template carries a different secrets hash than the
+// live secrets produce. Only meaningful with spec.rollOnSecretChange.
+func secretsDrifted(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, node ravendbv1.RavenDBNode, sts *appsv1.StatefulSet) bool {
+	if !c.Spec.RollOnSecretChange {
+		return false
+	}
+	desired, err := common.NodeSecretsHash(ctx, kc, c, node)
+	if err != nil {
+		return false
+	}
+	return sts.Spec.Template.Annotations[common.SecretsHashAnnotation] != desired
+}
+
 func (u *upgrader) loadSTSByNodeTag(ctx context.Context, kc client.Client, c *ravendbv1.RavenDBCluster, tag string) (*appsv1.StatefulSet, bool, error) {
 	var sts appsv1.StatefulSet
 	err := kc.Get(ctx, client.ObjectKey{Namespace: c.Namespace, Name: statefulSetName(tag)}, &sts)
@@ -0,0 +1,93 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package upgrade
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ravendbv1 "ravendb-operator/api/v1"
+)
+
+// restartingCluster has nodes A and B, both with a restart history, and no StatefulSets yet.
+func restartingCluster() *ravendbv1.RavenDBCluster {
+	restarts := int32(4)
+	recent := []metav1.Time{metav1.NewTime(time.Now().Add(-time.Minute))}
+	return &ravendbv1.RavenDBCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "raven", Namespace: "ravendb"},
+		Spec:       ravendbv1.RavenDBClusterSpec{Nodes: []ravendbv1.RavenDBNode{{Tag: "A"}, {Tag: "B"}}},
+		Status: ravendbv1.RavenDBClusterStatus{Nodes: []ravendbv1.RavenDBNodeStatus{
+			{Tag: "A", Status: ravendbv1.NodeStatusCreated, Restarts: &restarts, RecentRestarts: recent},
+			{Tag: "B", Status: ravendbv1.NodeStatusCreated, Restarts: &restarts, RecentRestarts: recent},
+		}},
+	}
+}
+
+func emptyClient(t *testing.T) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).Build()
+}
+
+func requireRestartHistory(t *testing.T, statuses []ravendbv1.RavenDBNodeStatus) {
+	t.Helper()
+	require.Len(t, statuses, 2)
+	for _, s := range statuses {
+		require.NotNil(t, s.Restarts, "node %s", s.Tag)
+		require.Equal(t, int32(4), *s.Restarts)
+		require.Len(t, s.RecentRestarts, 1)
+	}
+}
+
+func TestRun_KeepsRestartHistoryWhenTheGatesCantBeBuilt(t *testing.T) {
+	u := &upgrader{buildGates: func(context.Context, client.Client, *ravendbv1.RavenDBCluster) (*HealthCheckContext, error) {
+		return nil, errors.New("no client certificate")
+	}}
+	applied := false
+
+	statuses, err := u.Run(context.Background(), restartingCluster(), emptyClient(t), func(ravendbv1.RavenDBNode) error {
+		applied = true
+		return nil
+	})
+	require.Error(t, err)
+	require.False(t, applied)
+	requireRestartHistory(t, statuses)
+}
+
+func TestRun_KeepsRestartHistoryWhenANodeFails(t *testing.T) {
+	u := &upgrader{buildGates: func(context.Context, client.Client, *ravendbv1.RavenDBCluster) (*HealthCheckContext, error) {
+		return &HealthCheckContext{}, nil
+	}}
+
+	statuses, err := u.Run(context.Background(), restartingCluster(), emptyClient(t), func(ravendbv1.RavenDBNode) error {
+		return errors.New("apply failed")
+	})
+	require.Error(t, err)
+	requireRestartHistory(t, statuses)
+	require.Equal(t, ravendbv1.NodeStatusFailed, statuses[0].Status)
+	require.Equal(t, ravendbv1.NodeStatusCreated, statuses[1].Status, "the node after the failed one keeps its status")
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/metrics"
 	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -88,6 +89,7 @@ func (u *upgrader) wait(
 			if u.emit != nil {
 				u.emit(c, GateTimeout, phase, kind, tag, msg)
 			}
+			observeGate(GateTimeout, phase, kind, start, true)
 			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: msg}
 		}
 
@@ -98,6 +100,7 @@ func (u *upgrader) wait(
 			if u.emit != nil {
 				u.emit(c, GateBlock, phase, kind, tag, err.Error())
 			}
+			observeGate(GateBlock, phase, kind, start, true)
 			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: err.Error()}
 		}
 
@@ -105,6 +108,7 @@ func (u *upgrader) wait(
 			if u.emit != nil {
 				u.emit(c, GatePass, phase, kind, tag, "")
 			}
+			observeGate(GatePass, phase, kind, start, true)
 			return nil
 		}
 
@@ -115,6 +119,7 @@ func (u *upgrader) wait(
 			u.emit(c, GateBlock, phase, kind, tag,
 				fmt.Sprintf("retry in %s (attempt %d): %s", sleep, attempt, summarizeError(lastInfo)))
 		}
+		observeGate(GateBlock, phase, kind, start, false)
 
 		// check if we did we run out of time
 		if time.Since(start) >= maxWait {
@@ -127,6 +132,7 @@ func (u *upgrader) wait(
 			if u.emit != nil {
 				u.emit(c, GateTimeout, phase, kind, tag, msg)
 			}
+			observeGate(GateTimeout, phase, kind, start, true)
 			return &GateError{Phase: phase, Kind: kind, Tag: tag, Info: msg}
 		}
 
@@ -139,6 +145,10 @@ func (u *upgrader) wait(
 	}
 }
 
+func observeGate(state GateState, phase GatePhase, kind GateKind, start time.Time, terminal bool) {
+	metrics.ObserveGate(string(kind), string(phase), string(state), time.Since(start), terminal)
+}
+
 func (u *upgrader) maxWaitFor(phase GatePhase) time.Duration {
 	if phase == GatePreStep {
 		return u.timing.PreMaxWait
@@ -54,4 +54,10 @@ type ClusterAdapter interface {
 	GetAdditionalVolumeSources() []map[string]bool
 	GetClientCertSecretRef() string
 	GetCACertSecretRef() *string
+	GetCertManagerIssuerName() string
+	GetTrustedClientCertNames() []string
+	GetTrustedClientCertSources() []string
+	GetLicenseMaxClusterSize() int32
+	IsDeletionProtectionEnabled() bool
+	IsBeingDeleted() bool
 }
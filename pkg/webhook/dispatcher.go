@@ -44,3 +44,7 @@ func ValidateCreate(ctx context.Context, cluster ClusterAdapter) error {
 func ValidateUpdate(ctx context.Context, oldCluster, newCluster ClusterAdapter) error {
 	return validator.RunUpdate(ctx, oldCluster, newCluster)
 }
+
+func ValidateDelete(ctx context.Context, cluster ClusterAdapter) error {
+	return validator.RunDelete(ctx, cluster)
+}
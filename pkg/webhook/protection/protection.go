@@ -0,0 +1,156 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package protection
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	"ravendb-operator/pkg/webhook/validator"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// +kubebuilder:webhook:path=/validate-ravendb-protected-resources,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps;"",resources=statefulsets;persistentvolumeclaims,verbs=delete,versions=v1,name=vravendbprotection.kb.io,admissionReviewVersions=v1
+
+const Path = "/validate-ravendb-protected-resources"
+
+var protectionlog = logf.Log.WithName("ravendb-protection")
+
+// Handler rejects deletes of the StatefulSets and PVCs that back a RavenDBCluster
+// while that cluster has spec.deletionProtection set. Deletes issued as part of
+// deleting the cluster itself (deletionTimestamp set) are always allowed.
+type Handler struct {
+	client  client.Reader
+	decoder admission.Decoder
+}
+
+func NewHandler(c client.Reader, decoder admission.Decoder) *Handler {
+	return &Handler{client: c, decoder: decoder}
+}
+
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete {
+		return admission.Allowed("")
+	}
+
+	var (
+		cluster *ravendbv1.RavenDBCluster
+		err     error
+	)
+
+	switch req.Kind.Kind {
+	case "StatefulSet":
+		sts := &appsv1.StatefulSet{}
+		if err := h.decoder.DecodeRaw(req.OldObject, sts); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		cluster, err = h.clusterForStatefulSet(ctx, sts)
+
+	case "PersistentVolumeClaim":
+		pvc := &corev1.PersistentVolumeClaim{}
+		if err := h.decoder.DecodeRaw(req.OldObject, pvc); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		cluster, err = h.clusterForPVC(ctx, pvc)
+
+	default:
+		return admission.Allowed("")
+	}
+
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if cluster == nil || cluster.IsBeingDeleted() {
+		return admission.Allowed("")
+	}
+
+	if errs := validator.ValidateDeletionProtection(cluster); len(errs) > 0 {
+		protectionlog.Info("rejecting delete of protected resource", "kind", req.Kind.Kind, "name", req.Name, "cluster", cluster.Name)
+		return admission.Denied(fmt.Sprintf("%s %s/%s belongs to RavenDBCluster %s: %s",
+			req.Kind.Kind, req.Namespace, req.Name, cluster.Name, strings.Join(errs, "; ")))
+	}
+
+	return admission.Allowed("")
+}
+
+// clusterForStatefulSet resolves the owning cluster through the instance label.
+// StatefulSets whose node tag is no longer part of the spec are orphans and are
+// not protected.
+func (h *Handler) clusterForStatefulSet(ctx context.Context, sts *appsv1.StatefulSet) (*ravendbv1.RavenDBCluster, error) {
+	if sts.Labels[common.LabelManagedBy] != common.Manager {
+		return nil, nil
+	}
+
+	name := sts.Labels[common.LabelInstance]
+	if name == "" {
+		return nil, nil
+	}
+
+	cluster := &ravendbv1.RavenDBCluster{}
+	if err := h.client.Get(ctx, client.ObjectKey{Namespace: sts.Namespace, Name: name}, cluster); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get RavenDBCluster %s/%s: %w", sts.Namespace, name, err)
+	}
+
+	for _, tag := range cluster.GetNodeTags() {
+		if sts.Name == common.Prefix+tag {
+			return cluster, nil
+		}
+	}
+	return nil, nil
+}
+
+// clusterForPVC resolves the owning cluster by name. PVCs created from the
+// StatefulSet volumeClaimTemplates only carry the selector label, so we match
+// on the "<template>-ravendb-<tag>-0" naming the StatefulSet controller uses.
+func (h *Handler) clusterForPVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (*ravendbv1.RavenDBCluster, error) {
+	if _, ok := pvc.Labels[common.LabelNodeTag]; !ok {
+		return nil, nil
+	}
+
+	var clusters ravendbv1.RavenDBClusterList
+	if err := h.client.List(ctx, &clusters, client.InNamespace(pvc.Namespace)); err != nil {
+		return nil, fmt.Errorf("list RavenDBClusters in %s: %w", pvc.Namespace, err)
+	}
+
+	templates := []string{common.DataVolumeName, common.LogsVolumeName, common.AuditVolumeName}
+
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		for _, tag := range cluster.GetNodeTags() {
+			for _, tmpl := range templates {
+				if pvc.Name == fmt.Sprintf("%s-%s%s-0", tmpl, common.Prefix, tag) {
+					return cluster, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"crypto/x509"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// pfxKey returns the key of the .pfx file in a server certificate secret, the first in
+// order when there are several (rejected by the secret checks). Empty when there's none.
+func pfxKey(data map[string][]byte) string {
+	keys := slices.Sorted(maps.Keys(data))
+	if i := slices.IndexFunc(keys, func(k string) bool { return strings.HasSuffix(k, ".pfx") }); i >= 0 {
+		return keys[i]
+	}
+	return ""
+}
+
+// ValidateServerCertificate checks what RavenDB needs from a server certificate: SANs
+// covering every host the node(s) are reached on, a validity window containing now and
+// the client-auth EKU, which nodes present to each other when joining the cluster.
+func ValidateServerCertificate(label string, cert *x509.Certificate, hosts []string, now time.Time) []string {
+	var errs []string
+
+	for _, host := range hosts {
+		if err := cert.VerifyHostname(host); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: certificate SANs %v do not cover host '%s'", label, certificateNames(cert), host))
+		}
+	}
+
+	if now.After(cert.NotAfter) {
+		errs = append(errs, fmt.Sprintf("%s: certificate expired on %s", label, cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+	if now.Before(cert.NotBefore) {
+		errs = append(errs, fmt.Sprintf("%s: certificate is not valid before %s", label, cert.NotBefore.UTC().Format(time.RFC3339)))
+	}
+
+	if len(cert.ExtKeyUsage) > 0 &&
+		!slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageClientAuth) &&
+		!slices.Contains(cert.ExtKeyUsage, x509.ExtKeyUsageAny) {
+		errs = append(errs, fmt.Sprintf("%s: certificate must allow client authentication (extended key usage clientAuth)", label))
+	}
+
+	return errs
+}
+
+// urlHosts returns the host part of each URL, skipping the ones ValidateNodeUrl rejects anyway.
+func urlHosts(urls ...string) []string {
+	var hosts []string
+	for _, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	return hosts
+}
+
+func certificateNames(cert *x509.Certificate) []string {
+	names := slices.Clone(cert.DNSNames)
+	for _, ip := range cert.IPAddresses {
+		names = append(names, ip.String())
+	}
+	return names
+}
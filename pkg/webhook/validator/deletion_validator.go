@@ -0,0 +1,62 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type deletionValidator struct{}
+
+func NewDeletionValidator() *deletionValidator {
+	return &deletionValidator{}
+}
+
+func (v *deletionValidator) Name() string {
+	return "deletion-validator"
+}
+
+func (v *deletionValidator) ValidateCreate(_ context.Context, _ ClusterAdapter) error {
+	return nil
+}
+
+func (v *deletionValidator) ValidateUpdate(_ context.Context, _, _ ClusterAdapter) error {
+	return nil
+}
+
+func (v *deletionValidator) ValidateDelete(_ context.Context, c ClusterAdapter) error {
+	errs := ValidateDeletionProtection(c)
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// ValidateDeletionProtection rejects deletes while spec.deletionProtection is set.
+// It is shared with the protected-resources webhook so the message is the same
+// whether the cluster, one of its StatefulSets or one of its PVCs is targeted.
+func ValidateDeletionProtection(c ClusterAdapter) []string {
+	var errs []string
+
+	if c.IsDeletionProtectionEnabled() {
+		errs = append(errs, "spec.deletionProtection is enabled; set it to false before deleting")
+	}
+
+	return errs
+}
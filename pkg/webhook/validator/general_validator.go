@@ -18,10 +18,14 @@ package validator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"ravendb-operator/pkg/certutil"
 	"ravendb-operator/pkg/webhook/adapter"
+	"slices"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -53,11 +57,23 @@ func (v *generalValidator) ValidateCreate(ctx context.Context, c ClusterAdapter)
 
 	errs = append(errs, ValidateEmail(mode, email)...)
 	errs = append(errs, ValidateLicenseSecret(v, ctx, license)...)
-	errs = append(errs, ValidateClusterCertSecret(v, ctx, mode, clusterCert)...)
 	errs = append(errs, ValidateDomain(domain)...)
 	errs = append(errs, ValidateEnv(envVars)...)
-	errs = append(errs, ValidateClientCertSecret(v, ctx, clientCert)...)
-	errs = append(errs, ValidateCACertSecret(v, ctx, mode, caCert)...)
+
+	// with cert-manager the operator writes the certificate secrets, so there's nothing to read yet
+	if c.GetCertManagerIssuerName() != "" {
+		errs = append(errs, ValidateCertManager(mode, clusterCert, clientCert, caCert)...)
+	} else {
+		clusterCertErrs := ValidateClusterCertSecret(v, ctx, mode, clusterCert)
+		errs = append(errs, clusterCertErrs...)
+		if len(clusterCertErrs) == 0 {
+			errs = append(errs, ValidateClusterCertificate(v, ctx, clusterCert, clusterHosts(c))...)
+		}
+		errs = append(errs, ValidateClientCertSecret(v, ctx, clientCert)...)
+		errs = append(errs, ValidateCACertSecret(v, ctx, mode, caCert)...)
+	}
+
+	errs = append(errs, ValidateTrustedClientCertificates(v, ctx, c.GetTrustedClientCertNames(), c.GetTrustedClientCertSources(), nil)...)
 
 	if len(errs) > 0 {
 		return fmt.Errorf("%s", strings.Join(errs, "\n"))
@@ -72,6 +88,27 @@ func (v *generalValidator) ValidateUpdate(ctx context.Context, oldC, newC Cluste
 
 	errs = append(errs, ValidateImmutableOnceCreated(ctx, oldC, newC)...)
 
+	if newC.GetCertManagerIssuerName() != "" {
+		errs = append(errs, ValidateCertManager(newC.GetMode(), newC.GetClusterCertsSecretRef(), newC.GetClientCertSecretRef(), newC.GetCACertSecretRef())...)
+	} else if clusterCert := newC.GetClusterCertsSecretRef(); clusterCert != oldC.GetClusterCertsSecretRef() {
+		// node URLs are immutable, so the cluster certificate only needs a second look when it's swapped
+		clusterCertErrs := ValidateClusterCertSecret(v, ctx, newC.GetMode(), clusterCert)
+		errs = append(errs, clusterCertErrs...)
+		if len(clusterCertErrs) == 0 {
+			errs = append(errs, ValidateClusterCertificate(v, ctx, clusterCert, clusterHosts(newC))...)
+		}
+	}
+
+	// a new client certificate is registered in RavenDB with the old one, so it must be readable
+	if newC.GetCertManagerIssuerName() == "" && newC.GetClientCertSecretRef() != oldC.GetClientCertSecretRef() {
+		errs = append(errs, ValidateClientCertSecret(v, ctx, newC.GetClientCertSecretRef())...)
+	}
+
+	errs = append(errs, ValidateTrustedClientCertificates(v, ctx, newC.GetTrustedClientCertNames(), newC.GetTrustedClientCertSources(), oldC.GetTrustedClientCertSources())...)
+
+	// the cluster size is the one RavenDB reported for the running license, kept in the old object's status
+	errs = append(errs, ValidateLicenseLimits(len(oldC.GetNodeTags()), len(newC.GetNodeTags()), oldC.GetLicenseMaxClusterSize())...)
+
 	if len(errs) > 0 {
 		return fmt.Errorf("%s", strings.Join(errs, "\n"))
 	}
@@ -105,15 +142,31 @@ func ValidateLicenseSecret(v *generalValidator, ctx context.Context, license str
 		return errs
 	}
 
-	for key := range secret.Data {
+	for key, data := range secret.Data {
 		if !strings.HasSuffix(key, ".json") {
 			errs = append(errs, fmt.Sprintf("spec.licenseSecretRef: secret '%s' must contain a file ending with '.json', got '%s' instead", license, key))
+		} else if !json.Valid(data) {
+			errs = append(errs, fmt.Sprintf("spec.licenseSecretRef: '%s' in secret '%s' is not valid JSON", key, license))
 		}
 		break
 	}
 	return errs
 }
 
+// ValidateLicenseLimits rejects growing the cluster beyond the cluster size of the license RavenDB
+// reported; zero wasn't reported and isn't checked. Only the cluster size is: RavenDB assigns the
+// license's cores and memory to the nodes itself, and the spec sets no container limits to hold
+// against them.
+func ValidateLicenseLimits(oldNodes, newNodes int, maxClusterSize int32) []string {
+	if newNodes <= oldNodes {
+		return nil
+	}
+	if maxClusterSize > 0 && int32(newNodes) > maxClusterSize {
+		return []string{fmt.Sprintf("spec.nodes: %d nodes exceed the license's cluster size of %d", newNodes, maxClusterSize)}
+	}
+	return nil
+}
+
 func ValidateClusterCertSecret(v *generalValidator, ctx context.Context, mode, clusterCert string) []string {
 	var errs []string
 
@@ -150,6 +203,95 @@ func ValidateClusterCertSecret(v *generalValidator, ctx context.Context, mode, c
 	return errs
 }
 
+// ValidateClusterCertificate decodes the cluster .pfx (already checked by
+// ValidateClusterCertSecret). Every node serves it, so it must cover all node hosts.
+func ValidateClusterCertificate(v *generalValidator, ctx context.Context, clusterCert string, hosts []string) []string {
+	if clusterCert == "" {
+		return nil
+	}
+
+	secret, err := v.getSecret(ctx, clusterCert)
+	if err != nil {
+		return []string{fmt.Sprintf("spec.clusterCertSecretRef: %v", err)}
+	}
+
+	key := pfxKey(secret.Data)
+	if key == "" {
+		return nil // reported by ValidateClusterCertSecret
+	}
+	// server certificate secrets carry no password key
+	cert, err := certutil.ParsePFXCertificate(secret.Data[key], "")
+	if err != nil {
+		return []string{fmt.Sprintf("spec.clusterCertSecretRef: secret '%s' file '%s': %v", clusterCert, key, err)}
+	}
+	return ValidateServerCertificate("spec.clusterCertSecretRef", cert, hosts, time.Now())
+}
+
+func clusterHosts(c ClusterAdapter) []string {
+	return append(urlHosts(c.GetNodePublicUrls()...), urlHosts(c.GetNodeTcpUrls()...)...)
+}
+
+// ValidateCertManager checks spec.certManager: it only exists for None mode, and the
+// operator needs three distinct secrets to write the issued certificates into.
+func ValidateCertManager(mode, clusterCert, clientCert string, caCert *string) []string {
+	var errs []string
+
+	if mode != "None" {
+		errs = append(errs, "spec.certManager is only supported when mode is None")
+		return errs
+	}
+	if clusterCert == "" {
+		errs = append(errs, "spec.clusterCertSecretRef is required when spec.certManager is set")
+	}
+	if caCert == nil || *caCert == "" {
+		errs = append(errs, "spec.caCertSecretRef is required when spec.certManager is set")
+		return errs
+	}
+
+	if clusterCert == clientCert || clusterCert == *caCert || clientCert == *caCert {
+		errs = append(errs, "spec.certManager: clusterCertSecretRef, clientCertSecretRef and caCertSecretRef must name different secrets")
+	}
+
+	return errs
+}
+
+// ValidateTrustedClientCertificates requires unique names and, for sources ("secret/key") not
+// already in use, a secret key holding a PEM certificate.
+func ValidateTrustedClientCertificates(v *generalValidator, ctx context.Context, names, sources, oldSources []string) []string {
+	var errs []string
+
+	seen := map[string]bool{}
+	for _, name := range names {
+		if seen[name] {
+			errs = append(errs, fmt.Sprintf("spec.trustedClientCertificates: name '%s' is used more than once", name))
+		}
+		seen[name] = true
+	}
+
+	for i, source := range sources {
+		if slices.Contains(oldSources, source) {
+			continue
+		}
+		secretName, key, _ := strings.Cut(source, "/")
+		label := fmt.Sprintf("spec.trustedClientCertificates[name=%s]", names[i])
+
+		secret, err := v.getSecret(ctx, secretName)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", label, err))
+			continue
+		}
+		data, ok := secret.Data[key]
+		if !ok || len(data) == 0 {
+			errs = append(errs, fmt.Sprintf("%s: secret '%s' has no key '%s'", label, secretName, key))
+			continue
+		}
+		if _, err := certutil.ParsePEMCertificate(data); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: secret '%s' key '%s': %v", label, secretName, key, err))
+		}
+	}
+	return errs
+}
+
 func ValidateDomain(domain string) []string {
 	var errs []string
 
@@ -22,6 +22,9 @@ import (
 	"net"
 	"net/url"
 	"strings"
+	"time"
+
+	"ravendb-operator/pkg/certutil"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -48,16 +51,27 @@ type nodeInput struct {
 }
 
 func (v *nodeValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) error {
-	var errs []string
+	return v.validate(ctx, c, nil)
+}
+
+// ValidateUpdate re-runs the create checks, but only decodes the certificates of nodes
+// whose certSecretRef changed: an already accepted certificate that expired or broke must
+// not block the spec changes (e.g. an image upgrade) made to recover from it.
+func (v *nodeValidator) ValidateUpdate(ctx context.Context, oldC, newC ClusterAdapter) error {
+	oldCerts := map[string]string{}
+	for _, n := range nodeInputs(oldC) {
+		oldCerts[n.Tag] = n.CertSecret
+	}
+	return v.validate(ctx, newC, oldCerts)
+}
+
+func nodeInputs(c ClusterAdapter) []nodeInput {
 	var input []nodeInput
 
 	tags := c.GetNodeTags()
 	pubUrls := c.GetNodePublicUrls()
 	tcpUrls := c.GetNodeTcpUrls()
 	certRefs := c.GetNodeCertSecretRefs()
-	mode := c.GetMode()
-	domain := c.GetDomain()
-	extAccType := c.GetExternalAccessType()
 
 	for i := range tags {
 		var cert string
@@ -72,6 +86,22 @@ func (v *nodeValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) er
 			CertSecret: cert,
 		})
 	}
+	return input
+}
+
+// validate runs the node checks; oldCerts holds the node cert refs before an update and
+// is nil on create.
+func (v *nodeValidator) validate(ctx context.Context, c ClusterAdapter, oldCerts map[string]string) error {
+	var errs []string
+
+	tags := c.GetNodeTags()
+	pubUrls := c.GetNodePublicUrls()
+	tcpUrls := c.GetNodeTcpUrls()
+	mode := c.GetMode()
+	domain := c.GetDomain()
+	extAccType := c.GetExternalAccessType()
+	input := nodeInputs(c)
+
 	errs = append(errs, ValidateNodesNotEmpty(tags)...)
 	errs = append(errs, ValidateUniqueTags(tags)...)
 	errs = append(errs, ValidateUniqueUrls(pubUrls, tcpUrls)...)
@@ -80,7 +110,12 @@ func (v *nodeValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) er
 	for _, n := range input {
 		errs = append(errs, ValidateNodeUrl(n.Tag, n.PublicUrl, domain, "https", "publicServerUrl", n.Tag+".")...)
 		errs = append(errs, ValidateNodeUrl(n.Tag, n.TcpUrl, domain, "tcp", "publicServerUrlTcp", n.Tag+"-tcp.")...)
-		errs = append(errs, ValidateNodeCertSecret(ctx, v, mode, n.Tag, n.CertSecret)...)
+
+		certErrs := ValidateNodeCertSecret(ctx, v, mode, n.Tag, n.CertSecret)
+		errs = append(errs, certErrs...)
+		if old, ok := oldCerts[n.Tag]; len(certErrs) == 0 && (!ok || old != n.CertSecret) {
+			errs = append(errs, ValidateNodeCertificate(ctx, v, n.Tag, n.CertSecret, urlHosts(n.PublicUrl, n.TcpUrl))...)
+		}
 	}
 
 	if len(errs) > 0 {
@@ -89,10 +124,6 @@ func (v *nodeValidator) ValidateCreate(ctx context.Context, c ClusterAdapter) er
 	return nil
 }
 
-func (v *nodeValidator) ValidateUpdate(ctx context.Context, _, newC ClusterAdapter) error {
-	return v.ValidateCreate(ctx, newC)
-}
-
 func ValidateNodesNotEmpty(tags []string) []string {
 	if len(tags) == 0 {
 		return []string{"spec.nodes must contain at least one node"}
@@ -242,6 +273,31 @@ func ValidateNodeCertSecret(ctx context.Context, v *nodeValidator, mode, tag, se
 	return errs
 }
 
+// ValidateNodeCertificate decodes the node's .pfx (already checked by ValidateNodeCertSecret)
+// and validates it against the node's own hosts.
+func ValidateNodeCertificate(ctx context.Context, v *nodeValidator, tag, secretName string, hosts []string) []string {
+	if secretName == "" {
+		return nil
+	}
+	label := fmt.Sprintf("spec.nodes[tag=%s].certsSecretRef", tag)
+
+	secret, err := v.getSecret(ctx, secretName)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", label, err)}
+	}
+
+	key := pfxKey(secret.Data)
+	if key == "" {
+		return nil // reported by ValidateNodeCertSecret
+	}
+	// server certificate secrets carry no password key
+	cert, err := certutil.ParsePFXCertificate(secret.Data[key], "")
+	if err != nil {
+		return []string{fmt.Sprintf("%s: file '%s': %v", label, key, err)}
+	}
+	return ValidateServerCertificate(label, cert, hosts, time.Now())
+}
+
 func extractPort(raw string) string {
 	u, err := url.Parse(raw)
 	if err != nil {
@@ -32,6 +32,11 @@ type Validator interface {
 	ValidateUpdate(ctx context.Context, oldCluster, newCluster ClusterAdapter) error
 }
 
+// DeleteValidator is implemented by validators that also want a say on deletes.
+type DeleteValidator interface {
+	ValidateDelete(ctx context.Context, cluster ClusterAdapter) error
+}
+
 var validators []Validator
 
 func Register(v Validator) {
@@ -57,3 +62,17 @@ func RunUpdate(ctx context.Context, oldCluster, newCluster ClusterAdapter) error
 	}
 	return errors.NewAggregate(errs)
 }
+
+func RunDelete(ctx context.Context, cluster ClusterAdapter) error {
+	var errs []error
+	for _, v := range validators {
+		dv, ok := v.(DeleteValidator)
+		if !ok {
+			continue
+		}
+		if err := dv.ValidateDelete(ctx, cluster); err != nil {
+			errs = append(errs, fmt.Errorf("\n[%s] %w", v.Name(), err))
+		}
+	}
+	return errors.NewAggregate(errs)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	testutil "ravendb-operator/test/utils"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCertificates_C1_StatusListsDecodedCertificates_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "certs-c1-status",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionCertificatesExpiring, metav1.ConditionFalse, timeout, 2*time.Second)
+
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+
+	seen := map[string]ravendbv1.CertificateStatus{}
+	for _, c := range cur.Status.Certificates {
+		seen[c.SecretName+"/"+c.Key] = c
+	}
+	for _, want := range []string{testutil.SecretClientPFX + "/client.pfx", testutil.SecretNodeAPFX + "/server.pfx"} {
+		c, ok := seen[want]
+		require.True(t, ok, "missing certificate %s in status", want)
+		require.Empty(t, c.Error)
+		require.NotNil(t, c.NotAfter)
+		require.NotEmpty(t, c.Subject)
+	}
+}
+
+func TestCertificates_C2_WarningWindowCoversExpiry_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	tenYears := int32(3650)
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "certs-c2-expiring",
+		Namespace: testutil.DefaultNS,
+		Modify: func(spec *ravendbv1.RavenDBClusterSpec) {
+			spec.Health = &ravendbv1.HealthSpec{CertificateExpiryWarningDays: &tenYears}
+		},
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionCertificatesExpiring, metav1.ConditionTrue, timeout, 2*time.Second)
+
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+	cond, ok := testutil.GetCondition(cur, ravendbv1.ConditionCertificatesExpiring)
+	require.True(t, ok)
+	require.Equal(t, string(ravendbv1.ReasonCertificateExpiring), cond.Reason)
+	require.Contains(t, cond.Message, testutil.SecretClientPFX+"/client.pfx")
+}
@@ -72,3 +72,29 @@ func TestLicense_L2_DeletedAfterCreate_E2E(t *testing.T) {
 	require.Contains(t, cond.Message, "missing license secret:")
 
 }
+
+func TestLicense_L3_RecreatedSecretReevaluates_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "license-l3-recreated",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionLicensesValid, metav1.ConditionTrue, timeout, 2*time.Second)
+
+	secKey := testutil.Key(key.Namespace, "ravendb-license")
+	orig := &corev1.Secret{}
+	require.NoError(t, cli.Get(context.Background(), secKey, orig))
+
+	require.NoError(t, cli.Delete(context.Background(), orig))
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionLicensesValid, metav1.ConditionFalse, timeout, 2*time.Second)
+
+	// the secret is user-created (not owned), so only the referenced-secret watch brings us back
+	require.NoError(t, cli.Create(context.Background(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: secKey.Name, Namespace: secKey.Namespace},
+		Data:       orig.Data,
+	}))
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionLicensesValid, metav1.ConditionTrue, timeout, 2*time.Second)
+}
@@ -69,3 +69,31 @@ func TestNodes_N2_PodPending_E2E(t *testing.T) {
 	require.Contains(t, cond.Message, "pods pending:")
 
 }
+
+func TestNodes_N3_ClusterFormed_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "nodes-n3-formed",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionClusterFormed, metav1.ConditionTrue, timeout, 2*time.Second)
+
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+
+	leaders := 0
+	for _, n := range cur.Status.Nodes {
+		require.NotEmpty(t, n.Role, "node %s has no role", n.Tag)
+		require.NotEqual(t, ravendbv1.NodeRoleNotInCluster, n.Role)
+		require.NotNil(t, n.Connected)
+		require.True(t, *n.Connected)
+		require.NotEmpty(t, n.ServerVersion)
+		if n.Role == ravendbv1.NodeRoleLeader {
+			leaders++
+		}
+	}
+	require.Equal(t, 1, leaders)
+}
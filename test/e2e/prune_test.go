@@ -0,0 +1,58 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	testutil "ravendb-operator/test/utils"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPrune_G1_IngressRemovedWithExternalAccess_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "prune-g1-ingress",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	ingKey := testutil.Key(key.Namespace, "ravendb")
+	require.Eventually(t, func() bool {
+		return cli.Get(context.Background(), ingKey, &networkingv1.Ingress{}) == nil
+	}, timeout, 2*time.Second, "ingress was not created")
+
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+	orig := cur.DeepCopy()
+	cur.Spec.ExternalAccessConfiguration = nil
+	require.NoError(t, cli.Patch(context.Background(), cur, ctrlclient.MergeFrom(orig)))
+
+	require.Eventually(t, func() bool {
+		return cli.Get(context.Background(), ingKey, &networkingv1.Ingress{}) != nil
+	}, timeout, 2*time.Second, "ingress was not pruned")
+
+	fetch := func() (string, error) { return testutil.OperatorEventsTSVAll(t.Context()) }
+	testutil.RequireContainsAllEventually(t, fetch, []string{"ResourcePruned", "Ingress " + key.Namespace + "/ravendb"}, timeout)
+}
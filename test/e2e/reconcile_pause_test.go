@@ -0,0 +1,82 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	ravendbv1 "ravendb-operator/api/v1"
+	"ravendb-operator/pkg/common"
+	testutil "ravendb-operator/test/utils"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func setReconcilePaused(t *testing.T, cli ctrlclient.Client, key ctrlclient.ObjectKey, paused bool) {
+	t.Helper()
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+	orig := cur.DeepCopy()
+	if cur.Annotations == nil {
+		cur.Annotations = map[string]string{}
+	}
+	if paused {
+		cur.Annotations[common.ReconcilePausedAnnotation] = "true"
+	} else {
+		delete(cur.Annotations, common.ReconcilePausedAnnotation)
+	}
+	require.NoError(t, cli.Patch(context.Background(), cur, ctrlclient.MergeFrom(orig)))
+}
+
+func TestReconcile_P1_PausedSkipsActors_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "reconcile-p1-paused",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionReconcilePaused, metav1.ConditionFalse, timeout, 2*time.Second)
+
+	setReconcilePaused(t, cli, key, true)
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionReconcilePaused, metav1.ConditionTrue, timeout, 2*time.Second)
+
+	cur := &ravendbv1.RavenDBCluster{}
+	require.NoError(t, cli.Get(context.Background(), key, cur))
+	cond, ok := testutil.GetCondition(cur, ravendbv1.ConditionReconcilePaused)
+	require.True(t, ok)
+	require.Equal(t, string(ravendbv1.ReasonReconcilePaused), cond.Reason)
+
+	// the ingress is re-applied on every reconcile, so a deleted one must stay gone while paused
+	ingKey := testutil.Key(key.Namespace, "ravendb")
+	require.NoError(t, cli.Delete(context.Background(), &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: ingKey.Name, Namespace: ingKey.Namespace}}))
+	require.Never(t, func() bool {
+		return cli.Get(context.Background(), ingKey, &networkingv1.Ingress{}) == nil
+	}, 30*time.Second, 2*time.Second)
+
+	setReconcilePaused(t, cli, key, false)
+	testutil.WaitCondition(t, cli, key, ravendbv1.ConditionReconcilePaused, metav1.ConditionFalse, timeout, 2*time.Second)
+	require.Eventually(t, func() bool {
+		return cli.Get(context.Background(), ingKey, &networkingv1.Ingress{}) == nil
+	}, timeout, 2*time.Second)
+}
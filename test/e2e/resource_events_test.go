@@ -0,0 +1,50 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	testutil "ravendb-operator/test/utils"
+
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestResources_R1_UpdatedEventOnlyOnChange_E2E(t *testing.T) {
+	testutil.RecreateTestEnv(t, rbacPath)
+
+	cli, key := testutil.CreateCluster(t, testutil.BaseClusterLE, testutil.ClusterCase{
+		Name:      "resources-r1-updated",
+		Namespace: testutil.DefaultNS,
+	})
+	testutil.RegisterClusterCleanup(t, cli, key, timeout)
+
+	fetch := func() (string, error) { return testutil.OperatorEventsTSVAll(t.Context()) }
+	testutil.RequireContainsAllEventually(t, fetch, []string{"ResourceUpdated", "resources of node a were created or updated"}, timeout)
+
+	// deleting the ingress is a real change: the ingress actor recreates it and reports it
+	ingKey := testutil.Key(key.Namespace, "ravendb")
+	require.NoError(t, cli.Delete(context.Background(), &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: ingKey.Name, Namespace: ingKey.Namespace}}))
+	require.Eventually(t, func() bool {
+		return cli.Get(context.Background(), ingKey, &networkingv1.Ingress{}) == nil
+	}, timeout, 2*time.Second)
+	testutil.RequireContainsAllEventually(t, fetch, []string{"cluster-level resources were created or updated"}, timeout)
+}